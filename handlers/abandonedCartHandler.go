@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"go.uber.org/zap"
+)
+
+type AbandonedCartHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewAbandonedCartHandler(service service.MainService, log *zap.Logger) AbandonedCartHandler {
+	return AbandonedCartHandler{Service: service, Logger: log}
+}
+
+// GetAbandonedCartStatsHandler reports the reminders of the last days days
+// and how many carts they brought back.
+func (h *AbandonedCartHandler) GetAbandonedCartStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "AbandonedCart"), zap.String("function", "GetAbandonedCartStatsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	stats, err := h.Service.AbandonedCartService.GetStats(days)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "AbandonedCart"), zap.String("function", "GetAbandonedCartStatsHandler"))
+		if errors.Is(err, service.ErrAbandonedCartInvalid) {
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get abandoned cart stats")
+		return
+	}
+	JsonResponse.SendSuccess(w, stats, "Abandoned cart stats successfully retrieved")
+}
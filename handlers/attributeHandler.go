@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type AttributeHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewAttributeHandler(service service.MainService, log *zap.Logger) AttributeHandler {
+	return AttributeHandler{Service: service, Logger: log}
+}
+
+func (h *AttributeHandler) GetAttributesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	categoryID, _ := strconv.Atoi(r.URL.Query().Get("categoryId"))
+	attributes, err := h.Service.AttributeService.GetAttributes(categoryID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "GetAttributesHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get attributes")
+		return
+	}
+	JsonResponse.SendSuccess(w, attributes, "Attributes successfully retrieved")
+}
+
+func (h *AttributeHandler) CreateAttributeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var attributeInput model.AttributeDTO
+	err := json.NewDecoder(r.Body).Decode(&attributeInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "CreateAttributeHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attribute, err := h.Service.AttributeService.CreateAttribute(attributeInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "CreateAttributeHandler"))
+		sendAttributeError(w, err, "Failed to create attribute")
+		return
+	}
+	JsonResponse.SendCreated(w, attribute, "Attribute created successfully")
+}
+
+func (h *AttributeHandler) UpdateAttributeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	attributeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "UpdateAttributeHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid attribute ID")
+		return
+	}
+
+	var attributeInput model.AttributeDTO
+	err = json.NewDecoder(r.Body).Decode(&attributeInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "UpdateAttributeHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	attribute, err := h.Service.AttributeService.UpdateAttribute(attributeID, attributeInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "UpdateAttributeHandler"))
+		sendAttributeError(w, err, "Failed to update attribute")
+		return
+	}
+	JsonResponse.SendSuccess(w, attribute, "Attribute updated successfully")
+}
+
+func (h *AttributeHandler) DeleteAttributeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	attributeID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "DeleteAttributeHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid attribute ID")
+		return
+	}
+
+	err = h.Service.AttributeService.DeleteAttribute(attributeID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Attribute"), zap.String("function", "DeleteAttributeHandler"))
+		sendAttributeError(w, err, "Failed to delete attribute")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Attribute deleted successfully")
+}
+
+func sendAttributeError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrAttributeNotFound), errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrCategoryNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrAttributeInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type BrandHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewBrandHandler(service service.MainService, log *zap.Logger) BrandHandler {
+	return BrandHandler{Service: service, Logger: log}
+}
+
+func (h *BrandHandler) GetBrandsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "GetBrandsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	brands, pagination, err := h.Service.BrandService.GetBrands(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "GetBrandsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get brands")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, brands, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Brands successfully retrieved")
+}
+
+// GetBrandProductsHandler takes the same filters as the product listing.
+func (h *BrandHandler) GetBrandProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "GetBrandProductsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	var productFilter model.ProductDTO
+	productFilter.Name = r.URL.Query().Get("name")
+	productFilter.CategoryID, _ = strconv.Atoi(r.URL.Query().Get("categoryId"))
+	productFilter.Attributes = attributeFilters(r)
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	_, products, pagination, err := h.Service.BrandService.GetBrandProducts(chi.URLParam(r, "slug"), productFilter, paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "GetBrandProductsHandler"))
+		sendBrandError(w, err, "Failed to get brand products")
+		return
+	}
+	h.Service.CurrencyService.ConvertProducts(products, currency)
+	h.Service.TranslationService.TranslateProductList(requestLocale(w, r, h.Service), products)
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, products, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Brand products successfully retrieved")
+}
+
+func (h *BrandHandler) CreateBrandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var brandInput model.BrandDTO
+	err := json.NewDecoder(r.Body).Decode(&brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "CreateBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	brand, err := h.Service.BrandService.CreateBrand(brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "CreateBrandHandler"))
+		sendBrandError(w, err, "Failed to create brand")
+		return
+	}
+	JsonResponse.SendCreated(w, brand, "Brand created successfully")
+}
+
+func (h *BrandHandler) UpdateBrandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	brandID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "UpdateBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid brand ID")
+		return
+	}
+
+	var brandInput model.BrandDTO
+	err = json.NewDecoder(r.Body).Decode(&brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "UpdateBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	brand, err := h.Service.BrandService.UpdateBrand(brandID, brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "UpdateBrandHandler"))
+		sendBrandError(w, err, "Failed to update brand")
+		return
+	}
+	JsonResponse.SendSuccess(w, brand, "Brand updated successfully")
+}
+
+func (h *BrandHandler) DeleteBrandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	brandID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "DeleteBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid brand ID")
+		return
+	}
+
+	err = h.Service.BrandService.DeleteBrand(brandID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "DeleteBrandHandler"))
+		sendBrandError(w, err, "Failed to delete brand")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Brand deleted successfully")
+}
+
+func (h *BrandHandler) SetProductBrandHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "SetProductBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var brandInput model.ProductBrandDTO
+	err = json.NewDecoder(r.Body).Decode(&brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "SetProductBrandHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.BrandService.SetProductBrand(productID, brandInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Brand"), zap.String("function", "SetProductBrandHandler"))
+		sendBrandError(w, err, "Failed to set product brand")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Product brand updated successfully")
+}
+
+func sendBrandError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrBrandNotFound), errors.Is(err, service.ErrProductNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrBrandInvalid), errors.Is(err, service.ErrAttributeInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
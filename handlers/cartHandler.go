@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -112,6 +113,85 @@ func (h *CartHandler) UpdateCartItemHandler(w http.ResponseWriter, r *http.Reque
 	JsonResponse.SendSuccess(w, nil, "Cart item updated successfully")
 }
 
+// UpdateCartItemVariantsHandler changes the variant options of a cart line.
+func (h *CartHandler) UpdateCartItemVariantsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PATCH methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	cartItemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", "UpdateCartItemVariantsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid cart item ID")
+		return
+	}
+
+	var variantInput model.CartItemVariantsDTO
+	err = json.NewDecoder(r.Body).Decode(&variantInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", "UpdateCartItemVariantsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.CartService.UpdateItemVariants(user.ID, cartItemID, variantInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", "UpdateCartItemVariantsHandler"))
+		sendCartError(w, err, "Failed to update cart item")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Cart item updated successfully")
+}
+
+// SaveForLaterHandler moves a cart line to the saved for later list.
+func (h *CartHandler) SaveForLaterHandler(w http.ResponseWriter, r *http.Request) {
+	h.moveCartItem(w, r, "SaveForLaterHandler", h.Service.CartService.SaveForLater, "Cart item saved for later")
+}
+
+// MoveToCartHandler moves a saved line back into the cart.
+func (h *CartHandler) MoveToCartHandler(w http.ResponseWriter, r *http.Request) {
+	h.moveCartItem(w, r, "MoveToCartHandler", h.Service.CartService.MoveToCart, "Cart item moved to cart")
+}
+
+func (h *CartHandler) moveCartItem(w http.ResponseWriter, r *http.Request, function string, move func(string, int) error, message string) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	cartItemID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid cart item ID")
+		return
+	}
+
+	err = move(user.ID, cartItemID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", function))
+		sendCartError(w, err, "Failed to move cart item")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, message)
+}
+
 func (h *CartHandler) DeleteItemHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodDelete {
 		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
@@ -137,6 +217,37 @@ func (h *CartHandler) DeleteItemHandler(w http.ResponseWriter, r *http.Request)
 	JsonResponse.SendSuccess(w, nil, "Item deleted successfully")
 }
 
+// ValidateCartHandler reprices the cart with current data and flags the
+// lines that changed or can no longer be ordered.
+func (h *CartHandler) ValidateCartHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+
+	validation, err := h.Service.CartService.ValidateCart(user.ID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Cart"), zap.String("function", "ValidateCartHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to validate cart")
+		return
+	}
+	h.Service.CurrencyService.ConvertCartValidation(&validation, currency)
+	h.Service.TranslationService.TranslateCart(requestLocale(w, r, h.Service), &validation.Cart)
+	JsonResponse.SendSuccess(w, validation, "Cart validated successfully")
+}
+
 func (h *CartHandler) GetUserCart(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
@@ -158,11 +269,28 @@ func (h *CartHandler) GetUserCart(w http.ResponseWriter, r *http.Request) {
 		return
 
 	}
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
 	cart, err := h.Service.CartService.GetCartByUserID(user.ID)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "GetUserCart"))
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to retrieve user's cart")
 		return
 	}
+	h.Service.CurrencyService.ConvertCart(&cart, currency)
+	h.Service.TranslationService.TranslateCart(requestLocale(w, r, h.Service), &cart)
 	JsonResponse.SendSuccess(w, cart, "User's cart retrieved successfully")
 }
+
+func sendCartError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCartItemNotFound), errors.Is(err, service.ErrProductNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrCartVariantInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
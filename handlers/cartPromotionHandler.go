@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type CartPromotionHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewCartPromotionHandler(service service.MainService, log *zap.Logger) CartPromotionHandler {
+	return CartPromotionHandler{Service: service, Logger: log}
+}
+
+func (h *CartPromotionHandler) GetAllCartPromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	promotions, pagination, err := h.Service.CartPromotionService.GetAllCartPromotions(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "CartPromotion"), zap.String("function", "GetAllCartPromotionsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get cart promotions")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, promotions, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Cart promotions successfully retrieved")
+}
+
+func (h *CartPromotionHandler) CreateCartPromotionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var promotionInput model.CartPromotionDTO
+	err := json.NewDecoder(r.Body).Decode(&promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "CartPromotion"), zap.String("function", "CreateCartPromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	promotion, err := h.Service.CartPromotionService.CreateCartPromotion(promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "CartPromotion"), zap.String("function", "CreateCartPromotionHandler"))
+		h.sendCartPromotionError(w, err, "Failed to create cart promotion")
+		return
+	}
+	JsonResponse.SendCreated(w, promotion, "Cart promotion created successfully")
+}
+
+func (h *CartPromotionHandler) DeleteCartPromotionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	promotionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "CartPromotion"), zap.String("function", "DeleteCartPromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid cart promotion ID")
+		return
+	}
+
+	err = h.Service.CartPromotionService.DeleteCartPromotion(promotionID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "CartPromotion"), zap.String("function", "DeleteCartPromotionHandler"))
+		h.sendCartPromotionError(w, err, "Failed to delete cart promotion")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Cart promotion deleted successfully")
+}
+
+func (h *CartPromotionHandler) sendCartPromotionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCartPromotionNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrCartPromotionInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
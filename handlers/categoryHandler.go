@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -8,6 +9,7 @@ import (
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
 	// "github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
 
@@ -46,8 +48,40 @@ func (h *CategoryHandler) GetAllCategoryHandler(w http.ResponseWriter, r *http.R
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get all categories")
 		return
 	}
+	h.Service.TranslationService.TranslateCategories(requestLocale(w, r, h.Service), categories)
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
 	}
 	JsonResponse.SendPaginatedResponse(w, categories, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Categories successfully retrieved")
 }
+
+func (h *CategoryHandler) SaveCategoryTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	categoryId, _ := strconv.Atoi(id)
+	if categoryId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid category id %s", id))
+		return
+	}
+
+	var translationInput model.ContentTranslation
+	err := json.NewDecoder(r.Body).Decode(&translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Category"), zap.String("function", "SaveCategoryTranslationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	translation, err := h.Service.TranslationService.SaveCategoryTranslation(categoryId, chi.URLParam(r, "locale"), translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Category"), zap.String("function", "SaveCategoryTranslationHandler"))
+		sendTranslationError(w, err, "Failed to save category translation")
+		return
+	}
+	JsonResponse.SendSuccess(w, translation, "Category translation saved successfully")
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type CollectionHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewCollectionHandler(service service.MainService, log *zap.Logger) CollectionHandler {
+	return CollectionHandler{Service: service, Logger: log}
+}
+
+func (h *CollectionHandler) GetCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	collections, pagination, err := h.Service.CollectionService.GetCollections(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get collections")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, collections, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Collections successfully retrieved")
+}
+
+// GetCollectionProductsHandler takes the same filters as the product listing.
+func (h *CollectionHandler) GetCollectionProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionProductsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	var productFilter model.ProductDTO
+	productFilter.Name = r.URL.Query().Get("name")
+	productFilter.CategoryID, _ = strconv.Atoi(r.URL.Query().Get("categoryId"))
+	productFilter.BrandID, _ = strconv.Atoi(r.URL.Query().Get("brandId"))
+	productFilter.Attributes = attributeFilters(r)
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	_, products, pagination, err := h.Service.CollectionService.GetCollectionProducts(chi.URLParam(r, "slug"), productFilter, paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionProductsHandler"))
+		sendCollectionError(w, err, "Failed to get collection products")
+		return
+	}
+	h.Service.CurrencyService.ConvertProducts(products, currency)
+	h.Service.TranslationService.TranslateProductList(requestLocale(w, r, h.Service), products)
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, products, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Collection products successfully retrieved")
+}
+
+func (h *CollectionHandler) GetCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	collectionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid collection ID")
+		return
+	}
+
+	collection, err := h.Service.CollectionService.GetCollection(collectionID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "GetCollectionHandler"))
+		sendCollectionError(w, err, "Failed to get collection")
+		return
+	}
+	JsonResponse.SendSuccess(w, collection, "Collection successfully retrieved")
+}
+
+func (h *CollectionHandler) CreateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var collectionInput model.CollectionDTO
+	err := json.NewDecoder(r.Body).Decode(&collectionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "CreateCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, err := h.Service.CollectionService.CreateCollection(collectionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "CreateCollectionHandler"))
+		sendCollectionError(w, err, "Failed to create collection")
+		return
+	}
+	JsonResponse.SendCreated(w, collection, "Collection created successfully")
+}
+
+func (h *CollectionHandler) UpdateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	collectionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "UpdateCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid collection ID")
+		return
+	}
+
+	var collectionInput model.CollectionDTO
+	err = json.NewDecoder(r.Body).Decode(&collectionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "UpdateCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	collection, err := h.Service.CollectionService.UpdateCollection(collectionID, collectionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "UpdateCollectionHandler"))
+		sendCollectionError(w, err, "Failed to update collection")
+		return
+	}
+	JsonResponse.SendSuccess(w, collection, "Collection updated successfully")
+}
+
+func (h *CollectionHandler) DeleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	collectionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "DeleteCollectionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid collection ID")
+		return
+	}
+
+	err = h.Service.CollectionService.DeleteCollection(collectionID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "DeleteCollectionHandler"))
+		sendCollectionError(w, err, "Failed to delete collection")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Collection deleted successfully")
+}
+
+func (h *CollectionHandler) SetProductTagsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "SetProductTagsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var tagsInput []string
+	err = json.NewDecoder(r.Body).Decode(&tagsInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "SetProductTagsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	tags, err := h.Service.CollectionService.SetProductTags(productID, tagsInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Collection"), zap.String("function", "SetProductTagsHandler"))
+		sendCollectionError(w, err, "Failed to set product tags")
+		return
+	}
+	JsonResponse.SendSuccess(w, tags, "Product tags updated successfully")
+}
+
+func sendCollectionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCollectionNotFound), errors.Is(err, service.ErrProductNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrCollectionInvalid), errors.Is(err, service.ErrAttributeInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
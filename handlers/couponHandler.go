@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type CouponHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewCouponHandler(service service.MainService, log *zap.Logger) CouponHandler {
+	return CouponHandler{Service: service, Logger: log}
+}
+
+func (h *CouponHandler) ApplyCouponHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	var couponInput model.ApplyCouponDTO
+	err := json.NewDecoder(r.Body).Decode(&couponInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "ApplyCouponHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	coupon, err := h.Service.CouponService.ApplyCoupon(user.ID, couponInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "ApplyCouponHandler"))
+		JsonResponse.SendError(w, couponErrorStatus(err), couponErrorMessage(err, "Failed to apply coupon"))
+		return
+	}
+	if coupon == nil {
+		JsonResponse.SendSuccess(w, nil, "Coupon removed from cart")
+		return
+	}
+	JsonResponse.SendSuccess(w, coupon, "Coupon applied to cart")
+}
+
+func (h *CouponHandler) RemoveCouponHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	err := h.Service.CouponService.RemoveCoupon(user.ID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "RemoveCouponHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to remove coupon")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Coupon removed from cart")
+}
+
+func (h *CouponHandler) GetAllCouponsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	coupons, pagination, err := h.Service.CouponService.GetAllCoupons(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "GetAllCouponsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get coupons")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, coupons, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Coupons successfully retrieved")
+}
+
+func (h *CouponHandler) CreateCouponHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var couponInput model.CouponDTO
+	err := json.NewDecoder(r.Body).Decode(&couponInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "CreateCouponHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	coupon, err := h.Service.CouponService.CreateCoupon(couponInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "CreateCouponHandler"))
+		JsonResponse.SendError(w, couponErrorStatus(err), couponErrorMessage(err, "Failed to create coupon"))
+		return
+	}
+	JsonResponse.SendCreated(w, coupon, "Coupon created successfully")
+}
+
+func (h *CouponHandler) DeleteCouponHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	couponID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "DeleteCouponHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid coupon ID")
+		return
+	}
+
+	err = h.Service.CouponService.DeleteCoupon(couponID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Coupon"), zap.String("function", "DeleteCouponHandler"))
+		JsonResponse.SendError(w, couponErrorStatus(err), couponErrorMessage(err, "Failed to delete coupon"))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Coupon deleted successfully")
+}
+
+func couponErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrCouponNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCouponUsageLimit), errors.Is(err, service.ErrCouponUserLimit):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrCouponInvalid), errors.Is(err, service.ErrCouponExpired), errors.Is(err, service.ErrCouponUnavailable),
+		errors.Is(err, service.ErrCouponMinSpend), errors.Is(err, service.ErrCouponNotEligible), errors.Is(err, service.ErrCouponEmptyCart):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func couponErrorMessage(err error, fallback string) string {
+	if couponErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
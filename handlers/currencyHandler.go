@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// CurrencyHeader selects the currency prices are shown in. The currency
+// query parameter takes precedence over it.
+const CurrencyHeader = "X-Currency"
+
+type CurrencyHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewCurrencyHandler(service service.MainService, log *zap.Logger) CurrencyHandler {
+	return CurrencyHandler{Service: service, Logger: log}
+}
+
+// requestCurrency resolves the currency asked for by the request, or the
+// base currency. It writes the error response itself and returns false when
+// the currency is unknown.
+func requestCurrency(w http.ResponseWriter, r *http.Request, mainService service.MainService, logger *zap.Logger) (model.Currency, bool) {
+	code := r.URL.Query().Get("currency")
+	if code == "" {
+		code = r.Header.Get(CurrencyHeader)
+	}
+	currency, err := mainService.CurrencyService.Resolve(code)
+	if err != nil {
+		logger.Error(err.Error(), zap.String("method", r.Method), zap.String("path", r.URL.Path))
+		if errors.Is(err, service.ErrCurrencyNotFound) {
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+			return currency, false
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get currency")
+		return currency, false
+	}
+	return currency, true
+}
+
+func (h *CurrencyHandler) GetCurrenciesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	currencies, err := h.Service.CurrencyService.GetCurrencies()
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "GetCurrenciesHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get currencies")
+		return
+	}
+	JsonResponse.SendSuccess(w, currencies, "Currencies successfully retrieved")
+}
+
+func (h *CurrencyHandler) SaveCurrencyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	var currencyInput model.CurrencyDTO
+	err := json.NewDecoder(r.Body).Decode(&currencyInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "SaveCurrencyHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	currency, err := h.Service.CurrencyService.SaveCurrency(chi.URLParam(r, "code"), currencyInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "SaveCurrencyHandler"))
+		h.sendCurrencyError(w, err, "Failed to save currency")
+		return
+	}
+	JsonResponse.SendSuccess(w, currency, "Currency saved successfully")
+}
+
+// ImportCurrenciesHandler takes a CSV file, either as the raw request body
+// or as the "file" field of a multipart form.
+func (h *CurrencyHandler) ImportCurrenciesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var file io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		formFile, _, err := r.FormFile("file")
+		if err != nil {
+			h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "ImportCurrenciesHandler"))
+			JsonResponse.SendError(w, http.StatusBadRequest, "Missing file")
+			return
+		}
+		defer formFile.Close()
+		file = formFile
+	}
+
+	imported, err := h.Service.CurrencyService.ImportCurrencies(file)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "ImportCurrenciesHandler"))
+		h.sendCurrencyError(w, err, "Failed to import currencies")
+		return
+	}
+	JsonResponse.SendSuccess(w, map[string]int{"imported": imported}, "Currencies imported successfully")
+}
+
+func (h *CurrencyHandler) DeleteCurrencyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	err := h.Service.CurrencyService.DeleteCurrency(chi.URLParam(r, "code"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Currency"), zap.String("function", "DeleteCurrencyHandler"))
+		h.sendCurrencyError(w, err, "Failed to delete currency")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Currency deleted successfully")
+}
+
+func (h *CurrencyHandler) sendCurrencyError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCurrencyNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrCurrencyInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type FlashSaleHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewFlashSaleHandler(service service.MainService, log *zap.Logger) FlashSaleHandler {
+	return FlashSaleHandler{Service: service, Logger: log}
+}
+
+func (h *FlashSaleHandler) GetFlashSalesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	flashSales, err := h.Service.FlashSaleService.GetFlashSales()
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "FlashSale"), zap.String("function", "GetFlashSalesHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get flash sales")
+		return
+	}
+	JsonResponse.SendSuccess(w, flashSales, "Flash sales successfully retrieved")
+}
+
+func (h *FlashSaleHandler) CreateFlashSaleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var flashSaleInput model.FlashSaleDTO
+	err := json.NewDecoder(r.Body).Decode(&flashSaleInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "FlashSale"), zap.String("function", "CreateFlashSaleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	flashSale, err := h.Service.FlashSaleService.CreateFlashSale(flashSaleInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "FlashSale"), zap.String("function", "CreateFlashSaleHandler"))
+		h.sendFlashSaleError(w, err, "Failed to create flash sale")
+		return
+	}
+	JsonResponse.SendCreated(w, flashSale, "Flash sale created successfully")
+}
+
+func (h *FlashSaleHandler) DeleteFlashSaleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	flashSaleID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "FlashSale"), zap.String("function", "DeleteFlashSaleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid flash sale ID")
+		return
+	}
+
+	err = h.Service.FlashSaleService.DeleteFlashSale(flashSaleID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "FlashSale"), zap.String("function", "DeleteFlashSaleHandler"))
+		h.sendFlashSaleError(w, err, "Failed to delete flash sale")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Flash sale deleted successfully")
+}
+
+func (h *FlashSaleHandler) sendFlashSaleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrFlashSaleNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrFlashSaleInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
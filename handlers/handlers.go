@@ -15,6 +15,20 @@ type Mainhandler struct {
 	WishlistHandler       WishlistHandler
 	CartHandler           CartHandler
 	OrderHandler          OrderHandler
+	ReviewHandler         ReviewHandler
+	PromotionHandler      PromotionHandler
+	CouponHandler         CouponHandler
+	FlashSaleHandler      FlashSaleHandler
+	CartPromotionHandler  CartPromotionHandler
+	ProductViewHandler    ProductViewHandler
+	CurrencyHandler       CurrencyHandler
+	AttributeHandler      AttributeHandler
+	BrandHandler          BrandHandler
+	CollectionHandler     CollectionHandler
+	SEOHandler            SEOHandler
+	QuestionHandler       QuestionHandler
+	LifecycleHandler      LifecycleHandler
+	AbandonedCartHandler  AbandonedCartHandler
 }
 
 func NewMainHandler(service service.MainService, log *zap.Logger, config util.Configuration) Mainhandler {
@@ -27,5 +41,19 @@ func NewMainHandler(service service.MainService, log *zap.Logger, config util.Co
 		WishlistHandler:       NewWishlistHandler(service, log),
 		CartHandler:           NewCartHandler(service, log),
 		OrderHandler:          NewOrderHandler(service, log),
+		ReviewHandler:         NewReviewHandler(service, log),
+		PromotionHandler:      NewPromotionHandler(service, log),
+		CouponHandler:         NewCouponHandler(service, log),
+		FlashSaleHandler:      NewFlashSaleHandler(service, log),
+		CartPromotionHandler:  NewCartPromotionHandler(service, log),
+		ProductViewHandler:    NewProductViewHandler(service, log),
+		CurrencyHandler:       NewCurrencyHandler(service, log),
+		AttributeHandler:      NewAttributeHandler(service, log),
+		BrandHandler:          NewBrandHandler(service, log),
+		CollectionHandler:     NewCollectionHandler(service, log),
+		SEOHandler:            NewSEOHandler(service, log),
+		QuestionHandler:       NewQuestionHandler(service, log),
+		LifecycleHandler:      NewLifecycleHandler(service, log),
+		AbandonedCartHandler:  NewAbandonedCartHandler(service, log),
 	}
 }
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type LifecycleHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewLifecycleHandler(service service.MainService, log *zap.Logger) LifecycleHandler {
+	return LifecycleHandler{Service: service, Logger: log}
+}
+
+// GetProductLifecyclesHandler lists products by publish status, e.g. the
+// drafts being prepared or the upcoming scheduled releases.
+func (h *LifecycleHandler) GetProductLifecyclesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "GetProductLifecyclesHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	lifecycles, pagination, err := h.Service.LifecycleService.GetProductLifecycles(r.URL.Query().Get("status"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "GetProductLifecyclesHandler"))
+		sendLifecycleError(w, err, "Failed to get products")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, lifecycles, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Products successfully retrieved")
+}
+
+func (h *LifecycleHandler) GetProductLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "GetProductLifecycleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "GetProductLifecycleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	lifecycle, err := h.Service.LifecycleService.GetProductLifecycle(productID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "GetProductLifecycleHandler"))
+		sendLifecycleError(w, err, "Failed to get product lifecycle")
+		return
+	}
+	JsonResponse.SendSuccess(w, lifecycle, "Product lifecycle successfully retrieved")
+}
+
+func (h *LifecycleHandler) UpdateProductLifecycleHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "UpdateProductLifecycleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var lifecycleInput model.LifecycleDTO
+	err = json.NewDecoder(r.Body).Decode(&lifecycleInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "UpdateProductLifecycleHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	lifecycle, err := h.Service.LifecycleService.UpdateLifecycle(productID, lifecycleInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Lifecycle"), zap.String("function", "UpdateProductLifecycleHandler"))
+		sendLifecycleError(w, err, "Failed to update product lifecycle")
+		return
+	}
+	JsonResponse.SendSuccess(w, lifecycle, "Product lifecycle updated successfully")
+}
+
+func sendLifecycleError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrLifecycleInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
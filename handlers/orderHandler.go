@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -50,10 +51,18 @@ func (h *OrderHandler) CreateOrderHanlder(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	// The currency can also be chosen the same way as for prices.
+	if orderInput.Currency == "" {
+		orderInput.Currency = r.URL.Query().Get("currency")
+	}
+	if orderInput.Currency == "" {
+		orderInput.Currency = r.Header.Get(CurrencyHeader)
+	}
+
 	err = h.Service.OrderService.CreateOrder(user.ID, orderInput)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Order"), zap.String("function", "CreateOrderHanlder"))
-		JsonResponse.SendError(w, http.StatusInternalServerError, "Order creation failed due internal error")
+		JsonResponse.SendError(w, orderErrorStatus(err), orderErrorMessage(err, "Order creation failed due internal error"))
 		return
 	}
 	JsonResponse.SendCreated(w, nil, "Order created successfully")
@@ -121,3 +130,20 @@ func (h *OrderHandler) GetOrderDetailsHandler(w http.ResponseWriter, r *http.Req
 	}
 	JsonResponse.SendSuccess(w, order, "Order details successfully retrieved")
 }
+
+func orderErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrFlashSaleSoldOut), errors.Is(err, service.ErrFlashSaleCapacity), errors.Is(err, service.ErrCartUnavailable):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrFlashSaleEnded), errors.Is(err, service.ErrCurrencyNotFound):
+		return http.StatusUnprocessableEntity
+	}
+	return couponErrorStatus(err)
+}
+
+func orderErrorMessage(err error, fallback string) string {
+	if orderErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
 	// "github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
@@ -24,6 +30,18 @@ func NewProductHandler(service service.MainService, log *zap.Logger) ProductHand
 
 var TotalPage = 1
 
+// LocaleQuery selects the content locale. It takes precedence over the
+// Accept-Language header.
+const LocaleQuery = "locale"
+
+// requestLocale negotiates the content locale of the request and reports
+// it in the Content-Language header.
+func requestLocale(w http.ResponseWriter, r *http.Request, mainService service.MainService) string {
+	locale := mainService.TranslationService.Negotiate(r.URL.Query().Get(LocaleQuery), r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", locale)
+	return locale
+}
+
 func (h *ProductHandler) GetAllProductHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
@@ -54,18 +72,84 @@ func (h *ProductHandler) GetAllProductHandler(w http.ResponseWriter, r *http.Req
 		productFilter.CategoryID, _ = strconv.Atoi(categoryID)
 	}
 
+	brandID := r.URL.Query().Get("brandId")
+	if brandID != "" {
+		productFilter.BrandID, _ = strconv.Atoi(brandID)
+	}
+	productFilter.Tag = strings.ToLower(strings.TrimSpace(r.URL.Query().Get("tag")))
+	productFilter.Attributes = attributeFilters(r)
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
 	products, pagination, err := h.Service.ProductService.GetAllProduct(productFilter, paginationInput)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetAllProductHandler"))
+		if errors.Is(err, service.ErrAttributeInvalid) {
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get products")
 		return
 	}
+	h.Service.CurrencyService.ConvertProducts(products, currency)
+	h.Service.TranslationService.TranslateProductList(requestLocale(w, r, h.Service), products)
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
 	}
 	JsonResponse.SendPaginatedResponse(w, products, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Products successfully retrieved")
 }
 
+// attributeFilters reads the attr[code]=value,value query parameters of the
+// product listing.
+func attributeFilters(r *http.Request) []model.AttributeFilter {
+	var filters []model.AttributeFilter
+	for key, values := range r.URL.Query() {
+		code, ok := strings.CutPrefix(key, "attr[")
+		if !ok || !strings.HasSuffix(code, "]") {
+			continue
+		}
+		filter := model.AttributeFilter{Code: strings.TrimSuffix(code, "]")}
+		for _, value := range values {
+			for _, part := range strings.Split(value, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					filter.Values = append(filter.Values, part)
+				}
+			}
+		}
+		if len(filter.Values) > 0 {
+			filters = append(filters, filter)
+		}
+	}
+	sort.Slice(filters, func(i, j int) bool { return filters[i].Code < filters[j].Code })
+	return filters
+}
+
+// GetProductFacetsHandler takes the same name, categoryId and attr filters
+// as the product listing.
+func (h *ProductHandler) GetProductFacetsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductFacetsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var productFilter model.ProductDTO
+	productFilter.Name = r.URL.Query().Get("name")
+	productFilter.CategoryID, _ = strconv.Atoi(r.URL.Query().Get("categoryId"))
+	productFilter.Attributes = attributeFilters(r)
+
+	facets, err := h.Service.AttributeService.GetFacets(productFilter)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductFacetsHandler"))
+		sendAttributeError(w, err, "Failed to get product facets")
+		return
+	}
+	JsonResponse.SendSuccess(w, facets, "Product facets successfully retrieved")
+}
+
 func (h *ProductHandler) GetProductByIdHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
@@ -74,24 +158,122 @@ func (h *ProductHandler) GetProductByIdHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	var productId int
 	id := chi.URLParam(r, "id")
-	if id != "" {
-		productId, _ := strconv.Atoi(id)
-		if productId <= 0 {
-			errMessage := fmt.Sprintf("Invalid product id %s", id)
-			h.Logger.Error("Invalid requested product ID", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductByIdHandler"))
-			JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
-			return
-		}
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		errMessage := fmt.Sprintf("Invalid product id %s", id)
+		h.Logger.Error("Invalid requested product ID", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductByIdHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
 	}
 
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
 	product, err := h.Service.ProductService.GetProductByID(productId)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductByIdHandler"))
+		if errors.Is(err, service.ErrProductNotFound) {
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product")
+		return
+	}
+	h.sendProduct(w, r, product, currency, "GetProductByIdHandler")
+}
+
+// CompareProductsHandler compares the products listed in ids=1,2,3.
+func (h *ProductHandler) CompareProductsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "CompareProductsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var productIDs []int
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		productID, err := strconv.Atoi(id)
+		if err != nil || productID <= 0 {
+			h.Logger.Error("Invalid requested product ID", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "CompareProductsHandler"))
+			JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+			return
+		}
+		productIDs = append(productIDs, productID)
+	}
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	comparison, err := h.Service.ComparisonService.CompareProducts(productIDs, currency, requestLocale(w, r, h.Service))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "CompareProductsHandler"))
+		switch {
+		case errors.Is(err, service.ErrProductNotFound):
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrComparisonInvalid):
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to compare products")
+		}
+		return
+	}
+	JsonResponse.SendSuccess(w, comparison, "Products successfully compared")
+}
+
+// GetProductBySlugHandler permanently redirects a former slug to the
+// product's current one.
+func (h *ProductHandler) GetProductBySlugHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductBySlugHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	product, redirect, err := h.Service.SEOService.GetProductBySlug(chi.URLParam(r, "slug"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductBySlugHandler"))
+		if errors.Is(err, service.ErrProductNotFound) {
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product")
 		return
 	}
+	if redirect != "" {
+		target := "/api/products/slug/" + url.PathEscape(redirect)
+		if r.URL.RawQuery != "" {
+			target += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, target, http.StatusMovedPermanently)
+		return
+	}
+	h.sendProduct(w, r, product, currency, "GetProductBySlugHandler")
+}
+
+// sendProduct records the view and responds with the product detail.
+func (h *ProductHandler) sendProduct(w http.ResponseWriter, r *http.Request, product *model.Product, currency model.Currency, function string) {
+	// A failed view record must not fail the product page.
+	var userID string
+	if user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User); ok {
+		userID = user.ID
+	}
+	if err := h.Service.ProductViewService.RecordView(product.ID, userID, r.Header.Get(GuestTokenHeader)); err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", function))
+	}
+	h.Service.CurrencyService.ConvertProduct(product, currency)
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), product)
 
 	JsonResponse.SendSuccess(w, product, "Product successfully retrieved")
 }
@@ -114,15 +296,227 @@ func (h *ProductHandler) GetWeeklyPromotionsHandler(w http.ResponseWriter, r *ht
 		paginationInput.PerPage, _ = strconv.Atoi(perPage)
 	}
 
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
 	weeklyPromo, pagination, err := h.Service.ProductService.GetPromoWeekly(paginationInput)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetWeeklyPromotionsHandler"))
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get weekly promotions")
 		return
 	}
+	h.Service.CurrencyService.ConvertProducts(weeklyPromo, currency)
+	h.Service.TranslationService.TranslateProductList(requestLocale(w, r, h.Service), weeklyPromo)
 
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
 	}
 	JsonResponse.SendPaginatedResponse(w, weeklyPromo, pagination.Page, paginationInput.PerPage, pagination.CountData, TotalPage, "Weekly Promo successfully retrieved")
 }
+
+func (h *ProductHandler) GetBestSellersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetBestSellersHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+	window, _ := strconv.Atoi(r.URL.Query().Get("window"))
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	bestSellers, pagination, err := h.Service.BestSellerService.GetBestSellers(window, paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetBestSellersHandler"))
+		if errors.Is(err, service.ErrBestSellerWindow) {
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get best sellers")
+		return
+	}
+	products := make([]*model.Product, len(bestSellers))
+	for i := range bestSellers {
+		h.Service.CurrencyService.ConvertProduct(&bestSellers[i].Product, currency)
+		products[i] = &bestSellers[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), products...)
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, bestSellers, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Best sellers successfully retrieved")
+}
+
+func (h *ProductHandler) GetPriceHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	history, pagination, err := h.Service.PriceHistoryService.GetPriceHistory(productId, paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetPriceHistoryHandler"))
+		if errors.Is(err, service.ErrProductNotFound) {
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get price history")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, history, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Price history successfully retrieved")
+}
+
+func (h *ProductHandler) GetProductTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+
+	translations, err := h.Service.TranslationService.GetProductTranslations(productId)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "GetProductTranslationsHandler"))
+		sendTranslationError(w, err, "Failed to get product translations")
+		return
+	}
+	JsonResponse.SendSuccess(w, translations, "Product translations successfully retrieved")
+}
+
+func (h *ProductHandler) SaveProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+
+	var translationInput model.ProductTranslation
+	err := json.NewDecoder(r.Body).Decode(&translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "SaveProductTranslationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	translation, err := h.Service.TranslationService.SaveProductTranslation(productId, chi.URLParam(r, "locale"), translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "SaveProductTranslationHandler"))
+		sendTranslationError(w, err, "Failed to save product translation")
+		return
+	}
+	JsonResponse.SendSuccess(w, translation, "Product translation saved successfully")
+}
+
+func (h *ProductHandler) DeleteProductTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+
+	err := h.Service.TranslationService.DeleteProductTranslation(productId, chi.URLParam(r, "locale"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "DeleteProductTranslationHandler"))
+		sendTranslationError(w, err, "Failed to delete product translation")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Product translation deleted successfully")
+}
+
+func (h *ProductHandler) SetProductAttributesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productId, _ := strconv.Atoi(id)
+	if productId <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+
+	var valuesInput []model.ProductAttributeDTO
+	err := json.NewDecoder(r.Body).Decode(&valuesInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "SetProductAttributesHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	values, err := h.Service.AttributeService.SetProductAttributes(productId, valuesInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Product"), zap.String("function", "SetProductAttributesHandler"))
+		sendAttributeError(w, err, "Failed to save product attributes")
+		return
+	}
+	JsonResponse.SendSuccess(w, values, "Product attributes saved successfully")
+}
+
+func sendTranslationError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrCategoryNotFound), errors.Is(err, service.ErrRecommendationNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrTranslationInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"go.uber.org/zap"
+)
+
+// GuestTokenHeader identifies a guest browsing without an account, so their
+// recently viewed products can be tracked.
+const GuestTokenHeader = "X-Guest-Token"
+
+type ProductViewHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewProductViewHandler(service service.MainService, log *zap.Logger) ProductViewHandler {
+	return ProductViewHandler{Service: service, Logger: log}
+}
+
+func (h *ProductViewHandler) GetRecentlyViewedHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "ProductView"), zap.String("function", "GetRecentlyViewedHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var userID string
+	if user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User); ok {
+		userID = user.ID
+	}
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	products, err := h.Service.ProductViewService.GetRecentlyViewed(userID, r.Header.Get(GuestTokenHeader))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "ProductView"), zap.String("function", "GetRecentlyViewedHandler"))
+		if errors.Is(err, service.ErrViewerRequired) {
+			JsonResponse.SendError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get recently viewed products")
+		return
+	}
+	translated := make([]*model.Product, len(products))
+	for i := range products {
+		h.Service.CurrencyService.ConvertProduct(&products[i].Product, currency)
+		translated[i] = &products[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), translated...)
+	JsonResponse.SendSuccess(w, products, "Recently viewed products successfully retrieved")
+}
+
+func (h *ProductViewHandler) GetTrendingHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "ProductView"), zap.String("function", "GetTrendingHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	trending, pagination, err := h.Service.ProductViewService.GetTrending(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "ProductView"), zap.String("function", "GetTrendingHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get trending products")
+		return
+	}
+	products := make([]*model.Product, len(trending))
+	for i := range trending {
+		h.Service.CurrencyService.ConvertProduct(&trending[i].Product, currency)
+		products[i] = &trending[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), products...)
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, trending, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Trending products successfully retrieved")
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type PromotionHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewPromotionHandler(service service.MainService, log *zap.Logger) PromotionHandler {
+	return PromotionHandler{Service: service, Logger: log}
+}
+
+func (h *PromotionHandler) GetAllPromotionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	promotions, pagination, err := h.Service.PromotionService.GetAllPromotions(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "GetAllPromotionsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get promotions")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, promotions, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Promotions successfully retrieved")
+}
+
+func (h *PromotionHandler) CreatePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var promotionInput model.PromotionDTO
+	err := json.NewDecoder(r.Body).Decode(&promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "CreatePromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	promotion, err := h.Service.PromotionService.CreatePromotion(promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "CreatePromotionHandler"))
+		h.sendPromotionError(w, err, "Failed to create promotion")
+		return
+	}
+	JsonResponse.SendCreated(w, promotion, "Promotion created successfully")
+}
+
+func (h *PromotionHandler) UpdatePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	promotionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "UpdatePromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid promotion ID")
+		return
+	}
+
+	var promotionInput model.PromotionDTO
+	err = json.NewDecoder(r.Body).Decode(&promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "UpdatePromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.PromotionService.UpdatePromotion(promotionID, promotionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "UpdatePromotionHandler"))
+		h.sendPromotionError(w, err, "Failed to update promotion")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Promotion updated successfully")
+}
+
+func (h *PromotionHandler) DeletePromotionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	promotionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "DeletePromotionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid promotion ID")
+		return
+	}
+
+	err = h.Service.PromotionService.DeletePromotion(promotionID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Promotion"), zap.String("function", "DeletePromotionHandler"))
+		h.sendPromotionError(w, err, "Failed to delete promotion")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Promotion deleted successfully")
+}
+
+func (h *PromotionHandler) sendPromotionError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrPromotionNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrPromotionInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type QuestionHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewQuestionHandler(service service.MainService, log *zap.Logger) QuestionHandler {
+	return QuestionHandler{Service: service, Logger: log}
+}
+
+func (h *QuestionHandler) AskQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || productID <= 0 {
+		h.Logger.Error("Invalid product ID", zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AskQuestionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var questionInput model.QuestionDTO
+	err = json.NewDecoder(r.Body).Decode(&questionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AskQuestionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	question, err := h.Service.QuestionService.AskQuestion(user.ID, productID, questionInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AskQuestionHandler"))
+		JsonResponse.SendError(w, questionErrorStatus(err), questionErrorMessage(err, "Failed to submit question"))
+		return
+	}
+	JsonResponse.SendCreated(w, question, "Question submitted and waiting for moderation")
+}
+
+func (h *QuestionHandler) GetProductQuestionsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || productID <= 0 {
+		h.Logger.Error("Invalid product ID", zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "GetProductQuestionsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	questions, pagination, err := h.Service.QuestionService.GetProductQuestions(productID, r.URL.Query().Get("sort"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "GetProductQuestionsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product questions")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, questions, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Product questions successfully retrieved")
+}
+
+func (h *QuestionHandler) AnswerQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	questionID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AnswerQuestionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid question ID")
+		return
+	}
+
+	var answerInput model.AnswerDTO
+	err = json.NewDecoder(r.Body).Decode(&answerInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AnswerQuestionHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	answer, err := h.Service.QuestionService.AnswerQuestion(user, questionID, answerInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "AnswerQuestionHandler"))
+		JsonResponse.SendError(w, questionErrorStatus(err), questionErrorMessage(err, "Failed to submit answer"))
+		return
+	}
+
+	message := "Answer submitted and waiting for moderation"
+	if answer.ModerationStatus == model.ReviewStatusApproved {
+		message = "Answer published"
+	}
+	JsonResponse.SendCreated(w, answer, message)
+}
+
+func (h *QuestionHandler) UpvoteQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	h.upvote(w, r, "UpvoteQuestionHandler", "Invalid question ID", h.Service.QuestionService.UpvoteQuestion)
+}
+
+func (h *QuestionHandler) UpvoteAnswerHandler(w http.ResponseWriter, r *http.Request) {
+	h.upvote(w, r, "UpvoteAnswerHandler", "Invalid answer ID", h.Service.QuestionService.UpvoteAnswer)
+}
+
+func (h *QuestionHandler) upvote(w http.ResponseWriter, r *http.Request, function, invalidID string, upvote func(string, int) error) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, invalidID)
+		return
+	}
+
+	err = upvote(user.ID, id)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", function))
+		JsonResponse.SendError(w, questionErrorStatus(err), questionErrorMessage(err, "Failed to upvote"))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Upvoted successfully")
+}
+
+func (h *QuestionHandler) GetQuestionQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	questions, pagination, err := h.Service.QuestionService.GetQuestionQueue(r.URL.Query().Get("status"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "GetQuestionQueueHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get questions")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, questions, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Questions successfully retrieved")
+}
+
+func (h *QuestionHandler) GetAnswerQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	answers, pagination, err := h.Service.QuestionService.GetAnswerQueue(r.URL.Query().Get("status"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", "GetAnswerQueueHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get answers")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, answers, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Answers successfully retrieved")
+}
+
+func (h *QuestionHandler) ModerateQuestionHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "ModerateQuestionHandler", "Invalid question ID", "Question", h.Service.QuestionService.ModerateQuestion)
+}
+
+func (h *QuestionHandler) ModerateAnswerHandler(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, "ModerateAnswerHandler", "Invalid answer ID", "Answer", h.Service.QuestionService.ModerateAnswer)
+}
+
+func (h *QuestionHandler) moderate(w http.ResponseWriter, r *http.Request, function, invalidID, subject string, moderate func(int, model.QuestionModerationDTO) error) {
+	if r.Method != http.MethodPatch {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PATCH methods are allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, invalidID)
+		return
+	}
+
+	var moderationInput model.QuestionModerationDTO
+	err = json.NewDecoder(r.Body).Decode(&moderationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = moderate(id, moderationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Question"), zap.String("function", function))
+		JsonResponse.SendError(w, questionErrorStatus(err), questionErrorMessage(err, "Failed to moderate "+strings.ToLower(subject)))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, subject+" successfully "+moderationInput.ModerationStatus)
+}
+
+func questionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrQuestionNotFound), errors.Is(err, service.ErrAnswerNotFound), errors.Is(err, service.ErrProductNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrAnswerNotAllowed), errors.Is(err, service.ErrQuestionOwnPost):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrQuestionAlreadyVoted):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrQuestionEmpty), errors.Is(err, service.ErrQuestionTooLong),
+		errors.Is(err, service.ErrQuestionBannedWord), errors.Is(err, service.ErrQuestionInvalidStatus):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func questionErrorMessage(err error, fallback string) string {
+	if questionErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
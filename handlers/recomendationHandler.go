@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
 
 	// "github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
 	"go.uber.org/zap"
@@ -22,11 +26,6 @@ func NewRecommendationHandler(service service.MainService, log *zap.Logger) Reco
 	return RecommendationHandler{Service: service, Logger: log}
 }
 
-var recommedFilter = model.RecommendationDTO{
-	IsRecommended: false,
-	SetInBanner:   false,
-}
-
 func (h *RecommendationHandler) GetRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
@@ -45,14 +44,23 @@ func (h *RecommendationHandler) GetRecommendationsHandler(w http.ResponseWriter,
 		paginationInput.PerPage, _ = strconv.Atoi(perPage)
 	}
 
-	recommedFilter.IsRecommended = true
-	recommendations, pagination, err := h.Service.RecommendationService.GetProductRecommendations(recommedFilter, paginationInput)
+	// Signed in users get a feed built from their own history.
+	var recommendations []model.Recommendation
+	var pagination model.Pagination
+	var err error
+	if user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User); ok {
+		recommendations, pagination, err = h.Service.RecommendationService.GetPersonalisedRecommendations(user.ID, paginationInput)
+	} else {
+		recommendations, pagination, err = h.Service.RecommendationService.GetProductRecommendations(model.RecommendationDTO{IsRecommended: true}, paginationInput)
+	}
 	if err != nil {
 		h.Logger.Error(err.Error())
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product recommendations")
 		return
 	}
 
+	h.Service.TranslationService.TranslateRecommendations(requestLocale(w, r, h.Service), recommendations)
+
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
 	}
@@ -77,15 +85,239 @@ func (h *RecommendationHandler) GetBannerProduct(w http.ResponseWriter, r *http.
 		paginationInput.PerPage, _ = strconv.Atoi(perPage)
 	}
 
-	recommedFilter.SetInBanner = true
-	bannerProduct, pagination, err := h.Service.RecommendationService.GetProductRecommendations(recommedFilter, paginationInput)
+	bannerProduct, pagination, err := h.Service.RecommendationService.GetProductRecommendations(model.RecommendationDTO{SetInBanner: true}, paginationInput)
 	if err != nil {
 		h.Logger.Error(err.Error())
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product recommendations")
 		return
 	}
+	h.Service.TranslationService.TranslateRecommendations(requestLocale(w, r, h.Service), bannerProduct)
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
 	}
 	JsonResponse.SendPaginatedResponse(w, bannerProduct, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Banner products successfully retrieved")
 }
+
+func (h *RecommendationHandler) GetFrequentlyBoughtTogetherHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "GetFrequentlyBoughtTogetherHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	productID, _ := strconv.Atoi(id)
+	if productID <= 0 {
+		JsonResponse.SendError(w, http.StatusBadRequest, fmt.Sprintf("Invalid product id %s", id))
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	products, err := h.Service.RecommendationService.GetFrequentlyBoughtTogether(productID, limit)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "GetFrequentlyBoughtTogetherHandler"))
+		if errors.Is(err, service.ErrProductNotFound) {
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get frequently bought together products")
+		return
+	}
+	translated := make([]*model.Product, len(products))
+	for i := range products {
+		h.Service.CurrencyService.ConvertProduct(&products[i].Product, currency)
+		translated[i] = &products[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), translated...)
+	JsonResponse.SendSuccess(w, products, "Frequently bought together products successfully retrieved")
+}
+
+func (h *RecommendationHandler) CompleteYourOrderHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		errMessage := fmt.Sprintf("Invalid method %s", r.Method)
+		h.Logger.Error("Invalid method", zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "CompleteYourOrderHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, errMessage)
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
+	products, err := h.Service.RecommendationService.CompleteYourOrder(user.ID, limit)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "CompleteYourOrderHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product suggestions")
+		return
+	}
+	translated := make([]*model.Product, len(products))
+	for i := range products {
+		h.Service.CurrencyService.ConvertProduct(&products[i].Product, currency)
+		translated[i] = &products[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), translated...)
+	JsonResponse.SendSuccess(w, products, "Product suggestions successfully retrieved")
+}
+
+func (h *RecommendationHandler) GetAllRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	recommendations, pagination, err := h.Service.RecommendationService.GetAllRecommendations(paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "GetAllRecommendationsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get recommendations")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, recommendations, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Recommendations successfully retrieved")
+}
+
+func (h *RecommendationHandler) CreateRecommendationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	var recommendationInput model.RecommendationDTO
+	err := json.NewDecoder(r.Body).Decode(&recommendationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "CreateRecommendationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	recommendation, err := h.Service.RecommendationService.CreateRecommendation(recommendationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "CreateRecommendationHandler"))
+		h.sendRecommendationError(w, err, "Failed to create recommendation")
+		return
+	}
+	JsonResponse.SendCreated(w, recommendation, "Recommendation created successfully")
+}
+
+func (h *RecommendationHandler) UpdateRecommendationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	recommendationID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "UpdateRecommendationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid recommendation ID")
+		return
+	}
+
+	var recommendationInput model.RecommendationDTO
+	err = json.NewDecoder(r.Body).Decode(&recommendationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "UpdateRecommendationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.RecommendationService.UpdateRecommendation(recommendationID, recommendationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "UpdateRecommendationHandler"))
+		h.sendRecommendationError(w, err, "Failed to update recommendation")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Recommendation updated successfully")
+}
+
+func (h *RecommendationHandler) DeleteRecommendationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only DELETE methods are allowed")
+		return
+	}
+
+	recommendationID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "DeleteRecommendationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid recommendation ID")
+		return
+	}
+
+	err = h.Service.RecommendationService.DeleteRecommendation(recommendationID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "DeleteRecommendationHandler"))
+		h.sendRecommendationError(w, err, "Failed to delete recommendation")
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Recommendation deleted successfully")
+}
+
+func (h *RecommendationHandler) SaveRecommendationTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	recommendationID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "SaveRecommendationTranslationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid recommendation ID")
+		return
+	}
+
+	var translationInput model.ContentTranslation
+	err = json.NewDecoder(r.Body).Decode(&translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "SaveRecommendationTranslationHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	translation, err := h.Service.TranslationService.SaveRecommendationTranslation(recommendationID, chi.URLParam(r, "locale"), translationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Recommendation"), zap.String("function", "SaveRecommendationTranslationHandler"))
+		sendTranslationError(w, err, "Failed to save recommendation translation")
+		return
+	}
+	JsonResponse.SendSuccess(w, translation, "Recommendation translation saved successfully")
+}
+
+func (h *RecommendationHandler) sendRecommendationError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrRecommendationNotFound):
+		JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrRecommendationInvalid):
+		JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		JsonResponse.SendError(w, http.StatusInternalServerError, fallback)
+	}
+}
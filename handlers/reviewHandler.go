@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type ReviewHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewReviewHandler(service service.MainService, log *zap.Logger) ReviewHandler {
+	return ReviewHandler{Service: service, Logger: log}
+}
+
+func (h *ReviewHandler) SubmitReviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	var reviewInput model.ReviewDTO
+	err := json.NewDecoder(r.Body).Decode(&reviewInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "SubmitReviewHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	review, err := h.Service.ReviewService.SubmitReview(user.ID, reviewInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "SubmitReviewHandler"))
+		JsonResponse.SendError(w, reviewErrorStatus(err), reviewErrorMessage(err, "Failed to submit review"))
+		return
+	}
+
+	JsonResponse.SendCreated(w, review, "Review submitted and waiting for moderation")
+}
+
+func (h *ReviewHandler) GetProductReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	productID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || productID <= 0 {
+		h.Logger.Error("Invalid product ID", zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "GetProductReviewsHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	reviews, pagination, err := h.Service.ReviewService.GetProductReviews(productID, r.URL.Query().Get("sort"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "GetProductReviewsHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get product reviews")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, reviews, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Product reviews successfully retrieved")
+}
+
+func (h *ReviewHandler) MarkHelpfulHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "MarkHelpfulHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	err = h.Service.ReviewService.MarkHelpful(user.ID, reviewID)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "MarkHelpfulHandler"))
+		JsonResponse.SendError(w, reviewErrorStatus(err), reviewErrorMessage(err, "Failed to vote review"))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Review marked as helpful")
+}
+
+func (h *ReviewHandler) ReportReviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only POST methods are allowed")
+		return
+	}
+
+	user, ok := r.Context().Value(middleware.UserClaimsContextKey).(model.User)
+	if !ok {
+		h.Logger.Error("Failed to cast user from context")
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ReportReviewHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	var reportInput model.ReviewReportDTO
+	err = json.NewDecoder(r.Body).Decode(&reportInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ReportReviewHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.ReviewService.ReportReview(user.ID, reviewID, reportInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ReportReviewHandler"))
+		JsonResponse.SendError(w, reviewErrorStatus(err), reviewErrorMessage(err, "Failed to report review"))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Review reported successfully")
+}
+
+func (h *ReviewHandler) GetModerationQueueHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only GET methods are allowed")
+		return
+	}
+
+	var paginationInput model.Pagination
+	page := r.URL.Query().Get("page")
+	if page != "" {
+		paginationInput.Page, _ = strconv.Atoi(page)
+	}
+	perPage := r.URL.Query().Get("perPage")
+	if perPage != "" {
+		paginationInput.PerPage, _ = strconv.Atoi(perPage)
+	}
+
+	reviews, pagination, err := h.Service.ReviewService.GetModerationQueue(r.URL.Query().Get("status"), paginationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "GetModerationQueueHandler"))
+		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get reviews")
+		return
+	}
+
+	if pagination.CountData/pagination.PerPage > 0 {
+		TotalPage = pagination.CountData / pagination.PerPage
+	}
+	JsonResponse.SendPaginatedResponse(w, reviews, pagination.Page, pagination.PerPage, pagination.CountData, TotalPage, "Reviews successfully retrieved")
+}
+
+func (h *ReviewHandler) ModerateReviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPatch {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PATCH methods are allowed")
+		return
+	}
+
+	reviewID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ModerateReviewHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	var moderationInput model.ReviewModerationDTO
+	err = json.NewDecoder(r.Body).Decode(&moderationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ModerateReviewHandler"))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	err = h.Service.ReviewService.ModerateReview(reviewID, moderationInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Review"), zap.String("function", "ModerateReviewHandler"))
+		JsonResponse.SendError(w, reviewErrorStatus(err), reviewErrorMessage(err, "Failed to moderate review"))
+		return
+	}
+	JsonResponse.SendSuccess(w, nil, "Review successfully "+moderationInput.ReviewStatus)
+}
+
+func reviewErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrReviewNotAllowed), errors.Is(err, service.ErrReviewOwnReview):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrReviewAlreadyExists), errors.Is(err, service.ErrReviewAlreadyVoted):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrReviewInvalidRating), errors.Is(err, service.ErrReviewBannedWord),
+		errors.Is(err, service.ErrReviewInvalidStatus), errors.Is(err, service.ErrReviewReportNoReason):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func reviewErrorMessage(err error, fallback string) string {
+	if reviewErrorStatus(err) == http.StatusInternalServerError {
+		return fallback
+	}
+	return err.Error()
+}
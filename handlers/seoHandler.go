@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type SEOHandler struct {
+	Service service.MainService
+	Logger  *zap.Logger
+}
+
+func NewSEOHandler(service service.MainService, log *zap.Logger) SEOHandler {
+	return SEOHandler{Service: service, Logger: log}
+}
+
+func (h *SEOHandler) UpdateProductSEOHandler(w http.ResponseWriter, r *http.Request) {
+	h.updateSEO(w, r, "UpdateProductSEOHandler", "Invalid product ID", h.Service.SEOService.UpdateProductSEO)
+}
+
+func (h *SEOHandler) UpdateCategorySEOHandler(w http.ResponseWriter, r *http.Request) {
+	h.updateSEO(w, r, "UpdateCategorySEOHandler", "Invalid category ID", h.Service.SEOService.UpdateCategorySEO)
+}
+
+func (h *SEOHandler) updateSEO(w http.ResponseWriter, r *http.Request, function, invalidID string, update func(int, model.SEODTO) (model.SEO, error)) {
+	if r.Method != http.MethodPut {
+		h.Logger.Error("Invalid request method", zap.String("method", r.Method))
+		JsonResponse.SendError(w, http.StatusMethodNotAllowed, "Only PUT methods are allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "SEO"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, invalidID)
+		return
+	}
+
+	var seoInput model.SEODTO
+	err = json.NewDecoder(r.Body).Decode(&seoInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "SEO"), zap.String("function", function))
+		JsonResponse.SendError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	seo, err := update(id, seoInput)
+	if err != nil {
+		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "SEO"), zap.String("function", function))
+		switch {
+		case errors.Is(err, service.ErrProductNotFound), errors.Is(err, service.ErrCategoryNotFound):
+			JsonResponse.SendError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, service.ErrSEOInvalid):
+			JsonResponse.SendError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to update seo")
+		}
+		return
+	}
+	JsonResponse.SendSuccess(w, seo, "SEO updated successfully")
+}
@@ -132,7 +132,7 @@ func (h *UserHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := util.GenerateToken(user.ID, h.Config)
+	token, err := util.GenerateToken(user.ID, user.Role, h.Config)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "User"), zap.String("function", "LoginHandler"))
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to generate token")
@@ -95,12 +95,22 @@ func (h *WishlistHandler) GetWishlistHandler(w http.ResponseWriter, r *http.Requ
 		paginationInput.PerPage, _ = strconv.Atoi(perPage)
 	}
 
+	currency, ok := requestCurrency(w, r, h.Service, h.Logger)
+	if !ok {
+		return
+	}
 	wishlist, pagination, err := h.Service.WishlistService.GetWishlistByUserId(user.ID, paginationInput)
 	if err != nil {
 		h.Logger.Error(err.Error(), zap.String("method", r.Method), zap.String("handler", "Wishlist"), zap.String("function", "GetWishlistHandler"))
 		JsonResponse.SendError(w, http.StatusInternalServerError, "Failed to get wishlist")
 		return
 	}
+	products := make([]*model.Product, len(wishlist))
+	for i := range wishlist {
+		h.Service.CurrencyService.ConvertProduct(&wishlist[i].Product, currency)
+		products[i] = &wishlist[i].Product
+	}
+	h.Service.TranslationService.TranslateProducts(requestLocale(w, r, h.Service), products...)
 
 	if pagination.CountData/pagination.PerPage > 0 {
 		TotalPage = pagination.CountData / pagination.PerPage
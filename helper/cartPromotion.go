@@ -0,0 +1,164 @@
+package helper
+
+import (
+	"sort"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+)
+
+type cartUnit struct {
+	cartItemID int
+	price      model.Money
+}
+
+// ApplyCartPromotions evaluates the cart promotions across all priced cart
+// lines. Rules are applied in the given order and a line can never be
+// discounted below zero, so later rules only get what earlier ones left.
+func ApplyCartPromotions(items []model.CartItem, promotions []model.CartPromotion) []model.CartDiscount {
+	remaining := make(map[int]model.Money, len(items))
+	for _, item := range items {
+		remaining[item.ID] = item.SubTotal
+	}
+
+	var discounts []model.CartDiscount
+	for _, promotion := range promotions {
+		eligible := eligibleCartItems(items, promotion.ProductIDs)
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var lineAmounts map[int]model.Money
+		switch promotion.RuleType {
+		case model.CartRuleBuyXGetY:
+			lineAmounts = buyXGetYAmounts(eligible, promotion)
+		case model.CartRuleBundle:
+			lineAmounts = bundleAmounts(eligible, promotion)
+		case model.CartRuleTiered:
+			lineAmounts = tieredAmounts(eligible, promotion)
+		}
+
+		discount := model.CartDiscount{CartPromotionID: promotion.ID, Name: promotion.Name, RuleType: promotion.RuleType}
+		for _, item := range eligible {
+			amount := RoundPrice(lineAmounts[item.ID]).Min(remaining[item.ID])
+			if amount <= 0 {
+				continue
+			}
+			remaining[item.ID] = RoundPrice(remaining[item.ID] - amount)
+			discount.Amount += amount
+			discount.Items = append(discount.Items, model.CartDiscountItem{CartItemID: item.ID, Amount: amount})
+		}
+		if len(discount.Items) > 0 {
+			discount.Amount = RoundPrice(discount.Amount)
+			discounts = append(discounts, discount)
+		}
+	}
+	return discounts
+}
+
+func eligibleCartItems(items []model.CartItem, productIDs []int) []model.CartItem {
+	var eligible []model.CartItem
+	for _, item := range items {
+		for _, productID := range productIDs {
+			if item.ProductID == productID {
+				eligible = append(eligible, item)
+				break
+			}
+		}
+	}
+	return eligible
+}
+
+// cartUnits splits lines into single units, cheapest first.
+func cartUnits(items []model.CartItem) []cartUnit {
+	var units []cartUnit
+	for _, item := range items {
+		if item.Amount <= 0 {
+			continue
+		}
+		unitPrice := item.SubTotal.Div(item.Amount)
+		for i := 0; i < item.Amount; i++ {
+			units = append(units, cartUnit{cartItemID: item.ID, price: unitPrice})
+		}
+	}
+	sort.SliceStable(units, func(i, j int) bool { return units[i].price < units[j].price })
+	return units
+}
+
+// buyXGetYAmounts discounts the cheapest units, so "buy 2 get 1" gives the
+// cheapest of every three units away.
+func buyXGetYAmounts(items []model.CartItem, promotion model.CartPromotion) map[int]model.Money {
+	amounts := make(map[int]model.Money)
+	group := promotion.BuyQuantity + promotion.GetQuantity
+	if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+		return amounts
+	}
+	units := cartUnits(items)
+	discounted := (len(units) / group) * promotion.GetQuantity
+	for _, unit := range units[:discounted] {
+		amounts[unit.cartItemID] += unit.price
+	}
+	return percentOf(amounts, promotion.DiscountValue)
+}
+
+// bundleAmounts discounts one unit of every bundle product per complete set,
+// using the cheapest units.
+func bundleAmounts(items []model.CartItem, promotion model.CartPromotion) map[int]model.Money {
+	amounts := make(map[int]model.Money)
+	unitsByProduct := make(map[int][]cartUnit)
+	for _, item := range items {
+		unitsByProduct[item.ProductID] = append(unitsByProduct[item.ProductID], cartUnits([]model.CartItem{item})...)
+	}
+
+	sets := -1
+	for _, productID := range promotion.ProductIDs {
+		units := unitsByProduct[productID]
+		if sets == -1 || len(units) < sets {
+			sets = len(units)
+		}
+	}
+	if sets <= 0 {
+		return amounts
+	}
+
+	for _, productID := range promotion.ProductIDs {
+		units := unitsByProduct[productID]
+		sort.SliceStable(units, func(i, j int) bool { return units[i].price < units[j].price })
+		for _, unit := range units[:sets] {
+			amounts[unit.cartItemID] += unit.price
+		}
+	}
+	return percentOf(amounts, promotion.DiscountValue)
+}
+
+// tieredAmounts discounts every eligible line with the highest tier reached
+// by the combined quantity.
+func tieredAmounts(items []model.CartItem, promotion model.CartPromotion) map[int]model.Money {
+	amounts := make(map[int]model.Money)
+	var quantity int
+	for _, item := range items {
+		quantity += item.Amount
+	}
+
+	var best *model.CartPromotionTier
+	for i, tier := range promotion.Tiers {
+		if tier.MinQuantity <= quantity && (best == nil || tier.MinQuantity > best.MinQuantity) {
+			best = &promotion.Tiers[i]
+		}
+	}
+	if best == nil {
+		return amounts
+	}
+	for _, item := range items {
+		amounts[item.ID] = item.SubTotal.Percent(best.DiscountValue)
+	}
+	return amounts
+}
+
+// percentOf turns the discounted value of each line into its discount,
+// rounded to the cent per line.
+func percentOf(values map[int]model.Money, percent float64) map[int]model.Money {
+	for cartItemID, value := range values {
+		values[cartItemID] = value.Percent(percent)
+	}
+	return values
+}
@@ -0,0 +1,20 @@
+package helper
+
+import (
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+)
+
+// ConvertPrice converts a base currency amount and rounds it the way the
+// currency is shown.
+func ConvertPrice(amount model.Money, currency model.Currency) model.Money {
+	if currency.IsBase {
+		return amount
+	}
+	return RoundCurrency(amount.MulRate(currency.Rate), currency)
+}
+
+// RoundCurrency rounds to the currency rounding increment, if any, and then
+// to its number of decimals.
+func RoundCurrency(amount model.Money, currency model.Currency) model.Money {
+	return amount.RoundTo(currency.RoundingIncrement).Round(currency.Decimals)
+}
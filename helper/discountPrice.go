@@ -1,18 +1,177 @@
 package helper
 
-import "math"
+import (
+	"sort"
 
-func CalculateDiscountPrice(price, discount float64) float64 {
-	// Calculate the promo price
-	priceAfterDiscount := price * ((100.00 - discount) / 100)
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+)
 
-	// Round to 2 decimal places
-	return math.Round(priceAfterDiscount*100) / 100
+// CalculateDiscountPrice takes a discount percentage off the price.
+func CalculateDiscountPrice(price model.Money, discount float64) model.Money {
+	return RoundPrice(price - price.Percent(discount))
 }
 
-func CalculateCartPrice(price, additionalPrice, discount, promoDiscount float64, amount int) float64 {
-	totalPrice := (price + additionalPrice) * ((100.00 - discount) / 100) * float64(amount)
-	totalPrice -= totalPrice * ((100.00 - promoDiscount) / 100)
+// RoundPrice rounds a price to the cent, half away from zero.
+func RoundPrice(price model.Money) model.Money {
+	return price.Round(2)
+}
+
+// MatchPromotions returns the promotions that target the given product,
+// either directly, through its category or store-wide.
+func MatchPromotions(product model.Product, promotions []model.Promotion) []model.Promotion {
+	var matched []model.Promotion
+	for _, promotion := range promotions {
+		switch promotion.TargetType {
+		case model.PromotionTargetAll:
+			matched = append(matched, promotion)
+		case model.PromotionTargetCategory:
+			if product.CategoryID != 0 && promotion.TargetID == product.CategoryID {
+				matched = append(matched, promotion)
+			}
+		case model.PromotionTargetProduct:
+			if promotion.TargetID == product.ID {
+				matched = append(matched, promotion)
+			}
+		}
+	}
+	return matched
+}
+
+// ApplyPromotions discounts a unit price with the given promotions.
+//
+// Stackable promotions are applied one after another in priority order.
+// A non-stackable promotion cannot be combined with anything, so the engine
+// uses the single best non-stackable promotion only when it beats the
+// combined stackable ones. Ties are won by the higher priority.
+func ApplyPromotions(unitPrice model.Money, promotions []model.Promotion) (model.Money, []model.AppliedPromotion) {
+	sorted := make([]model.Promotion, len(promotions))
+	copy(sorted, promotions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	stackedPrice := unitPrice
+	var stacked []model.AppliedPromotion
+	var exclusive *model.AppliedPromotion
+	for _, promotion := range sorted {
+		if promotion.Stackable {
+			amount := promotionAmount(stackedPrice, promotion)
+			if amount <= 0 {
+				continue
+			}
+			stackedPrice = RoundPrice(stackedPrice - amount)
+			stacked = append(stacked, appliedPromotion(promotion, amount))
+			continue
+		}
+
+		amount := promotionAmount(unitPrice, promotion)
+		if amount > 0 && (exclusive == nil || amount > exclusive.Amount) {
+			applied := appliedPromotion(promotion, amount)
+			exclusive = &applied
+		}
+	}
+
+	if exclusive != nil && exclusive.Amount > RoundPrice(unitPrice-stackedPrice) {
+		return RoundPrice(unitPrice - exclusive.Amount), []model.AppliedPromotion{*exclusive}
+	}
+	return stackedPrice, stacked
+}
+
+// CalculatePrice is the single pricing function for a product line: the
+// variant additional price is added to the product price, products.discount
+// is taken off and the matching promotions are applied on top.
+func CalculatePrice(product model.Product, additionalPrice model.Money, amount int, promotions []model.Promotion) model.PriceBreakdown {
+	unitPrice := RoundPrice(product.Price + additionalPrice)
+	discountedPrice := CalculateDiscountPrice(unitPrice, product.Discount)
+	finalPrice, applied := ApplyPromotions(discountedPrice, MatchPromotions(product, promotions))
+
+	return model.PriceBreakdown{
+		UnitPrice:       unitPrice,
+		DiscountedPrice: discountedPrice,
+		FinalUnitPrice:  finalPrice,
+		Amount:          amount,
+		SubTotal:        RoundPrice(finalPrice.Mul(amount)),
+		Promotions:      applied,
+	}
+}
+
+// CalculateFlashSalePrice prices a line at the flash sale price. The sale
+// price replaces the product price, discount and promotions; the variant
+// additional price still applies.
+func CalculateFlashSalePrice(product model.Product, item model.FlashSaleItem, additionalPrice model.Money, amount int) model.PriceBreakdown {
+	finalPrice := RoundPrice(item.SalePrice + additionalPrice)
+	return model.PriceBreakdown{
+		UnitPrice:       RoundPrice(product.Price + additionalPrice),
+		DiscountedPrice: finalPrice,
+		FinalUnitPrice:  finalPrice,
+		Amount:          amount,
+		SubTotal:        RoundPrice(finalPrice.Mul(amount)),
+		FlashSaleItemID: item.ID,
+	}
+}
+
+// promotionAmount is the discount a promotion gives on a unit price.
+// DiscountValue is a percentage or, for fixed promotions, an amount.
+func promotionAmount(price model.Money, promotion model.Promotion) model.Money {
+	var amount model.Money
+	switch promotion.DiscountType {
+	case model.DiscountTypePercentage:
+		amount = price.Percent(promotion.DiscountValue.Float64())
+	case model.DiscountTypeFixed:
+		amount = RoundPrice(promotion.DiscountValue)
+	}
+	if amount > price {
+		amount = price
+	}
+	return amount
+}
+
+func appliedPromotion(promotion model.Promotion, amount model.Money) model.AppliedPromotion {
+	return model.AppliedPromotion{
+		PromotionID:   promotion.ID,
+		Name:          promotion.Name,
+		DiscountType:  promotion.DiscountType,
+		DiscountValue: promotion.DiscountValue,
+		Amount:        amount,
+	}
+}
+
+// CouponEligibleSubtotal sums the cart lines the coupon can discount, after
+// cart promotions. A coupon without categories applies to every line.
+func CouponEligibleSubtotal(coupon model.Coupon, items []model.CartItem) model.Money {
+	var subtotal model.Money
+	for _, item := range items {
+		if len(coupon.CategoryIDs) == 0 {
+			subtotal += item.SubTotal - item.Discount
+			continue
+		}
+		for _, categoryID := range coupon.CategoryIDs {
+			if item.Product.CategoryID == categoryID {
+				subtotal += item.SubTotal - item.Discount
+				break
+			}
+		}
+	}
+	return RoundPrice(subtotal)
+}
 
-	return math.Round(totalPrice*100) / 100
+// CalculateCouponDiscount returns the amount a coupon takes off the eligible
+// subtotal, or off the shipping cost for free shipping coupons.
+func CalculateCouponDiscount(coupon model.Coupon, eligibleSubtotal, shippingCost model.Money) model.Money {
+	var discount model.Money
+	switch coupon.CouponType {
+	case model.DiscountTypePercentage:
+		discount = eligibleSubtotal.Percent(coupon.DiscountValue.Float64())
+	case model.DiscountTypeFixed:
+		discount = RoundPrice(coupon.DiscountValue).Min(eligibleSubtotal)
+	case model.CouponTypeFreeShipping:
+		discount = shippingCost
+	}
+	if coupon.MaxDiscount > 0 {
+		discount = discount.Min(coupon.MaxDiscount)
+	}
+	return RoundPrice(discount)
 }
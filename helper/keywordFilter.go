@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultBannedWords is used when no banned words are configured.
+var DefaultBannedWords = []string{"fuck", "shit", "bitch", "asshole", "bastard", "scam"}
+
+var wordSplitter = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// ParseBannedWords splits a comma separated list of banned words,
+// falling back to DefaultBannedWords when the list is empty.
+func ParseBannedWords(list string) []string {
+	var words []string
+	for _, word := range strings.Split(list, ",") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+	if len(words) == 0 {
+		return DefaultBannedWords
+	}
+	return words
+}
+
+// FindBannedWord returns the first banned word found in text. Words are
+// matched case-insensitively on word boundaries so "scampi" does not match "scam".
+func FindBannedWord(text string, bannedWords []string) (string, bool) {
+	banned := make(map[string]bool, len(bannedWords))
+	for _, word := range bannedWords {
+		banned[strings.ToLower(word)] = true
+	}
+	for _, word := range wordSplitter.Split(strings.ToLower(text), -1) {
+		if banned[word] {
+			return word, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,14 @@
+package helper
+
+import (
+	"regexp"
+	"strings"
+)
+
+var slugSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify turns a name into a lowercase, hyphen separated URL slug.
+// Characters other than ASCII letters and digits separate words.
+func Slugify(name string) string {
+	return strings.Trim(slugSeparator.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
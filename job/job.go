@@ -0,0 +1,89 @@
+package job
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Start runs task once right away and then every interval in the
+// background for the life of the process. A zero or negative interval
+// disables the job. Errors are logged and the job keeps its schedule.
+func Start(logger *zap.Logger, name string, interval time.Duration, task func() error) {
+	if interval <= 0 {
+		logger.Info("job disabled", zap.String("job", name))
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			run(logger, name, task)
+			<-ticker.C
+		}
+	}()
+}
+
+func run(logger *zap.Logger, name string, task func() error) {
+	defer func() {
+		if p := recover(); p != nil {
+			logger.Error("job panicked", zap.String("job", name), zap.Any("panic", p))
+		}
+	}()
+
+	start := time.Now()
+	if err := task(); err != nil {
+		logger.Error("job failed", zap.String("job", name), zap.Error(err), zap.Duration("duration", time.Since(start)))
+		return
+	}
+	logger.Info("job finished", zap.String("job", name), zap.Duration("duration", time.Since(start)))
+}
+
+// Trigger makes a job started with StartTriggered run ahead of schedule.
+type Trigger chan struct{}
+
+func NewTrigger() Trigger {
+	return make(Trigger, 1)
+}
+
+// Pull asks the job to run now. It never blocks; pulls made while a run is
+// already pending are merged into it.
+func (t Trigger) Pull() {
+	select {
+	case t <- struct{}{}:
+	default:
+	}
+}
+
+// StartTriggered is Start for tasks that are also due at known moments.
+// After every run, next reports the next such moment, or a zero time for
+// none, and the task runs then if that comes before the interval is up.
+// Pulling the trigger runs the task right away and asks next again.
+func StartTriggered(logger *zap.Logger, name string, interval time.Duration, next func() (time.Time, error), trigger Trigger, task func() error) {
+	if interval <= 0 {
+		logger.Info("job disabled", zap.String("job", name))
+		return
+	}
+
+	go func() {
+		for {
+			run(logger, name, task)
+
+			wait := interval
+			due, err := next()
+			if err != nil {
+				logger.Error("job schedule failed", zap.String("job", name), zap.Error(err))
+			} else if !due.IsZero() && time.Until(due) < wait {
+				wait = max(time.Until(due), 0)
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-trigger:
+				timer.Stop()
+			}
+		}
+	}()
+}
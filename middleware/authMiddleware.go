@@ -41,23 +41,62 @@ func (m *Middleware) AuthMiddleware(next http.Handler) http.Handler {
 			m.handleUnauthorized(w, r, "Unauthorized access: "+err.Error())
 			return
 		}
+		m.authenticate(w, r, next, token)
+	})
+}
 
-		m.Log.Info("Token extracted successfully", zap.String("token", token))
-
-		claims, err := util.VerifyToken(token, m.Config)
+// OptionalAuthMiddleware adds the user to the context when the request
+// carries a token and lets anonymous requests through. A token that is
+// present but invalid is still rejected.
+func (m *Middleware) OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, err := m.extractToken(r)
 		if err != nil {
-			m.handleUnauthorized(w, r, "Invalid token: "+err.Error())
+			next.ServeHTTP(w, r)
 			return
 		}
+		m.authenticate(w, r, next, token)
+	})
+}
+
+func (m *Middleware) authenticate(w http.ResponseWriter, r *http.Request, next http.Handler, token string) {
+	m.Log.Info("Token extracted successfully", zap.String("token", token))
 
-		m.Log.Info("Claims parsed successfully", zap.Any("claims", claims))
+	claims, err := util.VerifyToken(token, m.Config)
+	if err != nil {
+		m.handleUnauthorized(w, r, "Invalid token: "+err.Error())
+		return
+	}
+
+	m.Log.Info("Claims parsed successfully", zap.Any("claims", claims))
+
+	user := model.User{
+		ID:   claims["userId"].(string),
+		Role: model.RoleCustomer,
+	}
+	if role, ok := claims["role"].(string); ok && role != "" {
+		user.Role = role
+	}
+	ctx := context.WithValue(r.Context(), UserClaimsContextKey, user)
+	m.Log.Info("added to context", zap.Any("contextValue", user))
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
 
-		user := model.User{
-			ID: claims["userId"].(string),
+// AdminMiddleware only lets through users authenticated with the admin role.
+// It must be chained after AuthMiddleware.
+func (m *Middleware) AdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(UserClaimsContextKey).(model.User)
+		if !ok || user.Role != model.RoleAdmin {
+			m.Log.Info("Forbidden access",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+			m.respondWithError(w, http.StatusForbidden, "Forbidden: admin access required")
+			return
 		}
-		ctx := context.WithValue(r.Context(), UserClaimsContextKey, user)
-		m.Log.Info("added to context", zap.Any("contextValue", user))
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r)
 	})
 }
 
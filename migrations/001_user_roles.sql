@@ -0,0 +1,11 @@
+-- Adds a role to users so admin-only endpoints can be protected.
+
+CREATE TYPE public.user_role_enum AS ENUM (
+    'customer',
+    'admin'
+);
+
+ALTER TYPE public.user_role_enum OWNER TO postgres;
+
+ALTER TABLE ONLY public.users
+    ADD COLUMN role public.user_role_enum DEFAULT 'customer'::public.user_role_enum NOT NULL;
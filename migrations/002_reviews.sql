@@ -0,0 +1,58 @@
+-- Product reviews with an admin moderation queue, helpful votes and abuse reports.
+
+CREATE TYPE public.review_status_enum AS ENUM (
+    'pending',
+    'approved',
+    'rejected'
+);
+
+ALTER TYPE public.review_status_enum OWNER TO postgres;
+
+CREATE TYPE public.review_vote_enum AS ENUM (
+    'helpful',
+    'report'
+);
+
+ALTER TYPE public.review_vote_enum OWNER TO postgres;
+
+CREATE TABLE public.reviews (
+    id SERIAL PRIMARY KEY,
+    order_item_id integer NOT NULL REFERENCES public.order_items(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    rating numeric(2,1) NOT NULL,
+    comment text,
+    review_status public.review_status_enum DEFAULT 'pending'::public.review_status_enum NOT NULL,
+    moderation_note text,
+    moderated_at timestamp without time zone,
+    helpful_count integer DEFAULT 0 NOT NULL,
+    report_count integer DEFAULT 0 NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT reviews_rating_check CHECK (((rating >= (1)::numeric) AND (rating <= (5)::numeric))),
+    CONSTRAINT reviews_order_item_id_key UNIQUE (order_item_id)
+);
+
+ALTER TABLE public.reviews OWNER TO postgres;
+
+CREATE INDEX reviews_product_id_status_idx ON public.reviews (product_id, review_status);
+
+CREATE TABLE public.review_votes (
+    id SERIAL PRIMARY KEY,
+    review_id integer NOT NULL REFERENCES public.reviews(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    vote_type public.review_vote_enum NOT NULL,
+    reason text,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT review_votes_review_user_type_key UNIQUE (review_id, user_id, vote_type)
+);
+
+ALTER TABLE public.review_votes OWNER TO postgres;
+
+-- products.rating is recalculated from approved reviews, so a product
+-- without any approved review needs to be able to hold 0.
+ALTER TABLE ONLY public.products DROP CONSTRAINT products_rating_check;
+ALTER TABLE ONLY public.products
+    ADD CONSTRAINT products_rating_check CHECK (((rating = (0)::numeric) OR ((rating >= (1)::numeric) AND (rating <= (5)::numeric))));
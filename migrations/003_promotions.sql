@@ -0,0 +1,52 @@
+-- Generalised promotion campaigns replacing weekly_promos.
+
+CREATE TYPE public.promotion_target_enum AS ENUM (
+    'all',
+    'category',
+    'product'
+);
+
+ALTER TYPE public.promotion_target_enum OWNER TO postgres;
+
+CREATE TYPE public.promotion_discount_enum AS ENUM (
+    'percentage',
+    'fixed'
+);
+
+ALTER TYPE public.promotion_discount_enum OWNER TO postgres;
+
+CREATE TABLE public.promotions (
+    id SERIAL PRIMARY KEY,
+    name character varying NOT NULL,
+    description text,
+    target_type public.promotion_target_enum DEFAULT 'all'::public.promotion_target_enum NOT NULL,
+    target_id integer,
+    discount_type public.promotion_discount_enum NOT NULL,
+    discount_value numeric(10,2) NOT NULL,
+    priority integer DEFAULT 0 NOT NULL,
+    stackable boolean DEFAULT false NOT NULL,
+    starts_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT promotions_discount_value_check CHECK ((discount_value > (0)::numeric)),
+    CONSTRAINT promotions_percentage_check CHECK (((discount_type <> 'percentage'::public.promotion_discount_enum) OR (discount_value <= (100)::numeric))),
+    CONSTRAINT promotions_period_check CHECK ((ends_at > starts_at)),
+    CONSTRAINT promotions_target_check CHECK (((target_type = 'all'::public.promotion_target_enum) = (target_id IS NULL)))
+);
+
+ALTER TABLE public.promotions OWNER TO postgres;
+
+CREATE INDEX promotions_active_period_idx ON public.promotions (starts_at, ends_at) WHERE (status = 'active'::public.status_enum);
+
+-- Weekly promos were percentage discounts stacked on top of products.discount
+-- for whole days, so they map onto stackable product promotions.
+INSERT INTO public.promotions (name, target_type, target_id, discount_type, discount_value, stackable, starts_at, ends_at, status, created_at, updated_at, deleted_at)
+SELECT 'Weekly promo #' || id, 'product', product_id, 'percentage', promo_discount, true,
+       start_date::timestamp, (end_date + 1)::timestamp, COALESCE(status, 'active'), COALESCE(created_at, now()), updated_at, deleted_at
+FROM public.weekly_promos
+WHERE product_id IS NOT NULL AND start_date IS NOT NULL AND end_date IS NOT NULL AND promo_discount > 0;
+
+DROP TABLE public.weekly_promos;
@@ -0,0 +1,63 @@
+-- Coupon codes applied to a cart and redeemed when the order is created.
+
+CREATE TYPE public.coupon_type_enum AS ENUM (
+    'percentage',
+    'fixed',
+    'free_shipping'
+);
+
+ALTER TYPE public.coupon_type_enum OWNER TO postgres;
+
+CREATE TABLE public.coupons (
+    id SERIAL PRIMARY KEY,
+    code character varying(50) NOT NULL,
+    description text,
+    coupon_type public.coupon_type_enum NOT NULL,
+    discount_value numeric(10,2) DEFAULT 0 NOT NULL,
+    max_discount numeric(10,2),
+    min_spend numeric(10,2) DEFAULT 0 NOT NULL,
+    starts_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    usage_limit integer,
+    per_user_limit integer DEFAULT 1,
+    times_used integer DEFAULT 0 NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT coupons_period_check CHECK ((ends_at > starts_at)),
+    CONSTRAINT coupons_times_used_check CHECK (((usage_limit IS NULL) OR (times_used <= usage_limit)))
+);
+
+ALTER TABLE public.coupons OWNER TO postgres;
+
+CREATE UNIQUE INDEX coupons_code_key ON public.coupons (upper(code)) WHERE (status = 'active'::public.status_enum);
+
+CREATE TABLE public.coupon_categories (
+    coupon_id integer NOT NULL REFERENCES public.coupons(id) ON DELETE CASCADE,
+    category_id integer NOT NULL REFERENCES public.categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (coupon_id, category_id)
+);
+
+ALTER TABLE public.coupon_categories OWNER TO postgres;
+
+ALTER TABLE ONLY public.carts
+    ADD COLUMN coupon_id integer REFERENCES public.coupons(id) ON DELETE SET NULL;
+
+ALTER TABLE ONLY public.orders
+    ADD COLUMN coupon_id integer REFERENCES public.coupons(id) ON DELETE SET NULL,
+    ADD COLUMN discount_amount numeric(10,2) DEFAULT 0 NOT NULL;
+
+CREATE TABLE public.coupon_redemptions (
+    id SERIAL PRIMARY KEY,
+    coupon_id integer NOT NULL REFERENCES public.coupons(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    order_id integer NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    discount_amount numeric(10,2) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT coupon_redemptions_order_id_key UNIQUE (order_id)
+);
+
+ALTER TABLE public.coupon_redemptions OWNER TO postgres;
+
+CREATE INDEX coupon_redemptions_coupon_user_idx ON public.coupon_redemptions (coupon_id, user_id);
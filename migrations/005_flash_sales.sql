@@ -0,0 +1,49 @@
+-- Flash sales: a fixed allocation of a product at a sale price for a short,
+-- second-precise window.
+
+CREATE TABLE public.flash_sales (
+    id SERIAL PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    starts_at timestamp(0) without time zone NOT NULL,
+    ends_at timestamp(0) without time zone NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT flash_sales_period_check CHECK ((ends_at > starts_at))
+);
+
+ALTER TABLE public.flash_sales OWNER TO postgres;
+
+CREATE INDEX flash_sales_period_idx ON public.flash_sales (starts_at, ends_at) WHERE (status = 'active'::public.status_enum);
+
+CREATE TABLE public.flash_sale_items (
+    id SERIAL PRIMARY KEY,
+    flash_sale_id integer NOT NULL REFERENCES public.flash_sales(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    sale_price numeric(10,2) NOT NULL,
+    allocation integer NOT NULL,
+    sold integer DEFAULT 0 NOT NULL,
+    per_customer_limit integer,
+    CONSTRAINT flash_sale_items_sale_price_check CHECK ((sale_price >= (0)::numeric)),
+    CONSTRAINT flash_sale_items_allocation_check CHECK ((allocation > 0)),
+    CONSTRAINT flash_sale_items_sold_check CHECK (((sold >= 0) AND (sold <= allocation))),
+    CONSTRAINT flash_sale_items_product_key UNIQUE (flash_sale_id, product_id)
+);
+
+ALTER TABLE public.flash_sale_items OWNER TO postgres;
+
+CREATE TABLE public.flash_sale_purchases (
+    id SERIAL PRIMARY KEY,
+    flash_sale_item_id integer NOT NULL REFERENCES public.flash_sale_items(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    order_id integer NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    amount integer NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT flash_sale_purchases_amount_check CHECK ((amount > 0))
+);
+
+ALTER TABLE public.flash_sale_purchases OWNER TO postgres;
+
+CREATE INDEX flash_sale_purchases_item_user_idx ON public.flash_sale_purchases (flash_sale_item_id, user_id);
+CREATE INDEX flash_sale_purchases_order_idx ON public.flash_sale_purchases (order_id);
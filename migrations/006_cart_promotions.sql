@@ -0,0 +1,72 @@
+-- Promotions evaluated across the whole cart: buy X get Y, bundles and
+-- tiered quantity pricing. Applied promotions are snapshotted on the order.
+
+CREATE TYPE public.cart_promotion_rule_enum AS ENUM (
+    'buy_x_get_y',
+    'bundle',
+    'tiered'
+);
+
+ALTER TYPE public.cart_promotion_rule_enum OWNER TO postgres;
+
+CREATE TABLE public.cart_promotions (
+    id SERIAL PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    rule_type public.cart_promotion_rule_enum NOT NULL,
+    buy_quantity integer DEFAULT 0 NOT NULL,
+    get_quantity integer DEFAULT 0 NOT NULL,
+    discount_value numeric(5,2) DEFAULT 0 NOT NULL,
+    starts_at timestamp without time zone NOT NULL,
+    ends_at timestamp without time zone NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT cart_promotions_discount_value_check CHECK (((discount_value >= (0)::numeric) AND (discount_value <= (100)::numeric))),
+    CONSTRAINT cart_promotions_period_check CHECK ((ends_at > starts_at)),
+    CONSTRAINT cart_promotions_buy_x_get_y_check CHECK (((rule_type <> 'buy_x_get_y'::public.cart_promotion_rule_enum) OR ((buy_quantity > 0) AND (get_quantity > 0))))
+);
+
+ALTER TABLE public.cart_promotions OWNER TO postgres;
+
+CREATE TABLE public.cart_promotion_products (
+    cart_promotion_id integer NOT NULL REFERENCES public.cart_promotions(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    PRIMARY KEY (cart_promotion_id, product_id)
+);
+
+ALTER TABLE public.cart_promotion_products OWNER TO postgres;
+
+CREATE TABLE public.cart_promotion_tiers (
+    id SERIAL PRIMARY KEY,
+    cart_promotion_id integer NOT NULL REFERENCES public.cart_promotions(id) ON DELETE CASCADE,
+    min_quantity integer NOT NULL,
+    discount_value numeric(5,2) NOT NULL,
+    CONSTRAINT cart_promotion_tiers_min_quantity_check CHECK ((min_quantity > 0)),
+    CONSTRAINT cart_promotion_tiers_discount_value_check CHECK (((discount_value > (0)::numeric) AND (discount_value <= (100)::numeric))),
+    CONSTRAINT cart_promotion_tiers_key UNIQUE (cart_promotion_id, min_quantity)
+);
+
+ALTER TABLE public.cart_promotion_tiers OWNER TO postgres;
+
+ALTER TABLE ONLY public.orders
+    ADD COLUMN promotion_discount numeric(10,2) DEFAULT 0 NOT NULL;
+
+ALTER TABLE ONLY public.order_items
+    ADD COLUMN discount numeric(10,2) DEFAULT 0 NOT NULL;
+
+-- source is 'promotion' for per-unit promotions or the cart promotion rule type.
+CREATE TABLE public.order_promotions (
+    id SERIAL PRIMARY KEY,
+    order_id integer NOT NULL REFERENCES public.orders(id) ON DELETE CASCADE,
+    order_item_id integer REFERENCES public.order_items(id) ON DELETE CASCADE,
+    source character varying(20) NOT NULL,
+    promotion_id integer NOT NULL,
+    name character varying(100) NOT NULL,
+    amount numeric(10,2) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE public.order_promotions OWNER TO postgres;
+
+CREATE INDEX order_promotions_order_idx ON public.order_promotions (order_id);
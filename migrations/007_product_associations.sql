@@ -0,0 +1,16 @@
+-- Precomputed "frequently bought together" pairs, rebuilt periodically from
+-- successful orders.
+
+CREATE TABLE public.product_associations (
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    associated_product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    support_count integer NOT NULL,
+    confidence numeric(6,5) NOT NULL,
+    lift numeric(12,5) NOT NULL,
+    computed_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (product_id, associated_product_id)
+);
+
+ALTER TABLE public.product_associations OWNER TO postgres;
+
+CREATE INDEX product_associations_rank_idx ON public.product_associations (product_id, lift DESC, confidence DESC);
@@ -0,0 +1,16 @@
+-- Product page views, per signed in user or per guest token. Anonymous views
+-- without either still count toward trending.
+
+CREATE TABLE public.product_views (
+    id SERIAL PRIMARY KEY,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    user_id character varying,
+    guest_token character varying(64),
+    viewed_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE public.product_views OWNER TO postgres;
+
+CREATE INDEX product_views_user_idx ON public.product_views (user_id, viewed_at DESC) WHERE (user_id IS NOT NULL);
+CREATE INDEX product_views_guest_idx ON public.product_views (guest_token, viewed_at DESC) WHERE (guest_token IS NOT NULL);
+CREATE INDEX product_views_viewed_at_idx ON public.product_views (viewed_at, product_id);
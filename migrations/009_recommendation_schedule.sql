@@ -0,0 +1,11 @@
+-- Banners and recommendations get a display order, an optional schedule and
+-- a target link. Entries without a schedule are always shown.
+
+ALTER TABLE public.recommendations
+    ADD COLUMN display_order integer DEFAULT 0 NOT NULL,
+    ADD COLUMN starts_at timestamp without time zone,
+    ADD COLUMN ends_at timestamp without time zone,
+    ADD COLUMN path_url text,
+    ADD CONSTRAINT recommendations_period_check CHECK (((starts_at IS NULL) OR (ends_at IS NULL) OR (ends_at > starts_at)));
+
+CREATE INDEX recommendations_display_idx ON public.recommendations (display_order, id) WHERE (status = 'active'::public.status_enum);
@@ -0,0 +1,17 @@
+-- Best-seller ranks per time window, rebuilt periodically from successful
+-- orders. products.is_best_selling mirrors the top ranks of one window.
+
+CREATE TABLE public.product_sales_ranks (
+    window_days integer NOT NULL,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    quantity integer NOT NULL,
+    rank integer NOT NULL,
+    computed_at timestamp without time zone DEFAULT now() NOT NULL,
+    PRIMARY KEY (window_days, product_id)
+);
+
+ALTER TABLE public.product_sales_ranks OWNER TO postgres;
+
+CREATE INDEX product_sales_ranks_rank_idx ON public.product_sales_ranks (window_days, rank);
+
+ALTER TABLE public.products ADD COLUMN is_best_selling boolean DEFAULT false NOT NULL;
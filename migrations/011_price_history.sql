@@ -0,0 +1,42 @@
+-- Price history per product. Changes to products.price and discount are
+-- recorded by a trigger; promotion and flash sale prices are recorded by the
+-- price snapshot job when the price a customer pays changes.
+
+CREATE TABLE public.price_history (
+    id SERIAL PRIMARY KEY,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    price numeric(10,2) NOT NULL,
+    discount numeric(10,2) DEFAULT 0 NOT NULL,
+    final_price numeric(10,2) NOT NULL,
+    source character varying(20) NOT NULL,
+    recorded_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT price_history_source_check CHECK (((source)::text = ANY ((ARRAY['product'::character varying, 'promotion'::character varying, 'flash_sale'::character varying])::text[])))
+);
+
+ALTER TABLE public.price_history OWNER TO postgres;
+
+CREATE INDEX price_history_product_idx ON public.price_history (product_id, recorded_at DESC);
+
+CREATE FUNCTION public.record_product_price() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' AND OLD.price = NEW.price AND COALESCE(OLD.discount, 0) = COALESCE(NEW.discount, 0) THEN
+        RETURN NEW;
+    END IF;
+    INSERT INTO public.price_history (product_id, price, discount, final_price, source)
+    VALUES (NEW.id, NEW.price, COALESCE(NEW.discount, 0),
+        round(NEW.price * (100 - COALESCE(NEW.discount, 0)) / 100, 2), 'product');
+    RETURN NEW;
+END;
+$$;
+
+ALTER FUNCTION public.record_product_price() OWNER TO postgres;
+
+CREATE TRIGGER products_price_history AFTER INSERT OR UPDATE OF price, discount ON public.products
+    FOR EACH ROW EXECUTE FUNCTION public.record_product_price();
+
+-- Start the history with the current prices.
+INSERT INTO public.price_history (product_id, price, discount, final_price, source)
+SELECT id, price, COALESCE(discount, 0), round(price * (100 - COALESCE(discount, 0)) / 100, 2), 'product'
+FROM public.products;
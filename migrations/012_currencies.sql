@@ -0,0 +1,33 @@
+-- Display and checkout currencies. Prices are stored in the base currency;
+-- rate is how many units of a currency one base unit buys. Each order keeps
+-- the currency and rate it was placed with.
+
+CREATE TABLE public.currencies (
+    code character(3) PRIMARY KEY,
+    name character varying(50) NOT NULL,
+    symbol character varying(5) DEFAULT ''::character varying NOT NULL,
+    rate numeric(18,8) NOT NULL,
+    decimals smallint DEFAULT 2 NOT NULL,
+    rounding_increment numeric(10,4) DEFAULT 0 NOT NULL,
+    is_base boolean DEFAULT false NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT currencies_rate_check CHECK ((rate > (0)::numeric)),
+    CONSTRAINT currencies_decimals_check CHECK (((decimals >= 0) AND (decimals <= 4))),
+    CONSTRAINT currencies_rounding_increment_check CHECK ((rounding_increment >= (0)::numeric))
+);
+
+ALTER TABLE public.currencies OWNER TO postgres;
+
+CREATE UNIQUE INDEX currencies_base_idx ON public.currencies (is_base) WHERE is_base;
+
+INSERT INTO public.currencies (code, name, symbol, rate, decimals, is_base) VALUES ('USD', 'US Dollar', '$', 1, 2, true);
+
+ALTER TABLE public.orders
+    ADD COLUMN currency_code character(3) DEFAULT 'USD'::bpchar NOT NULL,
+    ADD COLUMN exchange_rate numeric(18,8) DEFAULT 1 NOT NULL,
+    ADD COLUMN currency_total numeric(14,4);
+
+UPDATE public.orders SET currency_total = total_price;
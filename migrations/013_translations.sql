@@ -0,0 +1,21 @@
+-- Localised content. The text stored on products, categories, variants,
+-- variant options and recommendations is the default locale; a row here
+-- overrides one field of one entity in another locale. Fields without a row
+-- fall back to the default text.
+
+CREATE TABLE public.translations (
+    id SERIAL PRIMARY KEY,
+    entity_type character varying(30) NOT NULL,
+    entity_id integer NOT NULL,
+    locale character varying(10) NOT NULL,
+    field character varying(30) NOT NULL,
+    value text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    CONSTRAINT translations_entity_type_check CHECK (((entity_type)::text = ANY (ARRAY['product'::text, 'category'::text, 'variant'::text, 'variant_option'::text, 'recommendation'::text]))),
+    CONSTRAINT translations_entity_locale_field_key UNIQUE (entity_type, entity_id, locale, field)
+);
+
+ALTER TABLE public.translations OWNER TO postgres;
+
+CREATE INDEX translations_locale_idx ON public.translations (entity_type, locale, entity_id);
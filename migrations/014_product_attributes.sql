@@ -0,0 +1,37 @@
+-- Typed specification attributes. An attribute belongs to a category, or to
+-- every category when category_id is NULL. Each product holds at most one
+-- value per attribute, in the column matching the attribute's data type.
+
+CREATE TABLE public.attributes (
+    id SERIAL PRIMARY KEY,
+    category_id integer REFERENCES public.categories(id) ON DELETE CASCADE,
+    code character varying(50) NOT NULL,
+    name character varying(100) NOT NULL,
+    data_type character varying(10) NOT NULL,
+    unit character varying(20) DEFAULT ''::character varying NOT NULL,
+    is_filterable boolean DEFAULT true NOT NULL,
+    display_order integer DEFAULT 0 NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT attributes_data_type_check CHECK (((data_type)::text = ANY (ARRAY['text'::text, 'number'::text, 'boolean'::text])))
+);
+
+ALTER TABLE public.attributes OWNER TO postgres;
+
+CREATE UNIQUE INDEX attributes_category_code_idx ON public.attributes (COALESCE(category_id, 0), code) WHERE (status = 'active'::public.status_enum);
+
+CREATE TABLE public.product_attribute_values (
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    attribute_id integer NOT NULL REFERENCES public.attributes(id) ON DELETE CASCADE,
+    value_text character varying(255),
+    value_number numeric(14,4),
+    value_boolean boolean,
+    PRIMARY KEY (product_id, attribute_id)
+);
+
+ALTER TABLE public.product_attribute_values OWNER TO postgres;
+
+CREATE INDEX product_attribute_values_text_idx ON public.product_attribute_values (attribute_id, lower((value_text)::text));
+CREATE INDEX product_attribute_values_number_idx ON public.product_attribute_values (attribute_id, value_number);
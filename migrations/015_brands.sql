@@ -0,0 +1,23 @@
+-- Brands. A product belongs to at most one brand; deleting a brand takes it
+-- off its products.
+
+CREATE TABLE public.brands (
+    id SERIAL PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    slug character varying(120) NOT NULL,
+    logo_url character varying(255) DEFAULT ''::character varying NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone
+);
+
+ALTER TABLE public.brands OWNER TO postgres;
+
+CREATE UNIQUE INDEX brands_slug_idx ON public.brands (slug) WHERE (status = 'active'::public.status_enum);
+
+ALTER TABLE public.products
+    ADD COLUMN brand_id integer REFERENCES public.brands(id) ON DELETE SET NULL;
+
+CREATE INDEX products_brand_idx ON public.products (brand_id) WHERE (brand_id IS NOT NULL);
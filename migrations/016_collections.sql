@@ -0,0 +1,40 @@
+-- Product tags and curated collections. A manual collection lists its
+-- products in collection_products; a rule collection matches products on a
+-- rule such as "tag=summer AND discount>20", evaluated when it is listed.
+
+CREATE TABLE public.product_tags (
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    tag character varying(50) NOT NULL,
+    PRIMARY KEY (product_id, tag)
+);
+
+ALTER TABLE public.product_tags OWNER TO postgres;
+
+CREATE INDEX product_tags_tag_idx ON public.product_tags (tag);
+
+CREATE TABLE public.collections (
+    id SERIAL PRIMARY KEY,
+    name character varying(100) NOT NULL,
+    slug character varying(120) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    type character varying(10) DEFAULT 'manual'::character varying NOT NULL,
+    rule text DEFAULT ''::text NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone,
+    CONSTRAINT collections_type_check CHECK (((type)::text = ANY (ARRAY['manual'::text, 'rule'::text])))
+);
+
+ALTER TABLE public.collections OWNER TO postgres;
+
+CREATE UNIQUE INDEX collections_slug_idx ON public.collections (slug) WHERE (status = 'active'::public.status_enum);
+
+CREATE TABLE public.collection_products (
+    collection_id integer NOT NULL REFERENCES public.collections(id) ON DELETE CASCADE,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    "position" integer DEFAULT 0 NOT NULL,
+    PRIMARY KEY (collection_id, product_id)
+);
+
+ALTER TABLE public.collection_products OWNER TO postgres;
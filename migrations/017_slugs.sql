@@ -0,0 +1,121 @@
+-- SEO slugs and meta fields for products and categories. Slugs are
+-- backfilled from the names; a duplicate gets -2, -3, ... appended, skipping
+-- any slug already in use. A trigger does the same for rows inserted later
+-- without a slug. When a slug changes the old one is kept in slug_redirects
+-- so existing links keep resolving.
+
+ALTER TABLE public.products
+    ADD COLUMN slug character varying(255),
+    ADD COLUMN meta_title character varying(255) DEFAULT ''::character varying NOT NULL,
+    ADD COLUMN meta_description text DEFAULT ''::text NOT NULL;
+
+ALTER TABLE public.categories
+    ADD COLUMN slug character varying(255),
+    ADD COLUMN meta_title character varying(255) DEFAULT ''::character varying NOT NULL,
+    ADD COLUMN meta_description text DEFAULT ''::text NOT NULL;
+
+UPDATE public.products
+SET slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE public.categories
+SET slug = TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(name), '[^a-z0-9]+', '-', 'g'));
+
+UPDATE public.products SET slug = 'product-' || id WHERE slug = '';
+
+UPDATE public.categories SET slug = 'category-' || id WHERE slug = '';
+
+DO $$
+DECLARE
+    r RECORD;
+    n integer;
+BEGIN
+    FOR r IN SELECT d.id, d.slug FROM public.products d
+        WHERE EXISTS (SELECT 1 FROM public.products o WHERE o.slug = d.slug AND o.id < d.id)
+        ORDER BY d.id
+    LOOP
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM public.products WHERE slug = r.slug || '-' || n) LOOP
+            n := n + 1;
+        END LOOP;
+        UPDATE public.products SET slug = r.slug || '-' || n WHERE id = r.id;
+    END LOOP;
+END $$;
+
+DO $$
+DECLARE
+    r RECORD;
+    n integer;
+BEGIN
+    FOR r IN SELECT d.id, d.slug FROM public.categories d
+        WHERE EXISTS (SELECT 1 FROM public.categories o WHERE o.slug = d.slug AND o.id < d.id)
+        ORDER BY d.id
+    LOOP
+        n := 2;
+        WHILE EXISTS (SELECT 1 FROM public.categories WHERE slug = r.slug || '-' || n) LOOP
+            n := n + 1;
+        END LOOP;
+        UPDATE public.categories SET slug = r.slug || '-' || n WHERE id = r.id;
+    END LOOP;
+END $$;
+
+ALTER TABLE public.products ALTER COLUMN slug SET NOT NULL;
+
+ALTER TABLE public.categories ALTER COLUMN slug SET NOT NULL;
+
+CREATE UNIQUE INDEX products_slug_idx ON public.products (slug);
+
+CREATE UNIQUE INDEX categories_slug_idx ON public.categories (slug);
+
+CREATE TABLE public.slug_redirects (
+    id SERIAL PRIMARY KEY,
+    entity_type character varying(20) NOT NULL,
+    entity_id integer NOT NULL,
+    slug character varying(255) NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+);
+
+ALTER TABLE public.slug_redirects OWNER TO postgres;
+
+CREATE UNIQUE INDEX slug_redirects_slug_idx ON public.slug_redirects (entity_type, slug);
+
+-- New rows without a slug get one from the name, deduplicated the same way
+-- as the backfill. A slug another record redirects from counts as taken.
+CREATE FUNCTION public.generate_slug() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+    base text;
+    candidate text;
+    taken boolean;
+    n integer := 2;
+BEGIN
+    IF COALESCE(NEW.slug, '') <> '' THEN
+        RETURN NEW;
+    END IF;
+    base := TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(COALESCE(NEW.name, '')), '[^a-z0-9]+', '-', 'g'));
+    IF base = '' THEN
+        base := TG_ARGV[0] || '-' || NEW.id;
+    END IF;
+    candidate := base;
+    LOOP
+        EXECUTE format('SELECT EXISTS (SELECT 1 FROM %I.%I WHERE slug = $1)', TG_TABLE_SCHEMA, TG_TABLE_NAME)
+            INTO taken USING candidate;
+        IF NOT taken THEN
+            SELECT EXISTS (SELECT 1 FROM public.slug_redirects WHERE entity_type = TG_ARGV[0] AND slug = candidate) INTO taken;
+        END IF;
+        EXIT WHEN NOT taken;
+        candidate := base || '-' || n;
+        n := n + 1;
+    END LOOP;
+    NEW.slug := candidate;
+    RETURN NEW;
+END;
+$$;
+
+ALTER FUNCTION public.generate_slug() OWNER TO postgres;
+
+CREATE TRIGGER products_generate_slug BEFORE INSERT ON public.products
+    FOR EACH ROW EXECUTE FUNCTION public.generate_slug('product');
+
+CREATE TRIGGER categories_generate_slug BEFORE INSERT ON public.categories
+    FOR EACH ROW EXECUTE FUNCTION public.generate_slug('category');
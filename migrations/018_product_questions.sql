@@ -0,0 +1,56 @@
+-- Product questions and answers. Questions and customer answers wait in the
+-- admin moderation queue like reviews; answers by admins are approved right
+-- away. Only admins and customers who bought the product can answer.
+
+CREATE TABLE public.product_questions (
+    id SERIAL PRIMARY KEY,
+    product_id integer NOT NULL REFERENCES public.products(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    question text NOT NULL,
+    moderation_status public.review_status_enum DEFAULT 'pending'::public.review_status_enum NOT NULL,
+    moderation_note text,
+    moderated_at timestamp without time zone,
+    upvote_count integer DEFAULT 0 NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone
+);
+
+ALTER TABLE public.product_questions OWNER TO postgres;
+
+CREATE INDEX product_questions_product_id_status_idx ON public.product_questions (product_id, moderation_status);
+
+CREATE TABLE public.product_answers (
+    id SERIAL PRIMARY KEY,
+    question_id integer NOT NULL REFERENCES public.product_questions(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    answer text NOT NULL,
+    is_admin boolean DEFAULT false NOT NULL,
+    is_verified_buyer boolean DEFAULT false NOT NULL,
+    moderation_status public.review_status_enum DEFAULT 'pending'::public.review_status_enum NOT NULL,
+    moderation_note text,
+    moderated_at timestamp without time zone,
+    upvote_count integer DEFAULT 0 NOT NULL,
+    status public.status_enum DEFAULT 'active'::public.status_enum NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone,
+    deleted_at timestamp without time zone
+);
+
+ALTER TABLE public.product_answers OWNER TO postgres;
+
+CREATE INDEX product_answers_question_id_status_idx ON public.product_answers (question_id, moderation_status);
+
+-- One upvote per user on a question or an answer
+CREATE TABLE public.question_votes (
+    id SERIAL PRIMARY KEY,
+    target_type character varying(10) NOT NULL,
+    target_id integer NOT NULL,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT question_votes_target_type_check CHECK (((target_type)::text = ANY (ARRAY['question'::text, 'answer'::text]))),
+    CONSTRAINT question_votes_target_user_key UNIQUE (target_type, target_id, user_id)
+);
+
+ALTER TABLE public.question_votes OWNER TO postgres;
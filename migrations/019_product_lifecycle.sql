@@ -0,0 +1,26 @@
+-- Publishing lifecycle. status stays the soft-delete flag; publish_status
+-- decides whether the storefront shows the product. Draft and scheduled
+-- products are being prepared, archived ones are withdrawn from sale but
+-- keep their order history. published_at drives the "new" flag.
+
+CREATE TYPE public.product_publish_enum AS ENUM (
+    'draft',
+    'scheduled',
+    'published',
+    'archived'
+);
+
+ALTER TYPE public.product_publish_enum OWNER TO postgres;
+
+ALTER TABLE public.products
+    ADD COLUMN publish_status public.product_publish_enum DEFAULT 'draft'::public.product_publish_enum NOT NULL,
+    ADD COLUMN publish_at timestamp without time zone,
+    ADD COLUMN published_at timestamp without time zone;
+
+-- Everything already in the catalog is live.
+UPDATE public.products SET publish_status = 'published', published_at = created_at;
+
+ALTER TABLE public.products
+    ADD CONSTRAINT products_publish_at_check CHECK (((publish_status <> 'scheduled'::public.product_publish_enum) OR (publish_at IS NOT NULL)));
+
+CREATE INDEX products_publish_due_idx ON public.products (publish_at) WHERE (publish_status = 'scheduled'::public.product_publish_enum);
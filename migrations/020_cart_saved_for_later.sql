@@ -0,0 +1,7 @@
+-- Saved for later. A saved line stays in the cart with its variant
+-- selection but is left out of the cart totals and of the order.
+
+ALTER TABLE public.cart_items
+    ADD COLUMN saved_for_later boolean DEFAULT false NOT NULL;
+
+CREATE INDEX cart_items_saved_idx ON public.cart_items (cart_id) WHERE (saved_for_later AND status = 'active'::public.status_enum);
@@ -0,0 +1,27 @@
+-- Abandoned carts. A cart left untouched gets one reminder per idle spell,
+-- optionally with a coupon only its owner can use. The reminder and its
+-- coupon are stored before the notification goes out and sent_at is set
+-- after, so a failed send is retried with the same coupon. A reminder is
+-- converted when the cart is checked out afterwards. Carts left far longer
+-- expire and the user starts a new one, which keeps the saved lines.
+
+ALTER TYPE public.cart_status_enum ADD VALUE IF NOT EXISTS 'expired';
+
+ALTER TABLE public.coupons
+    ADD COLUMN user_id character varying REFERENCES public.users(id) ON DELETE CASCADE;
+
+CREATE TABLE public.cart_reminders (
+    id SERIAL PRIMARY KEY,
+    cart_id integer NOT NULL REFERENCES public.carts(id) ON DELETE CASCADE,
+    user_id character varying NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+    coupon_id integer REFERENCES public.coupons(id) ON DELETE SET NULL,
+    cart_total numeric(10,2) DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    sent_at timestamp without time zone,
+    converted_at timestamp without time zone,
+    order_id integer REFERENCES public.orders(id) ON DELETE SET NULL
+);
+
+ALTER TABLE public.cart_reminders OWNER TO postgres;
+
+CREATE INDEX cart_reminders_cart_idx ON public.cart_reminders (cart_id, sent_at DESC);
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// AbandonedCart is an active cart left untouched, with the owner to remind.
+type AbandonedCart struct {
+	CartID       int
+	UserID       string
+	Name         string
+	Email        string
+	PhoneNumber  string
+	TotalAmount  int
+	TotalPrice   Money
+	CouponID     int
+	LastActivity time.Time
+}
+
+// CartReminder is a reminder for an abandoned cart. It is stored before it
+// is sent, so SentAt stays nil until the notification went out, and it is
+// converted once the cart is checked out afterwards.
+type CartReminder struct {
+	ID          int        `json:"id"`
+	CartID      int        `json:"cart_id"`
+	UserID      string     `json:"user_id"`
+	CouponID    int        `json:"coupon_id,omitempty"`
+	CartTotal   Money      `json:"cart_total"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	ConvertedAt *time.Time `json:"converted_at,omitempty"`
+	OrderID     int        `json:"order_id,omitempty"`
+}
+
+// AbandonedCartStats sums up the reminders sent since a point in time and
+// what came of them.
+type AbandonedCartStats struct {
+	Since            time.Time `json:"since"`
+	RemindersSent    int       `json:"reminders_sent"`
+	CouponsSent      int       `json:"coupons_sent"`
+	Converted        int       `json:"converted"`
+	CouponsRedeemed  int       `json:"coupons_redeemed"`
+	ConversionRate   float64   `json:"conversion_rate"`
+	RecoveredRevenue Money     `json:"recovered_revenue"`
+	ExpiredCarts     int       `json:"expired_carts"`
+}
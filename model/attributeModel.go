@@ -0,0 +1,71 @@
+package model
+
+// Data types of specification attributes.
+const (
+	AttributeTypeText    = "text"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+)
+
+// Attribute is a specification products of a category can have. An
+// attribute without a category applies to every category.
+type Attribute struct {
+	ID           int    `json:"id"`
+	CategoryID   int    `json:"category_id,omitempty"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	DataType     string `json:"data_type"`
+	Unit         string `json:"unit,omitempty"`
+	IsFilterable bool   `json:"is_filterable"`
+	DisplayOrder int    `json:"display_order"`
+}
+
+type AttributeDTO struct {
+	CategoryID   int    `json:"category_id"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	DataType     string `json:"data_type"`
+	Unit         string `json:"unit"`
+	IsFilterable *bool  `json:"is_filterable"`
+	DisplayOrder int    `json:"display_order"`
+}
+
+// ProductAttribute is a product's value for one attribute. Value is a
+// string, a number or a bool, following the attribute's data type.
+type ProductAttribute struct {
+	AttributeID int         `json:"attribute_id"`
+	Code        string      `json:"code"`
+	Name        string      `json:"name"`
+	DataType    string      `json:"data_type"`
+	Unit        string      `json:"unit,omitempty"`
+	Value       interface{} `json:"value"`
+}
+
+type ProductAttributeDTO struct {
+	AttributeID int         `json:"attribute_id"`
+	Value       interface{} `json:"value"`
+}
+
+// AttributeFilter narrows the product listing to products whose value of
+// the attribute is one of Values or, for numbers, within Min and Max.
+type AttributeFilter struct {
+	Code     string
+	DataType string
+	Values   []string
+	Min      *float64
+	Max      *float64
+}
+
+// Facet counts the listed products per value of a filterable attribute.
+// Number facets also carry the range of the values.
+type Facet struct {
+	Attribute Attribute    `json:"attribute"`
+	Values    []FacetValue `json:"values"`
+	Min       *float64     `json:"min,omitempty"`
+	Max       *float64     `json:"max,omitempty"`
+}
+
+type FacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
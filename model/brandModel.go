@@ -0,0 +1,23 @@
+package model
+
+type Brand struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	LogoURL     string `json:"logo_url"`
+	Description string `json:"description"`
+}
+
+// BrandDTO is the admin input for a brand. Without a slug one is made from
+// the name.
+type BrandDTO struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	LogoURL     string `json:"logo_url"`
+	Description string `json:"description"`
+}
+
+// ProductBrandDTO assigns a brand to a product; a null brand_id removes it.
+type ProductBrandDTO struct {
+	BrandID *int `json:"brand_id"`
+}
@@ -3,22 +3,43 @@ package model
 import "database/sql"
 
 type Cart struct {
-	ID          int        `json:"id"`
-	UserID      string     `json:"user_id"`
-	TotalAmount int        `json:"total_amount"`
-	TotalPrice  float64    `json:"total_price"`
-	Items       []CartItem `json:"cart_items"`
-	CartStatus  string     `json:"-"`
+	ID            int            `json:"id"`
+	UserID        string         `json:"user_id"`
+	TotalAmount   int            `json:"total_amount"`
+	TotalPrice    Money          `json:"total_price"`
+	Currency      string         `json:"currency,omitempty"`
+	SubTotal      Money          `json:"subtotal"`
+	DiscountTotal Money          `json:"discount_total"`
+	Discounts     []CartDiscount `json:"discounts,omitempty"`
+	Items         []CartItem     `json:"cart_items"`
+	SavedItems    []CartItem     `json:"saved_for_later,omitempty"`
+	CartStatus    string         `json:"-"`
+	CouponID      int            `json:"-"`
+	Coupon        *AppliedCoupon `json:"coupon,omitempty"`
 }
 
 type CartItem struct {
-	ID          int              `json:"id"`
-	CartID      int              `json:"cart_id,omitempty"`
-	ProductID   int              `json:"product_id,omitempty"`
-	Product     Product          `json:"product"`
-	Amount      int              `json:"amount"`
-	ItemVariant []CarttemVariant `json:"cart_item_variants,omitempty"`
-	SubTotal    float64          `json:"subtotal"`
+	ID              int                `json:"id"`
+	CartID          int                `json:"cart_id,omitempty"`
+	ProductID       int                `json:"product_id,omitempty"`
+	Product         Product            `json:"product"`
+	Amount          int                `json:"amount"`
+	ItemVariant     []CarttemVariant   `json:"cart_item_variants,omitempty"`
+	SubTotal        Money              `json:"subtotal"`
+	Discount        Money              `json:"discount,omitempty"`
+	Promotions      []AppliedPromotion `json:"promotions,omitempty"`
+	FlashSaleItemID int                `json:"-"`
+	SavedForLater   bool               `json:"-"`
+}
+
+// CartPricing is a priced cart: every line at its current price, with the
+// cart promotions evaluated across all lines.
+type CartPricing struct {
+	Items         []CartItem
+	SubTotal      Money
+	Discounts     []CartDiscount
+	DiscountTotal Money
+	Total         Money
 }
 
 type CarttemVariant struct {
@@ -28,7 +49,7 @@ type CarttemVariant struct {
 	Variant         Variant       `json:"variant"`
 	OptionID        sql.NullInt64 `json:"-"`
 	Option          VariantOption `json:"option"`
-	AdditionalPrice float64       `json:"-"`
+	AdditionalPrice Money         `json:"-"`
 }
 
 type CartItemDTO struct {
@@ -37,8 +58,42 @@ type CartItemDTO struct {
 	Amount    int                  `json:"amount,omitempty"`
 }
 
+// CartItemVariantsDTO replaces the variant selection of a cart line.
+type CartItemVariantsDTO struct {
+	Variant []CartItemVariantDTO `json:"variant"`
+}
+
 type CartItemVariantDTO struct {
-	VariantID       int     `json:"variant_id,omitempty"`
-	VariantOptionID int     `json:"variant_option_id,omitempty"`
-	AdditionalPrice float64 `json:"-"`
+	VariantID       int   `json:"variant_id,omitempty"`
+	VariantOptionID int   `json:"variant_option_id,omitempty"`
+	AdditionalPrice Money `json:"-"`
+}
+
+// Reasons a cart line is flagged when the cart is validated.
+const (
+	CartIssueUnavailable        = "unavailable"
+	CartIssueVariantUnavailable = "variant_unavailable"
+	CartIssueOutOfStock         = "out_of_stock"
+	CartIssuePriceChanged       = "price_changed"
+)
+
+// CartIssue flags a cart line that is no longer what the customer added.
+// Price changes are informational; the other reasons keep the cart from
+// being ordered.
+type CartIssue struct {
+	CartItemID  int    `json:"cart_item_id"`
+	ProductID   int    `json:"product_id"`
+	Reason      string `json:"reason"`
+	Message     string `json:"message"`
+	OldSubTotal Money  `json:"old_subtotal,omitempty"`
+	NewSubTotal Money  `json:"new_subtotal,omitempty"`
+}
+
+// CartValidation is a cart repriced with current data. Cart only holds the
+// lines that can be ordered.
+type CartValidation struct {
+	Valid   bool        `json:"valid"`
+	Issues  []CartIssue `json:"issues"`
+	Cart    Cart        `json:"cart"`
+	Pricing CartPricing `json:"-"`
 }
@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+const (
+	CartRuleBuyXGetY = "buy_x_get_y"
+	CartRuleBundle   = "bundle"
+	CartRuleTiered   = "tiered"
+
+	OrderPromotionSourcePromotion = "promotion"
+)
+
+// CartPromotion is a rule evaluated across all cart lines.
+//
+// buy_x_get_y: for every BuyQuantity units of ProductIDs, the next
+// GetQuantity cheapest units get DiscountValue percent off (100 is free).
+// bundle: when every product of ProductIDs is in the cart, each complete
+// set gets DiscountValue percent off.
+// tiered: the lines of ProductIDs get the percentage of the highest tier
+// reached by their combined quantity.
+type CartPromotion struct {
+	ID            int                 `json:"id"`
+	Name          string              `json:"name"`
+	RuleType      string              `json:"rule_type"`
+	BuyQuantity   int                 `json:"buy_quantity,omitempty"`
+	GetQuantity   int                 `json:"get_quantity,omitempty"`
+	DiscountValue float64             `json:"discount_value,omitempty"`
+	ProductIDs    []int               `json:"product_ids"`
+	Tiers         []CartPromotionTier `json:"tiers,omitempty"`
+	StartsAt      time.Time           `json:"starts_at"`
+	EndsAt        time.Time           `json:"ends_at"`
+}
+
+type CartPromotionTier struct {
+	MinQuantity   int     `json:"min_quantity"`
+	DiscountValue float64 `json:"discount_value"`
+}
+
+type CartPromotionDTO struct {
+	Name          string              `json:"name"`
+	RuleType      string              `json:"rule_type"`
+	BuyQuantity   int                 `json:"buy_quantity"`
+	GetQuantity   int                 `json:"get_quantity"`
+	DiscountValue float64             `json:"discount_value"`
+	ProductIDs    []int               `json:"product_ids"`
+	Tiers         []CartPromotionTier `json:"tiers"`
+	StartsAt      time.Time           `json:"starts_at"`
+	EndsAt        time.Time           `json:"ends_at"`
+}
+
+// CartDiscount is a cart promotion applied to the cart, with the part of
+// the discount each line received.
+type CartDiscount struct {
+	CartPromotionID int                `json:"cart_promotion_id"`
+	Name            string             `json:"name"`
+	RuleType        string             `json:"rule_type"`
+	Amount          Money              `json:"amount"`
+	Items           []CartDiscountItem `json:"items"`
+}
+
+type CartDiscountItem struct {
+	CartItemID int   `json:"cart_item_id"`
+	Amount     Money `json:"amount"`
+}
+
+// OrderPromotion is the snapshot of a promotion used by an order.
+type OrderPromotion struct {
+	ID          int    `json:"-"`
+	OrderID     int    `json:"-"`
+	OrderItemID int    `json:"order_item_id,omitempty"`
+	Source      string `json:"source"`
+	PromotionID int    `json:"promotion_id"`
+	Name        string `json:"name"`
+	Amount      Money  `json:"amount"`
+}
@@ -1,6 +1,9 @@
 package model
 
 type Category struct {
-	ID   int    `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
+	ID              int    `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Slug            string `json:"slug,omitempty"`
+	MetaTitle       string `json:"meta_title,omitempty"`
+	MetaDescription string `json:"meta_description,omitempty"`
 }
@@ -0,0 +1,35 @@
+package model
+
+const (
+	CollectionTypeManual = "manual"
+	CollectionTypeRule   = "rule"
+)
+
+// Collection groups products for merchandising. A manual collection lists
+// ProductIDs in display order; a rule collection matches products on Rule.
+type Collection struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Rule        string `json:"rule,omitempty"`
+	ProductIDs  []int  `json:"product_ids,omitempty"`
+}
+
+type CollectionDTO struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+	Rule        string `json:"rule"`
+	ProductIDs  []int  `json:"product_ids"`
+}
+
+// CollectionCondition is one comparison of a collection rule, such as
+// discount > 20.
+type CollectionCondition struct {
+	Field    string
+	Operator string
+	Value    string
+}
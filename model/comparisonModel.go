@@ -0,0 +1,19 @@
+package model
+
+// ProductComparison lays products side by side. Every row holds one value
+// per product, in the order of Products; a product without the field has a
+// null value.
+type ProductComparison struct {
+	Products []Product       `json:"products"`
+	Rows     []ComparisonRow `json:"rows"`
+}
+
+// ComparisonRow is one compared field. Differs is set when the products do
+// not all share the same value.
+type ComparisonRow struct {
+	Key     string        `json:"key"`
+	Label   string        `json:"label"`
+	Unit    string        `json:"unit,omitempty"`
+	Values  []interface{} `json:"values"`
+	Differs bool          `json:"differs"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+const CouponTypeFreeShipping = "free_shipping"
+
+// Coupon is a code that takes a discount off a cart. DiscountValue is a
+// percentage or, for fixed coupons, an amount; both are exact decimals.
+type Coupon struct {
+	ID            int       `json:"id"`
+	Code          string    `json:"code"`
+	Description   string    `json:"description,omitempty"`
+	CouponType    string    `json:"coupon_type"`
+	DiscountValue Money     `json:"discount_value"`
+	MaxDiscount   Money     `json:"max_discount,omitempty"`
+	MinSpend      Money     `json:"min_spend"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	UsageLimit    int       `json:"usage_limit,omitempty"`
+	PerUserLimit  int       `json:"per_user_limit,omitempty"`
+	TimesUsed     int       `json:"times_used"`
+	CategoryIDs   []int     `json:"category_ids,omitempty"`
+	UserID        string    `json:"user_id,omitempty"`
+}
+
+type CouponDTO struct {
+	Code          string    `json:"code"`
+	Description   string    `json:"description"`
+	CouponType    string    `json:"coupon_type"`
+	DiscountValue Money     `json:"discount_value"`
+	MaxDiscount   Money     `json:"max_discount"`
+	MinSpend      Money     `json:"min_spend"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	UsageLimit    int       `json:"usage_limit"`
+	PerUserLimit  int       `json:"per_user_limit"`
+	CategoryIDs   []int     `json:"category_ids"`
+}
+
+type ApplyCouponDTO struct {
+	Code string `json:"code"`
+}
+
+// AppliedCoupon is the coupon attached to a cart or order with the discount
+// it gives. Free shipping coupons only get an amount once shipping is known.
+type AppliedCoupon struct {
+	Code           string `json:"code"`
+	CouponType     string `json:"coupon_type"`
+	DiscountAmount Money  `json:"discount_amount"`
+	Message        string `json:"message,omitempty"`
+}
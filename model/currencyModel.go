@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Currency is a currency prices can be shown and charged in. Rate converts
+// from the base currency; amounts are rounded to Decimals places and, when
+// RoundingIncrement is set, to a multiple of it.
+type Currency struct {
+	Code              string     `json:"code"`
+	Name              string     `json:"name"`
+	Symbol            string     `json:"symbol"`
+	Rate              float64    `json:"rate"`
+	Decimals          int        `json:"decimals"`
+	RoundingIncrement Money      `json:"rounding_increment,omitempty"`
+	IsBase            bool       `json:"is_base"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+type CurrencyDTO struct {
+	Code              string  `json:"code"`
+	Name              string  `json:"name"`
+	Symbol            string  `json:"symbol"`
+	Rate              float64 `json:"rate"`
+	Decimals          *int    `json:"decimals"`
+	RoundingIncrement Money   `json:"rounding_increment"`
+}
@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	FlashSaleUpcoming = "upcoming"
+	FlashSaleActive   = "active"
+)
+
+type FlashSale struct {
+	ID               int             `json:"id"`
+	Name             string          `json:"name"`
+	StartsAt         time.Time       `json:"starts_at"`
+	EndsAt           time.Time       `json:"ends_at"`
+	SaleStatus       string          `json:"sale_status,omitempty"`
+	CountdownSeconds int64           `json:"countdown_seconds,omitempty"`
+	Items            []FlashSaleItem `json:"items"`
+}
+
+type FlashSaleItem struct {
+	ID               int       `json:"id"`
+	FlashSaleID      int       `json:"-"`
+	ProductID        int       `json:"product_id"`
+	ProductName      string    `json:"product_name,omitempty"`
+	Price            Money     `json:"price,omitempty"`
+	SalePrice        Money     `json:"sale_price"`
+	Allocation       int       `json:"allocation"`
+	Sold             int       `json:"-"`
+	Remaining        int       `json:"remaining"`
+	PerCustomerLimit int       `json:"per_customer_limit,omitempty"`
+	EndsAt           time.Time `json:"ends_at,omitempty"`
+}
+
+type FlashSaleDTO struct {
+	Name     string             `json:"name"`
+	StartsAt time.Time          `json:"starts_at"`
+	EndsAt   time.Time          `json:"ends_at"`
+	Items    []FlashSaleItemDTO `json:"items"`
+}
+
+type FlashSaleItemDTO struct {
+	ProductID        int   `json:"product_id"`
+	SalePrice        Money `json:"sale_price"`
+	Allocation       int   `json:"allocation"`
+	PerCustomerLimit int   `json:"per_customer_limit"`
+}
+
+// FlashSaleClaim is the quantity of a flash sale allocation an order takes.
+type FlashSaleClaim struct {
+	FlashSaleItemID int
+	Amount          int
+}
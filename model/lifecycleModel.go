@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	PublishStatusDraft     = "draft"
+	PublishStatusScheduled = "scheduled"
+	PublishStatusPublished = "published"
+	PublishStatusArchived  = "archived"
+)
+
+// LifecycleDTO moves a product through its publishing lifecycle. PublishAt
+// is required for, and only used by, the scheduled status.
+type LifecycleDTO struct {
+	PublishStatus string     `json:"publish_status"`
+	PublishAt     *time.Time `json:"publish_at"`
+}
+
+// ProductLifecycle is a product's publishing state as seen by admins.
+type ProductLifecycle struct {
+	ProductID     int        `json:"product_id"`
+	Name          string     `json:"name"`
+	PublishStatus string     `json:"publish_status"`
+	PublishAt     *time.Time `json:"publish_at,omitempty"`
+	PublishedAt   *time.Time `json:"published_at,omitempty"`
+}
@@ -0,0 +1,227 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount in ten-thousandths of a currency unit. Amounts in
+// the base currency are kept to the cent, like the numeric(10,2) columns
+// they are stored in; the two extra places carry converted amounts of
+// currencies with up to 4 decimals.
+//
+// Money is written to and read from the database and JSON as a decimal, so
+// it never passes through float64 on the way.
+type Money int64
+
+const (
+	moneyDecimals = 4
+	moneyScale    = 10000
+	// inputDecimals is the precision of amounts read from JSON
+	inputDecimals = 2
+)
+
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// NewMoney converts a float amount to the nearest ten-thousandth. It is only
+// meant for values that arrive as floats, such as exchange rate products.
+func NewMoney(amount float64) Money {
+	return Money(math.Round(amount * moneyScale))
+}
+
+// ParseMoney reads a decimal such as "12.50" or "-3" exactly. Places beyond
+// the fourth decimal are rounded half away from zero.
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	for _, part := range []string{whole, fraction} {
+		if strings.TrimLeft(part, "0123456789") != "" {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+		}
+	}
+
+	var units int64
+	if whole != "" {
+		var err error
+		units, err = strconv.ParseInt(whole, 10, 64)
+		if err != nil || units > math.MaxInt64/moneyScale {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+		}
+	}
+	roundUp := len(fraction) > moneyDecimals && fraction[moneyDecimals] >= '5'
+	if len(fraction) > moneyDecimals {
+		fraction = fraction[:moneyDecimals]
+	}
+	fraction += strings.Repeat("0", moneyDecimals-len(fraction))
+	places, _ := strconv.ParseInt(fraction, 10, 64)
+
+	amount := units*moneyScale + places
+	if roundUp {
+		amount++
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money(amount), nil
+}
+
+// Float64 returns the amount as a float, for ratios and display math only.
+func (m Money) Float64() float64 {
+	return float64(m) / moneyScale
+}
+
+// String formats the amount with as few decimals as it needs, e.g. "12.5".
+func (m Money) String() string {
+	sign := ""
+	amount := int64(m)
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	whole, places := amount/moneyScale, amount%moneyScale
+	if places == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%04d", places), "0")
+	return sign + strconv.FormatInt(whole, 10) + "." + fraction
+}
+
+// Round rounds to the given number of decimals, half away from zero.
+func (m Money) Round(decimals int) Money {
+	if decimals >= moneyDecimals {
+		return m
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	step := Money(1)
+	for i := decimals; i < moneyDecimals; i++ {
+		step *= 10
+	}
+	return m.RoundTo(step)
+}
+
+// RoundTo rounds to a multiple of step, half away from zero. A zero or
+// negative step leaves the amount as it is.
+func (m Money) RoundTo(step Money) Money {
+	if step <= 0 {
+		return m
+	}
+	if m < 0 {
+		return -(-m).RoundTo(step)
+	}
+	return (m + step/2) / step * step
+}
+
+// Mul multiplies the amount by a quantity.
+func (m Money) Mul(quantity int) Money {
+	return m * Money(quantity)
+}
+
+// Div divides the amount by a quantity, rounding half away from zero.
+func (m Money) Div(quantity int) Money {
+	if quantity == 0 {
+		return 0
+	}
+	if m < 0 {
+		return -(-m).Div(quantity)
+	}
+	return (m + Money(quantity)/2) / Money(quantity)
+}
+
+// Percent returns percent % of the amount, rounded once to the cent. The
+// percentage is taken to two decimals, like the numeric percentage columns.
+func (m Money) Percent(percent float64) Money {
+	basisPoints := Money(math.Round(percent * 100))
+	return (m * basisPoints).Div(1000000) * 100
+}
+
+// MulRate multiplies the amount by an exchange rate. The result is not
+// rounded; the caller rounds it the way the target currency is shown.
+func (m Money) MulRate(rate float64) Money {
+	return NewMoney(m.Float64() * rate)
+}
+
+// Min returns the smaller of the two amounts.
+func (m Money) Min(other Money) Money {
+	if other < m {
+		return other
+	}
+	return m
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal. Amounts sent by
+// clients are in the base currency, so more than 2 decimals is rejected
+// rather than rounded away unnoticed.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "null" || s == "" {
+		*m = 0
+		return nil
+	}
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMoney, s)
+		}
+		amount := NewMoney(f)
+		if amount != amount.Round(inputDecimals) {
+			return fmt.Errorf("%w: %s has more than %d decimals", ErrInvalidMoney, s, inputDecimals)
+		}
+		*m = amount
+		return nil
+	}
+	if _, fraction, _ := strings.Cut(s, "."); len(strings.TrimRight(fraction, "0")) > inputDecimals {
+		return fmt.Errorf("%w: %s has more than %d decimals", ErrInvalidMoney, s, inputDecimals)
+	}
+	amount, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// Scan reads a numeric column. NULL reads as zero.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+	case []byte:
+		amount, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = amount
+	case string:
+		amount, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = amount
+	case int64:
+		*m = Money(v * moneyScale)
+	case float64:
+		*m = NewMoney(v)
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidMoney, src)
+	}
+	return nil
+}
+
+// Value writes the amount as a decimal string for numeric columns.
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+const NotificationAbandonedCart = "abandoned_cart"
+
+// Notification is a message to a user. How it reaches them is up to the
+// notifier; Data carries the details a template would need.
+type Notification struct {
+	Type        string                 `json:"type"`
+	UserID      string                 `json:"user_id"`
+	Name        string                 `json:"name"`
+	Email       string                 `json:"email,omitempty"`
+	PhoneNumber string                 `json:"phone_number,omitempty"`
+	Subject     string                 `json:"subject"`
+	Message     string                 `json:"message"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
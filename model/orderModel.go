@@ -3,28 +3,39 @@ package model
 import "database/sql"
 
 type Order struct {
-	ID            int         `json:"id"`
-	UserID        string      `json:"-"`
-	AddressID     int         `json:"-"`
-	Address       Address     `json:"address"`
-	ShippingType  string      `json:"shipping_type"`
-	ShippingCost  float64     `json:"shipping_cost"`
-	PaymentMethod string      `json:"payment_method"`
-	TotalAmount   int         `json:"total_amount"`
-	TotalPrice    float64     `json:"total_price"`
-	OrderItems    []OrderItem `json:"order_items"`
-	OrderStatus   string      `json:"order_status"`
-	CartID        int         `json:"cart_id"`
+	ID                int              `json:"id"`
+	UserID            string           `json:"-"`
+	AddressID         int              `json:"-"`
+	Address           Address          `json:"address"`
+	ShippingType      string           `json:"shipping_type"`
+	ShippingCost      Money            `json:"shipping_cost"`
+	PaymentMethod     string           `json:"payment_method"`
+	TotalAmount       int              `json:"total_amount"`
+	TotalPrice        Money            `json:"total_price"`
+	CouponID          int              `json:"-"`
+	CouponCode        string           `json:"coupon_code,omitempty"`
+	Discount          Money            `json:"discount_amount"`
+	PromotionDiscount Money            `json:"promotion_discount"`
+	Currency          string           `json:"currency"`
+	ExchangeRate      float64          `json:"exchange_rate"`
+	CurrencyTotal     Money            `json:"currency_total"`
+	Promotions        []OrderPromotion `json:"promotions,omitempty"`
+	FlashSales        []FlashSaleClaim `json:"-"`
+	OrderItems        []OrderItem      `json:"order_items"`
+	OrderStatus       string           `json:"order_status"`
+	CartID            int              `json:"cart_id"`
 }
 
 type OrderDTO struct {
-	CartID        int     `json:"cart_id"`
-	AddressID     int     `json:"address_id"`
-	ShippingType  string  `json:"shipping_type"`
-	ShippingCost  float64 `json:"shipping_cost"`
-	PaymentMethod string  `json:"payment_method"`
-	TotalPrice    float64 `json:"total_price"`
-	TotalAmount   int     `json:"total_amount"`
+	CartID        int    `json:"cart_id"`
+	AddressID     int    `json:"address_id"`
+	ShippingType  string `json:"shipping_type"`
+	ShippingCost  Money  `json:"shipping_cost"`
+	PaymentMethod string `json:"payment_method"`
+	TotalPrice    Money  `json:"total_price"`
+	TotalAmount   int    `json:"total_amount"`
+	CouponCode    string `json:"coupon_code"`
+	Currency      string `json:"currency"`
 }
 
 type OrderItem struct {
@@ -34,7 +45,8 @@ type OrderItem struct {
 	Product    Product            `json:"product"`
 	Variants   []OrderItemVariant `json:"item_variants"`
 	Amount     int                `json:"amount"`
-	SubTotal   float64            `json:"subtotal"`
+	SubTotal   Money              `json:"subtotal"`
+	Discount   Money              `json:"discount"`
 	Review     string             `json:"review"`
 	Rating     float64            `json:"rating"`
 	Photos     []string           `json:"photos"`
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const (
+	PriceSourceProduct   = "product"
+	PriceSourcePromotion = "promotion"
+	PriceSourceFlashSale = "flash_sale"
+)
+
+// PriceHistory is a price a product was sold at from RecordedAt until the
+// next entry.
+type PriceHistory struct {
+	ID         int       `json:"id"`
+	ProductID  int       `json:"product_id"`
+	Price      Money     `json:"price"`
+	Discount   float64   `json:"discount"`
+	FinalPrice Money     `json:"final_price"`
+	Source     string    `json:"source"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
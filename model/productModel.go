@@ -1,32 +1,52 @@
 package model
 
 type Product struct {
-	ID                 int            `json:"id,omitempty"`
-	Name               string         `json:"name,omitempty"`
-	Description        string         `json:"description,omitempty"`
-	CategoryID         int            `json:"category_id,omitempty"`
-	Category           Category       `json:"category,omitempty"`
-	Price              float64        `json:"price,omitempty"`
-	Discount           float64        `json:"discount,omitempty"`
-	PriceAfterDiscount float64        `json:"price_after_discount"`
-	PhotoURL           string         `json:"photo_url,omitempty"`
-	IsNewProduct       bool           `json:"is_new_product,omitempty"`
-	HasVariant         bool           `json:"has_variant,omitempty"`
-	Rating             float64        `json:"rating,omitempty"`
-	TotalStock         int            `json:"total_stock,omitempty"`
-	Variant            []Variant      `json:"variants,omitempty"`
-	SpecialProduct     SpecialProduct `json:"special_products,omitempty"`
+	ID                 int                `json:"id,omitempty"`
+	Name               string             `json:"name,omitempty"`
+	Slug               string             `json:"slug,omitempty"`
+	MetaTitle          string             `json:"meta_title,omitempty"`
+	MetaDescription    string             `json:"meta_description,omitempty"`
+	Description        string             `json:"description,omitempty"`
+	CategoryID         int                `json:"category_id,omitempty"`
+	Category           Category           `json:"category,omitempty"`
+	BrandID            int                `json:"brand_id,omitempty"`
+	Brand              *Brand             `json:"brand,omitempty"`
+	Price              Money              `json:"price,omitempty"`
+	Discount           float64            `json:"discount,omitempty"`
+	PriceAfterDiscount Money              `json:"price_after_discount"`
+	LowestPrice30d     Money              `json:"lowest_price_30d,omitempty"`
+	Currency           string             `json:"currency,omitempty"`
+	Promotions         []AppliedPromotion `json:"promotions,omitempty"`
+	FlashSale          *FlashSaleItem     `json:"flash_sale,omitempty"`
+	PhotoURL           string             `json:"photo_url,omitempty"`
+	IsNewProduct       bool               `json:"is_new_product,omitempty"`
+	HasVariant         bool               `json:"has_variant,omitempty"`
+	Rating             float64            `json:"rating,omitempty"`
+	TotalStock         int                `json:"total_stock,omitempty"`
+	Variant            []Variant          `json:"variants,omitempty"`
+	Attributes         []ProductAttribute `json:"attributes,omitempty"`
+	Tags               []string           `json:"tags,omitempty"`
+	SpecialProduct     SpecialProduct     `json:"special_products,omitempty"`
+	PublishStatus      string             `json:"-"`
 	Detail             `json:"-"`
 }
 
 type ProductDTO struct {
 	Name       string     `json:"name"`
 	CategoryID int        `json:"category_id" validate:"required,gt 0"`
-	Price      float64    `json:"price" validate:"required,gt 0.0"`
+	BrandID    int        `json:"brand_id"`
+	Price      Money      `json:"price" validate:"required,gt=0"`
 	Discount   float64    `json:"discount"`
 	PhotoUrl   string     `json:"photo_url"`
 	HasVariant bool       `json:"has_variant"`
 	VariantDTO VariantDTO `json:"variant"`
+	// Attributes filters the product listing by specification values.
+	Attributes []AttributeFilter `json:"-"`
+	Tag        string            `json:"-"`
+	// CollectionID narrows the listing to a manual collection, in its order;
+	// Conditions to the products matching a rule collection.
+	CollectionID int                   `json:"-"`
+	Conditions   []CollectionCondition `json:"-"`
 }
 
 type SpecialProduct struct {
@@ -34,12 +54,10 @@ type SpecialProduct struct {
 	IsNewProduct  bool `json:"is_new_product,omitempty"`
 }
 
-type WeeklyPromo struct {
-	ID            int     `json:"id,omitempty"`
-	ProductID     int     `json:"-"`
-	Product       Product `json:"product,omitempty"`
-	PromoDiscount float64 `json:"promo_discount,omitempty"`
-	PromoPrice    float64 `json:"promo_price,omitempty"`
-	StartDate     string  `json:"start_date"`
-	EndDate       string  `json:"end_date"`
+// BestSeller is a product's sales rank over the last WindowDays days.
+type BestSeller struct {
+	Product    Product `json:"product"`
+	WindowDays int     `json:"window_days"`
+	Quantity   int     `json:"quantity_sold"`
+	Rank       int     `json:"rank"`
 }
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type RecentlyViewed struct {
+	Product  Product   `json:"product"`
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+type TrendingProduct struct {
+	Product   Product `json:"product"`
+	ViewCount int     `json:"view_count"`
+}
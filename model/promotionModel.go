@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+const (
+	PromotionTargetAll      = "all"
+	PromotionTargetCategory = "category"
+	PromotionTargetProduct  = "product"
+
+	DiscountTypePercentage = "percentage"
+	DiscountTypeFixed      = "fixed"
+)
+
+// Promotion discounts matching products. DiscountValue is a percentage or,
+// for fixed promotions, an amount off each unit; both are exact decimals.
+type Promotion struct {
+	ID            int       `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description,omitempty"`
+	TargetType    string    `json:"target_type"`
+	TargetID      int       `json:"target_id,omitempty"`
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue Money     `json:"discount_value"`
+	Priority      int       `json:"priority"`
+	Stackable     bool      `json:"stackable"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+	Detail        `json:"-"`
+}
+
+type PromotionDTO struct {
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	TargetType    string    `json:"target_type"`
+	TargetID      int       `json:"target_id"`
+	DiscountType  string    `json:"discount_type"`
+	DiscountValue Money     `json:"discount_value"`
+	Priority      int       `json:"priority"`
+	Stackable     bool      `json:"stackable"`
+	StartsAt      time.Time `json:"starts_at"`
+	EndsAt        time.Time `json:"ends_at"`
+}
+
+// AppliedPromotion is a promotion that was used to price an item, with the
+// discount it gave per unit.
+type AppliedPromotion struct {
+	PromotionID   int    `json:"promotion_id"`
+	Name          string `json:"name"`
+	DiscountType  string `json:"discount_type"`
+	DiscountValue Money  `json:"discount_value"`
+	Amount        Money  `json:"amount"`
+}
+
+// PriceBreakdown is the result of the pricing engine for one line.
+type PriceBreakdown struct {
+	UnitPrice       Money              `json:"unit_price"`
+	DiscountedPrice Money              `json:"discounted_price"`
+	FinalUnitPrice  Money              `json:"final_unit_price"`
+	Amount          int                `json:"amount"`
+	SubTotal        Money              `json:"subtotal"`
+	Promotions      []AppliedPromotion `json:"promotions,omitempty"`
+	FlashSaleItemID int                `json:"flash_sale_item_id,omitempty"`
+}
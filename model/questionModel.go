@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+const (
+	QuestionVoteQuestion = "question"
+	QuestionVoteAnswer   = "answer"
+
+	QuestionSortUpvotes = "upvotes"
+	QuestionSortRecent  = "recent"
+)
+
+// Question is a customer question on a product. Moderation uses the review
+// statuses.
+type Question struct {
+	ID               int       `json:"id"`
+	ProductID        int       `json:"product_id"`
+	UserID           string    `json:"-"`
+	UserName         string    `json:"user_name,omitempty"`
+	Question         string    `json:"question"`
+	ModerationStatus string    `json:"moderation_status,omitempty"`
+	ModerationNote   string    `json:"moderation_note,omitempty"`
+	UpvoteCount      int       `json:"upvote_count"`
+	Answers          []Answer  `json:"answers,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type Answer struct {
+	ID               int       `json:"id"`
+	QuestionID       int       `json:"question_id"`
+	UserID           string    `json:"-"`
+	UserName         string    `json:"user_name,omitempty"`
+	Answer           string    `json:"answer"`
+	IsAdmin          bool      `json:"is_admin"`
+	IsVerifiedBuyer  bool      `json:"is_verified_buyer"`
+	ModerationStatus string    `json:"moderation_status,omitempty"`
+	ModerationNote   string    `json:"moderation_note,omitempty"`
+	UpvoteCount      int       `json:"upvote_count"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type QuestionDTO struct {
+	Question string `json:"question"`
+}
+
+type AnswerDTO struct {
+	Answer string `json:"answer"`
+}
+
+type QuestionFilter struct {
+	ProductID        int
+	ModerationStatus string
+	SortBy           string
+}
+
+type QuestionModerationDTO struct {
+	ModerationStatus string `json:"moderation_status"`
+	Note             string `json:"note"`
+}
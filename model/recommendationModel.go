@@ -1,18 +1,51 @@
 package model
 
+import "time"
+
 type Recommendation struct {
-	ID            int     `json:"id,omitempty"`
-	Product       Product `json:"product"`
-	IsRecommended bool    `json:"is_recommended,omitempty"`
-	SetInBanner   bool    `json:"set_in_banner,omitempty"`
-	Title         string  `json:"title"`
-	Subtitle      string  `json:"subtitle"`
-	PhotoUrl      string  `json:"photo_url"`
-	PathUrl       string  `json:"path_url"`
+	ID            int        `json:"id,omitempty"`
+	Product       Product    `json:"product"`
+	IsRecommended bool       `json:"is_recommended,omitempty"`
+	SetInBanner   bool       `json:"set_in_banner,omitempty"`
+	Title         string     `json:"title"`
+	Subtitle      string     `json:"subtitle"`
+	PhotoUrl      string     `json:"photo_url"`
+	PathUrl       string     `json:"path_url"`
+	DisplayOrder  int        `json:"display_order"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
 	Detail        `json:"-"`
 }
 
+// RecommendationDTO filters the public lists and carries the admin input.
+// Leaving starts_at or ends_at out means the entry has no start or end.
 type RecommendationDTO struct {
-	IsRecommended bool `json:"is_recommended,omitempty"`
-	SetInBanner   bool `json:"set_in_banner,omitempty"`
+	ProductID     int        `json:"product_id"`
+	IsRecommended bool       `json:"is_recommended,omitempty"`
+	SetInBanner   bool       `json:"set_in_banner,omitempty"`
+	Title         string     `json:"title"`
+	Subtitle      string     `json:"subtitle"`
+	PhotoUrl      string     `json:"photo_url"`
+	PathUrl       string     `json:"path_url"`
+	DisplayOrder  int        `json:"display_order"`
+	StartsAt      *time.Time `json:"starts_at"`
+	EndsAt        *time.Time `json:"ends_at"`
+}
+
+// Reasons a product shows up in a personalised feed.
+const (
+	RecommendationReasonBoughtTogether = "bought_together"
+	RecommendationReasonCategory       = "category"
+)
+
+// ProductAssociation is a product often bought in the same order as
+// another one. Confidence is the share of orders with the first product
+// that also have this one; lift is how much more likely that is than
+// buying this product at all.
+type ProductAssociation struct {
+	Product      Product `json:"product"`
+	SupportCount int     `json:"support_count"`
+	Confidence   float64 `json:"confidence"`
+	Lift         float64 `json:"lift"`
 }
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+const (
+	ReviewStatusPending  = "pending"
+	ReviewStatusApproved = "approved"
+	ReviewStatusRejected = "rejected"
+
+	ReviewVoteHelpful = "helpful"
+	ReviewVoteReport  = "report"
+
+	ReviewSortHelpful = "helpful"
+	ReviewSortRecent  = "recent"
+)
+
+type Review struct {
+	ID             int       `json:"id"`
+	OrderItemID    int       `json:"-"`
+	ProductID      int       `json:"product_id"`
+	UserID         string    `json:"-"`
+	UserName       string    `json:"user_name,omitempty"`
+	Rating         float64   `json:"rating"`
+	Comment        string    `json:"comment"`
+	ReviewStatus   string    `json:"review_status,omitempty"`
+	ModerationNote string    `json:"moderation_note,omitempty"`
+	HelpfulCount   int       `json:"helpful_count"`
+	ReportCount    int       `json:"report_count,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ReviewDTO struct {
+	OrderItemID int     `json:"order_item_id"`
+	Rating      float64 `json:"rating"`
+	Comment     string  `json:"comment"`
+}
+
+type ReviewFilter struct {
+	ProductID    int
+	ReviewStatus string
+	SortBy       string
+}
+
+type ReviewModerationDTO struct {
+	ReviewStatus string `json:"review_status"`
+	Note         string `json:"note"`
+}
+
+type ReviewReportDTO struct {
+	Reason string `json:"reason"`
+}
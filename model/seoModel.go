@@ -0,0 +1,25 @@
+package model
+
+const (
+	SlugEntityProduct  = "product"
+	SlugEntityCategory = "category"
+)
+
+// SEODTO renames a product or category and sets its storefront metadata. An
+// empty slug is generated from the name.
+type SEODTO struct {
+	Name            string `json:"name"`
+	Slug            string `json:"slug"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+}
+
+// SEO is the stored slug and metadata of a product or category.
+type SEO struct {
+	EntityType      string `json:"-"`
+	EntityID        int    `json:"id"`
+	Name            string `json:"name"`
+	Slug            string `json:"slug"`
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+}
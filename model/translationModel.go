@@ -0,0 +1,56 @@
+package model
+
+// Entities and fields that can be translated.
+const (
+	TranslationEntityProduct        = "product"
+	TranslationEntityCategory       = "category"
+	TranslationEntityVariant        = "variant"
+	TranslationEntityVariantOption  = "variant_option"
+	TranslationEntityRecommendation = "recommendation"
+
+	TranslationFieldName          = "name"
+	TranslationFieldDescription   = "description"
+	TranslationFieldAttributeName = "attribute_name"
+	TranslationFieldOptionValue   = "option_value"
+	TranslationFieldTitle         = "title"
+	TranslationFieldSubtitle      = "subtitle"
+)
+
+// Translation overrides one field of one entity in a locale.
+type Translation struct {
+	EntityType string `json:"entity_type"`
+	EntityID   int    `json:"entity_id"`
+	Locale     string `json:"locale"`
+	Field      string `json:"field"`
+	Value      string `json:"value"`
+}
+
+// ProductTranslation is a product's content in one locale, with its
+// variant attribute names and option values. An empty field has no
+// translation and falls back to the default text.
+type ProductTranslation struct {
+	Locale      string               `json:"locale"`
+	Name        string               `json:"name"`
+	Description string               `json:"description"`
+	Variants    []VariantTranslation `json:"variants,omitempty"`
+}
+
+type VariantTranslation struct {
+	ID            int                        `json:"id"`
+	AttributeName string                     `json:"atribute_name"`
+	Options       []VariantOptionTranslation `json:"variant_options,omitempty"`
+}
+
+type VariantOptionTranslation struct {
+	ID          int    `json:"id"`
+	OptionValue string `json:"option_value"`
+}
+
+// ContentTranslation is a category's or banner's content in one locale.
+// Categories use name; recommendations use title and subtitle.
+type ContentTranslation struct {
+	Locale   string `json:"locale"`
+	Name     string `json:"name,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
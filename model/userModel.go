@@ -8,6 +8,7 @@ type User struct {
 	Email          sql.NullString `json:"email"`
 	PasswordHashed string         `json:"-"`
 	PhoneNumber    sql.NullString `json:"phone_number"`
+	Role           string         `json:"role,omitempty"`
 	// Wishlist       Wishlist       `json:"wishlist,omitempty"`
 	Detail `json:"-"`
 }
@@ -17,3 +18,8 @@ type UserDTO struct {
 	Password           string `json:"password" validate:"min=8"`
 	EmailOrPhoneNumber string `json:"email_or_phone_number"`
 }
+
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
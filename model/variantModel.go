@@ -9,11 +9,11 @@ type Variant struct {
 }
 
 type VariantOption struct {
-	ID              int     `json:"id,omitempty"`
-	VariantID       int     `json:"variant_id,omitempty"`
-	OptionValue     string  `json:"option_value,omitempty"`
-	AdditionalPrice float64 `json:"additional_price,omitempty"`
-	Stock           int     `json:"stock,omitempty"`
+	ID              int    `json:"id,omitempty"`
+	VariantID       int    `json:"variant_id,omitempty"`
+	OptionValue     string `json:"option_value,omitempty"`
+	AdditionalPrice Money  `json:"additional_price,omitempty"`
+	Stock           int    `json:"stock,omitempty"`
 	Detail          `json:"-"`
 }
 
@@ -24,8 +24,8 @@ type VariantDTO struct {
 }
 
 type VariantOptionDTO struct {
-	VariantID       int     `json:"variant_id"`
-	OptionValue     string  `json:"option_value"`
-	AdditionalPrice float64 `json:"additional_price"`
-	Stock           int     `json:"stock"`
+	VariantID       int    `json:"variant_id"`
+	OptionValue     string `json:"option_value"`
+	AdditionalPrice Money  `json:"additional_price"`
+	Stock           int    `json:"stock"`
 }
@@ -0,0 +1,58 @@
+package notify
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+// Notifier delivers notifications to users. Services build the message;
+// the notifier decides the channel.
+type Notifier interface {
+	Notify(notification model.Notification) error
+}
+
+// New returns the notifier picked by the configured driver. Only the file
+// driver exists so far; an unknown driver falls back to it.
+func New(config util.NotificationConfig, logger *zap.Logger) Notifier {
+	if config.Driver != "file" {
+		logger.Warn("unknown notification driver, using file", zap.String("driver", config.Driver))
+	}
+	return NewFileNotifier(config.File)
+}
+
+// FileNotifier appends every notification to a file as a line of JSON. It
+// stands in for an email or push provider.
+type FileNotifier struct {
+	Path string
+	mu   *sync.Mutex
+}
+
+func NewFileNotifier(path string) FileNotifier {
+	return FileNotifier{Path: path, mu: &sync.Mutex{}}
+}
+
+func (n FileNotifier) Notify(notification model.Notification) error {
+	line, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(n.Path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(n.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
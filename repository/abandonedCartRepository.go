@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type AbandonedCartRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewAbandonedCartRepository(db *sql.DB, logger *zap.Logger) AbandonedCartRepository {
+	return AbandonedCartRepository{DB: db, Logger: logger}
+}
+
+// GetAbandoned returns the active carts with items last touched between
+// activeAfter and idleBefore that have not been reminded since. A cart
+// whose reminder was created but not sent is returned again.
+func (repo AbandonedCartRepository) GetAbandoned(idleBefore, activeAfter time.Time) ([]model.AbandonedCart, error) {
+	sqlStatement := `SELECT c.id, c.user_id, u.name, COALESCE(u.email, ''), COALESCE(u.phone_number, ''), c.total_amount, c.total_price,
+			COALESCE(c.coupon_id, 0), COALESCE(c.updated_at, c.created_at)
+		FROM carts c JOIN users u ON u.id = c.user_id
+		WHERE c.status = 'active' AND c.cart_status = 'active' AND c.total_amount > 0
+			AND COALESCE(c.updated_at, c.created_at) < $1 AND COALESCE(c.updated_at, c.created_at) >= $2
+			AND NOT EXISTS (SELECT 1 FROM cart_reminders cr WHERE cr.cart_id = c.id AND cr.sent_at >= COALESCE(c.updated_at, c.created_at))
+		ORDER BY c.id`
+	rows, err := repo.DB.Query(sqlStatement, idleBefore, activeAfter)
+	if err != nil {
+		repo.Logger.Error("Error retrieving abandoned carts", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "GetAbandoned"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var carts []model.AbandonedCart
+	for rows.Next() {
+		var cart model.AbandonedCart
+		err := rows.Scan(&cart.CartID, &cart.UserID, &cart.Name, &cart.Email, &cart.PhoneNumber, &cart.TotalAmount, &cart.TotalPrice,
+			&cart.CouponID, &cart.LastActivity)
+		if err != nil {
+			repo.Logger.Error("Error scanning abandoned cart", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "GetAbandoned"))
+			return nil, err
+		}
+		carts = append(carts, cart)
+	}
+	return carts, rows.Err()
+}
+
+// ExpireIdle expires the active carts last touched before the given time
+// and returns how many there were. Saved for later lines move to a new cart
+// of the owner, so they are not lost with the expired one.
+func (repo AbandonedCartRepository) ExpireIdle(before time.Time) (int64, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+		return 0, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `UPDATE carts SET cart_status = 'expired', updated_at = NOW()
+		WHERE status = 'active' AND cart_status = 'active' AND COALESCE(updated_at, created_at) < $1
+		RETURNING id`
+	rows, err := tx.Query(sqlStatement, before)
+	if err != nil {
+		repo.Logger.Error("Failed to expire carts", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+		return 0, err
+	}
+	var cartIDs []int
+	for rows.Next() {
+		var cartID int
+		if err = rows.Scan(&cartID); err != nil {
+			rows.Close()
+			repo.Logger.Error("Error scanning expired cart", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+			return 0, err
+		}
+		cartIDs = append(cartIDs, cartID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		repo.Logger.Error("Error reading expired carts", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+		return 0, err
+	}
+
+	for _, cartID := range cartIDs {
+		if err = carryOverSavedItems(tx, cartID); err != nil {
+			repo.Logger.Error("Failed to move saved cart items", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "ExpireIdle"))
+		return 0, err
+	}
+	return int64(len(cartIDs)), nil
+}
+
+// GetPendingReminder returns the reminder of the cart that was created but
+// not sent yet, or a zero reminder.
+func (repo AbandonedCartRepository) GetPendingReminder(cartID int) (model.CartReminder, error) {
+	var reminder model.CartReminder
+	sqlStatement := `SELECT id, cart_id, user_id, COALESCE(coupon_id, 0), cart_total, created_at
+		FROM cart_reminders WHERE cart_id = $1 AND sent_at IS NULL ORDER BY id DESC LIMIT 1`
+	err := repo.DB.QueryRow(sqlStatement, cartID).Scan(&reminder.ID, &reminder.CartID, &reminder.UserID, &reminder.CouponID,
+		&reminder.CartTotal, &reminder.CreatedAt)
+	if err == sql.ErrNoRows {
+		return reminder, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving pending cart reminder", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "GetPendingReminder"))
+		return reminder, err
+	}
+	return reminder, nil
+}
+
+// CreateReminder stores a reminder that is yet to be sent, together with
+// its coupon when one is given, and attaches the coupon to the cart. The
+// cart's updated_at is left alone, so the reminder does not count as
+// activity.
+func (repo AbandonedCartRepository) CreateReminder(reminder model.CartReminder, coupon *model.Coupon) (model.CartReminder, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+		return reminder, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+			tx.Rollback()
+		}
+	}()
+
+	if coupon != nil {
+		*coupon, err = insertCoupon(tx, *coupon)
+		if err != nil {
+			repo.Logger.Error("Failed to create reminder coupon", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+			return reminder, err
+		}
+		reminder.CouponID = coupon.ID
+		_, err = tx.Exec(`UPDATE carts SET coupon_id = $2 WHERE id = $1`, reminder.CartID, reminder.CouponID)
+		if err != nil {
+			repo.Logger.Error("Failed to attach reminder coupon", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+			return reminder, err
+		}
+	}
+
+	sqlStatement := `INSERT INTO cart_reminders (cart_id, user_id, coupon_id, cart_total) VALUES ($1, $2, NULLIF($3, 0), $4) RETURNING id, created_at`
+	err = tx.QueryRow(sqlStatement, reminder.CartID, reminder.UserID, reminder.CouponID, reminder.CartTotal).Scan(&reminder.ID, &reminder.CreatedAt)
+	if err != nil {
+		repo.Logger.Error("Failed to create cart reminder", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+		return reminder, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "CreateReminder"))
+		return reminder, err
+	}
+	return reminder, nil
+}
+
+// MarkSent records that the reminder went out.
+func (repo AbandonedCartRepository) MarkSent(reminderID int) error {
+	_, err := repo.DB.Exec(`UPDATE cart_reminders SET sent_at = NOW() WHERE id = $1`, reminderID)
+	if err != nil {
+		repo.Logger.Error("Failed to mark cart reminder sent", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "MarkSent"))
+		return err
+	}
+	return nil
+}
+
+// MarkConverted credits the order to the latest sent reminder of the cart,
+// if any.
+func (repo AbandonedCartRepository) MarkConverted(cartID, orderID int) error {
+	sqlStatement := `UPDATE cart_reminders SET converted_at = NOW(), order_id = $2
+		WHERE id = (SELECT id FROM cart_reminders WHERE cart_id = $1 AND sent_at IS NOT NULL AND converted_at IS NULL ORDER BY sent_at DESC LIMIT 1)`
+	_, err := repo.DB.Exec(sqlStatement, cartID, orderID)
+	if err != nil {
+		repo.Logger.Error("Failed to mark cart reminder converted", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "MarkConverted"))
+		return err
+	}
+	return nil
+}
+
+// GetStats sums up the reminders sent since the given time. A coupon is
+// redeemed when the converting order used the coupon of the reminder.
+func (repo AbandonedCartRepository) GetStats(since time.Time) (model.AbandonedCartStats, error) {
+	stats := model.AbandonedCartStats{Since: since}
+	sqlStatement := `SELECT COUNT(*), COUNT(cr.coupon_id), COUNT(cr.converted_at),
+			COUNT(*) FILTER (WHERE cr.converted_at IS NOT NULL AND o.coupon_id = cr.coupon_id),
+			COALESCE(SUM(o.total_price) FILTER (WHERE cr.converted_at IS NOT NULL), 0)
+		FROM cart_reminders cr LEFT JOIN orders o ON o.id = cr.order_id
+		WHERE cr.sent_at >= $1`
+	err := repo.DB.QueryRow(sqlStatement, since).Scan(&stats.RemindersSent, &stats.CouponsSent, &stats.Converted,
+		&stats.CouponsRedeemed, &stats.RecoveredRevenue)
+	if err != nil {
+		repo.Logger.Error("Failed to get cart reminder stats", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "GetStats"))
+		return stats, err
+	}
+
+	sqlStatement = `SELECT COUNT(*) FROM carts WHERE status = 'active' AND cart_status = 'expired' AND updated_at >= $1`
+	err = repo.DB.QueryRow(sqlStatement, since).Scan(&stats.ExpiredCarts)
+	if err != nil {
+		repo.Logger.Error("Failed to count expired carts", zap.Error(err), zap.String("Repository", "AbandonedCart"), zap.String("Function", "GetStats"))
+		return stats, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type AttributeRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewAttributeRepository(db *sql.DB, logger *zap.Logger) AttributeRepository {
+	return AttributeRepository{DB: db, Logger: logger}
+}
+
+const attributeColumns = `id, COALESCE(category_id, 0), code, name, data_type, unit, is_filterable, display_order`
+
+// GetAll returns the attributes of a category, including the ones shared by
+// every category, or all attributes when categoryID is 0.
+func (repo AttributeRepository) GetAll(categoryID int) ([]model.Attribute, error) {
+	sqlStatement := `SELECT ` + attributeColumns + ` FROM attributes
+		WHERE status = 'active' AND ($1 = 0 OR category_id IS NULL OR category_id = $1)
+		ORDER BY display_order, id`
+	return repo.query("GetAll", sqlStatement, categoryID)
+}
+
+func (repo AttributeRepository) GetByID(id int) (model.Attribute, error) {
+	sqlStatement := `SELECT ` + attributeColumns + ` FROM attributes WHERE id = $1 AND status = 'active'`
+	attributes, err := repo.query("GetByID", sqlStatement, id)
+	if err != nil || len(attributes) == 0 {
+		return model.Attribute{}, err
+	}
+	return attributes[0], nil
+}
+
+// GetByCodes returns the attributes with the given codes. The same code can
+// exist in several categories.
+func (repo AttributeRepository) GetByCodes(codes []string) ([]model.Attribute, error) {
+	sqlStatement := `SELECT ` + attributeColumns + ` FROM attributes WHERE code = ANY($1) AND status = 'active' ORDER BY display_order, id`
+	return repo.query("GetByCodes", sqlStatement, pq.Array(codes))
+}
+
+func (repo AttributeRepository) Create(attributeInput model.Attribute) (model.Attribute, error) {
+	sqlStatement := `INSERT INTO attributes (category_id, code, name, data_type, unit, is_filterable, display_order)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6, $7) RETURNING id`
+	err := repo.DB.QueryRow(sqlStatement, attributeInput.CategoryID, attributeInput.Code, attributeInput.Name, attributeInput.DataType,
+		attributeInput.Unit, attributeInput.IsFilterable, attributeInput.DisplayOrder).Scan(&attributeInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "Create"))
+		return attributeInput, err
+	}
+	return attributeInput, nil
+}
+
+// Update changes an attribute. The data type cannot change, as the stored
+// values would no longer match it.
+func (repo AttributeRepository) Update(attributeInput model.Attribute) error {
+	sqlStatement := `UPDATE attributes SET code = $2, name = $3, unit = $4, is_filterable = $5, display_order = $6, updated_at = NOW()
+		WHERE id = $1 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, attributeInput.ID, attributeInput.Code, attributeInput.Name, attributeInput.Unit,
+		attributeInput.IsFilterable, attributeInput.DisplayOrder)
+	if err != nil {
+		repo.Logger.Error("Failed to update attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "Update"))
+		return err
+	}
+	return nil
+}
+
+func (repo AttributeRepository) Delete(id int) error {
+	sqlStatement := `UPDATE attributes SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+// GetProductValues returns the product's values of active attributes.
+func (repo AttributeRepository) GetProductValues(productID int) ([]model.ProductAttribute, error) {
+	sqlStatement := `SELECT a.id, a.code, a.name, a.data_type, a.unit, v.value_text, v.value_number, v.value_boolean
+		FROM product_attribute_values v
+		JOIN attributes a ON a.id = v.attribute_id AND a.status = 'active'
+		WHERE v.product_id = $1
+		ORDER BY a.display_order, a.id`
+	rows, err := repo.DB.Query(sqlStatement, productID)
+	if err != nil {
+		repo.Logger.Error("Error retrieving product attributes", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "GetProductValues"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []model.ProductAttribute
+	for rows.Next() {
+		var value model.ProductAttribute
+		var text sql.NullString
+		var number sql.NullFloat64
+		var boolean sql.NullBool
+		err := rows.Scan(&value.AttributeID, &value.Code, &value.Name, &value.DataType, &value.Unit, &text, &number, &boolean)
+		if err != nil {
+			repo.Logger.Error("Error scanning product attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "GetProductValues"))
+			return nil, err
+		}
+		switch {
+		case text.Valid:
+			value.Value = text.String
+		case number.Valid:
+			value.Value = number.Float64
+		case boolean.Valid:
+			value.Value = boolean.Bool
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// SaveProductValues replaces every attribute value of a product in one
+// transaction. Value is written to the column of the attribute's type.
+func (repo AttributeRepository) SaveProductValues(productID int, values []model.ProductAttribute) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "SaveProductValues"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "SaveProductValues"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`DELETE FROM product_attribute_values WHERE product_id = $1`, productID)
+	if err != nil {
+		repo.Logger.Error("Failed to delete product attributes", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "SaveProductValues"))
+		return err
+	}
+
+	sqlStatement := `INSERT INTO product_attribute_values (product_id, attribute_id, value_text, value_number, value_boolean) VALUES ($1, $2, $3, $4, $5)`
+	for _, value := range values {
+		var text sql.NullString
+		var number sql.NullFloat64
+		var boolean sql.NullBool
+		switch v := value.Value.(type) {
+		case string:
+			text = sql.NullString{String: v, Valid: true}
+		case float64:
+			number = sql.NullFloat64{Float64: v, Valid: true}
+		case bool:
+			boolean = sql.NullBool{Bool: v, Valid: true}
+		}
+		_, err = tx.Exec(sqlStatement, productID, value.AttributeID, text, number, boolean)
+		if err != nil {
+			repo.Logger.Error("Failed to insert product attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "SaveProductValues"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "SaveProductValues"))
+		return err
+	}
+	return nil
+}
+
+// GetFacetValues counts the active products matching the filter per value
+// of the given attributes.
+func (repo AttributeRepository) GetFacetValues(attributeIDs []int, productFilter model.ProductDTO) (map[int][]model.FacetValue, error) {
+	facets := make(map[int][]model.FacetValue)
+	if len(attributeIDs) == 0 {
+		return facets, nil
+	}
+	args := []interface{}{pq.Array(attributeIDs)}
+	sqlStatement := `SELECT v.attribute_id, COALESCE(v.value_text, v.value_number::float8::text, v.value_boolean::text) AS value, COUNT(*)
+		FROM product_attribute_values v
+		JOIN products p ON p.id = v.product_id AND p.status = 'active' AND p.publish_status = 'published'
+		WHERE v.attribute_id = ANY($1)`
+	if productFilter.Name != "" {
+		args = append(args, "%"+productFilter.Name+"%")
+		sqlStatement += ` AND p.name ILIKE $` + fmt.Sprint(len(args))
+	}
+	if productFilter.CategoryID != 0 {
+		args = append(args, productFilter.CategoryID)
+		sqlStatement += ` AND p.category_id = $` + fmt.Sprint(len(args))
+	}
+	var filterSQL string
+	filterSQL, args = AttributeFilterSQL("p.id", productFilter.Attributes, args)
+	sqlStatement += filterSQL + ` GROUP BY v.attribute_id, value ORDER BY v.attribute_id, COUNT(*) DESC, value`
+
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving facets", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "GetFacetValues"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attributeID int
+		var value model.FacetValue
+		if err := rows.Scan(&attributeID, &value.Value, &value.Count); err != nil {
+			repo.Logger.Error("Error scanning facet", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", "GetFacetValues"))
+			return nil, err
+		}
+		facets[attributeID] = append(facets[attributeID], value)
+	}
+	return facets, rows.Err()
+}
+
+// AttributeFilterSQL turns attribute filters into conditions on the given
+// product id column, numbering its placeholders after args. Values of one
+// attribute are alternatives; different attributes must all match.
+func AttributeFilterSQL(productColumn string, filters []model.AttributeFilter, args []interface{}) (string, []interface{}) {
+	var sqlStatement strings.Builder
+	for _, filter := range filters {
+		args = append(args, filter.Code)
+		condition := `fa.code = $` + strconv.Itoa(len(args))
+		switch filter.DataType {
+		case model.AttributeTypeText:
+			lower := make([]string, len(filter.Values))
+			for i, value := range filter.Values {
+				lower[i] = strings.ToLower(value)
+			}
+			args = append(args, pq.Array(lower))
+			condition += ` AND LOWER(fv.value_text) = ANY($` + strconv.Itoa(len(args)) + `)`
+		case model.AttributeTypeBoolean:
+			args = append(args, pq.Array(filter.Values))
+			condition += ` AND fv.value_boolean::text = ANY($` + strconv.Itoa(len(args)) + `)`
+		case model.AttributeTypeNumber:
+			if len(filter.Values) > 0 {
+				args = append(args, pq.Array(filter.Values))
+				condition += ` AND fv.value_number = ANY($` + strconv.Itoa(len(args)) + `::numeric[])`
+			}
+			if filter.Min != nil {
+				args = append(args, *filter.Min)
+				condition += ` AND fv.value_number >= $` + strconv.Itoa(len(args))
+			}
+			if filter.Max != nil {
+				args = append(args, *filter.Max)
+				condition += ` AND fv.value_number <= $` + strconv.Itoa(len(args))
+			}
+		}
+		sqlStatement.WriteString(` AND EXISTS (SELECT 1 FROM product_attribute_values fv
+			JOIN attributes fa ON fa.id = fv.attribute_id AND fa.status = 'active'
+			WHERE fv.product_id = ` + productColumn + ` AND ` + condition + `)`)
+	}
+	return sqlStatement.String(), args
+}
+
+func (repo AttributeRepository) query(function, sqlStatement string, args ...interface{}) ([]model.Attribute, error) {
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving attributes", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", function))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attributes []model.Attribute
+	for rows.Next() {
+		var attribute model.Attribute
+		err := rows.Scan(&attribute.ID, &attribute.CategoryID, &attribute.Code, &attribute.Name, &attribute.DataType,
+			&attribute.Unit, &attribute.IsFilterable, &attribute.DisplayOrder)
+		if err != nil {
+			repo.Logger.Error("Error scanning attribute", zap.Error(err), zap.String("Repository", "Attribute"), zap.String("Function", function))
+			return nil, err
+		}
+		attributes = append(attributes, attribute)
+	}
+	return attributes, rows.Err()
+}
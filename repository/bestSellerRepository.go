@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type BestSellerRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewBestSellerRepository(db *sql.DB, logger *zap.Logger) BestSellerRepository {
+	return BestSellerRepository{DB: db, Logger: logger}
+}
+
+// RefreshRanks rebuilds the sales ranks of every window from the quantities
+// sold in successful orders, then flags the top flagTopN products of
+// flagWindow as best selling.
+func (repo BestSellerRepository) RefreshRanks(windows []int, flagWindow, flagTopN int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`DELETE FROM product_sales_ranks`)
+	if err != nil {
+		repo.Logger.Error("Failed to clear sales ranks", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"))
+		return err
+	}
+
+	sqlStatement := `INSERT INTO product_sales_ranks (window_days, product_id, quantity, rank, computed_at)
+		SELECT $1, oi.product_id, SUM(oi.amount), RANK() OVER (ORDER BY SUM(oi.amount) DESC), NOW()
+		FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		WHERE o.order_status = 'success' AND oi.status = 'active' AND o.created_at >= NOW() - make_interval(days => $1)
+		GROUP BY oi.product_id`
+	for _, window := range windows {
+		_, err = tx.Exec(sqlStatement, window)
+		if err != nil {
+			repo.Logger.Error("Failed to compute sales ranks", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"), zap.Int("window", window))
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE products p SET is_best_selling = EXISTS (
+			SELECT 1 FROM product_sales_ranks r WHERE r.product_id = p.id AND r.window_days = $1 AND r.rank <= $2
+		)`, flagWindow, flagTopN)
+	if err != nil {
+		repo.Logger.Error("Failed to flag best selling products", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "RefreshRanks"))
+		return err
+	}
+	return nil
+}
+
+// GetByWindow lists the ranked products of one window, best first. Deleted
+// products are skipped.
+func (repo BestSellerRepository) GetByWindow(windowDays int, pagination model.Pagination) ([]model.BestSeller, model.Pagination, error) {
+	sqlStatement := `SELECT r.product_id, r.window_days, r.quantity, r.rank, COUNT(*) OVER ()
+		FROM product_sales_ranks r JOIN products p ON p.id = r.product_id
+		WHERE r.window_days = $1 AND p.status = 'active' AND p.publish_status = 'published'
+		ORDER BY r.rank, r.product_id LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, windowDays, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving best sellers", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "GetByWindow"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var bestSellers []model.BestSeller
+	for rows.Next() {
+		var bestSeller model.BestSeller
+		err := rows.Scan(&bestSeller.Product.ID, &bestSeller.WindowDays, &bestSeller.Quantity, &bestSeller.Rank, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning best seller", zap.Error(err), zap.String("Repository", "BestSeller"), zap.String("Function", "GetByWindow"))
+			return nil, pagination, err
+		}
+		bestSellers = append(bestSellers, bestSeller)
+	}
+	return bestSellers, pagination, rows.Err()
+}
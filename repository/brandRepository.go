@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type BrandRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewBrandRepository(db *sql.DB, logger *zap.Logger) BrandRepository {
+	return BrandRepository{DB: db, Logger: logger}
+}
+
+const brandColumns = `id, name, slug, logo_url, description`
+
+func (repo BrandRepository) GetAll(pagination model.Pagination) ([]model.Brand, model.Pagination, error) {
+	sqlStatement := `SELECT ` + brandColumns + `, COUNT(*) OVER () FROM brands
+		WHERE status = 'active' ORDER BY name, id LIMIT $1 OFFSET $2`
+	rows, err := repo.DB.Query(sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving brands", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var brands []model.Brand
+	for rows.Next() {
+		var brand model.Brand
+		err := rows.Scan(&brand.ID, &brand.Name, &brand.Slug, &brand.LogoURL, &brand.Description, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "GetAll"))
+			return nil, pagination, err
+		}
+		brands = append(brands, brand)
+	}
+	return brands, pagination, rows.Err()
+}
+
+func (repo BrandRepository) GetByID(id int) (model.Brand, error) {
+	return repo.get("GetByID", `SELECT `+brandColumns+` FROM brands WHERE id = $1 AND status = 'active'`, id)
+}
+
+func (repo BrandRepository) GetBySlug(slug string) (model.Brand, error) {
+	return repo.get("GetBySlug", `SELECT `+brandColumns+` FROM brands WHERE slug = $1 AND status = 'active'`, slug)
+}
+
+func (repo BrandRepository) Create(brandInput model.Brand) (model.Brand, error) {
+	sqlStatement := `INSERT INTO brands (name, slug, logo_url, description) VALUES ($1, $2, $3, $4) RETURNING id`
+	err := repo.DB.QueryRow(sqlStatement, brandInput.Name, brandInput.Slug, brandInput.LogoURL, brandInput.Description).Scan(&brandInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Create"))
+		return brandInput, err
+	}
+	return brandInput, nil
+}
+
+func (repo BrandRepository) Update(brandInput model.Brand) error {
+	sqlStatement := `UPDATE brands SET name = $2, slug = $3, logo_url = $4, description = $5, updated_at = NOW()
+		WHERE id = $1 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, brandInput.ID, brandInput.Name, brandInput.Slug, brandInput.LogoURL, brandInput.Description)
+	if err != nil {
+		repo.Logger.Error("Failed to update brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Update"))
+		return err
+	}
+	return nil
+}
+
+// Delete soft deletes a brand and takes it off its products.
+func (repo BrandRepository) Delete(id int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Delete"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Delete"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`UPDATE brands SET status = 'deleted', deleted_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Delete"))
+		return err
+	}
+	_, err = tx.Exec(`UPDATE products SET brand_id = NULL WHERE brand_id = $1`, id)
+	if err != nil {
+		repo.Logger.Error("Failed to unset product brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Delete"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+// SetProductBrand assigns a brand to a product. A brandID of 0 removes the
+// product's brand.
+func (repo BrandRepository) SetProductBrand(productID, brandID int) error {
+	sqlStatement := `UPDATE products SET brand_id = NULLIF($2, 0), updated_at = NOW() WHERE id = $1 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, productID, brandID)
+	if err != nil {
+		repo.Logger.Error("Failed to set product brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", "SetProductBrand"))
+		return err
+	}
+	return nil
+}
+
+func (repo BrandRepository) get(function, sqlStatement string, args ...interface{}) (model.Brand, error) {
+	var brand model.Brand
+	err := repo.DB.QueryRow(sqlStatement, args...).Scan(&brand.ID, &brand.Name, &brand.Slug, &brand.LogoURL, &brand.Description)
+	if err == sql.ErrNoRows {
+		return brand, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving brand", zap.Error(err), zap.String("Repository", "Brand"), zap.String("Function", function))
+		return brand, err
+	}
+	return brand, nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type CartPromotionRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewCartPromotionRepository(db *sql.DB, logger *zap.Logger) CartPromotionRepository {
+	return CartPromotionRepository{DB: db, Logger: logger}
+}
+
+const cartPromotionColumns = `id, name, rule_type, buy_quantity, get_quantity, discount_value, starts_at, ends_at`
+
+// GetActive returns every cart promotion running right now, oldest first.
+func (repo CartPromotionRepository) GetActive() ([]model.CartPromotion, error) {
+	sqlStatement := `SELECT ` + cartPromotionColumns + ` FROM cart_promotions
+		WHERE status = 'active' AND starts_at <= NOW() AND ends_at > NOW() ORDER BY id`
+	return repo.query("GetActive", sqlStatement)
+}
+
+func (repo CartPromotionRepository) GetAll(pagination model.Pagination) ([]model.CartPromotion, model.Pagination, error) {
+	sqlStatement := `SELECT ` + cartPromotionColumns + ` FROM cart_promotions WHERE status = 'active'
+		ORDER BY starts_at DESC, id DESC LIMIT $1 OFFSET $2`
+	promotions, err := repo.query("GetAll", sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM cart_promotions WHERE status = 'active'`
+	err = repo.DB.QueryRow(countQuery).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting cart promotions", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	return promotions, pagination, nil
+}
+
+func (repo CartPromotionRepository) GetByID(id int) (model.CartPromotion, error) {
+	sqlStatement := `SELECT ` + cartPromotionColumns + ` FROM cart_promotions WHERE id = $1 AND status = 'active'`
+	promotions, err := repo.query("GetByID", sqlStatement, id)
+	if err != nil || len(promotions) == 0 {
+		return model.CartPromotion{}, err
+	}
+	return promotions[0], nil
+}
+
+func (repo CartPromotionRepository) Create(promotionInput model.CartPromotion) (model.CartPromotion, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO cart_promotions (name, rule_type, buy_quantity, get_quantity, discount_value, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	err = tx.QueryRow(sqlStatement, promotionInput.Name, promotionInput.RuleType, promotionInput.BuyQuantity, promotionInput.GetQuantity,
+		promotionInput.DiscountValue, promotionInput.StartsAt, promotionInput.EndsAt).Scan(&promotionInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create cart promotion", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+
+	for _, productID := range promotionInput.ProductIDs {
+		_, err = tx.Exec(`INSERT INTO cart_promotion_products (cart_promotion_id, product_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, promotionInput.ID, productID)
+		if err != nil {
+			repo.Logger.Error("Failed to add cart promotion product", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+			return promotionInput, err
+		}
+	}
+	for _, tier := range promotionInput.Tiers {
+		_, err = tx.Exec(`INSERT INTO cart_promotion_tiers (cart_promotion_id, min_quantity, discount_value) VALUES ($1, $2, $3)`,
+			promotionInput.ID, tier.MinQuantity, tier.DiscountValue)
+		if err != nil {
+			repo.Logger.Error("Failed to add cart promotion tier", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+			return promotionInput, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+	return promotionInput, nil
+}
+
+func (repo CartPromotionRepository) Delete(id int) error {
+	sqlStatement := `UPDATE cart_promotions SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete cart promotion", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo CartPromotionRepository) query(function, sqlStatement string, args ...interface{}) ([]model.CartPromotion, error) {
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving cart promotions", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", function))
+		return nil, err
+	}
+
+	var promotions []model.CartPromotion
+	for rows.Next() {
+		var promotion model.CartPromotion
+		err := rows.Scan(&promotion.ID, &promotion.Name, &promotion.RuleType, &promotion.BuyQuantity, &promotion.GetQuantity,
+			&promotion.DiscountValue, &promotion.StartsAt, &promotion.EndsAt)
+		if err != nil {
+			rows.Close()
+			repo.Logger.Error("Error scanning cart promotion", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", function))
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range promotions {
+		if err = repo.loadRules(&promotions[i]); err != nil {
+			repo.Logger.Error("Error retrieving cart promotion rules", zap.Error(err), zap.String("Repository", "CartPromotion"), zap.String("Function", function))
+			return nil, err
+		}
+	}
+	return promotions, nil
+}
+
+func (repo CartPromotionRepository) loadRules(promotion *model.CartPromotion) error {
+	rows, err := repo.DB.Query(`SELECT product_id FROM cart_promotion_products WHERE cart_promotion_id = $1 ORDER BY product_id`, promotion.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			return err
+		}
+		promotion.ProductIDs = append(promotion.ProductIDs, productID)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	tierRows, err := repo.DB.Query(`SELECT min_quantity, discount_value FROM cart_promotion_tiers WHERE cart_promotion_id = $1 ORDER BY min_quantity`, promotion.ID)
+	if err != nil {
+		return err
+	}
+	defer tierRows.Close()
+	for tierRows.Next() {
+		var tier model.CartPromotionTier
+		if err := tierRows.Scan(&tier.MinQuantity, &tier.DiscountValue); err != nil {
+			return err
+		}
+		promotion.Tiers = append(promotion.Tiers, tier)
+	}
+	return tierRows.Err()
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -232,9 +233,8 @@ func (repo CartRepository) UpdateItem(itemInput model.CartItem) error {
 	if itemInput.Amount != 0 {
 		fields["amount"] = itemInput.Amount
 	}
-	if itemInput.SubTotal != 0 {
-		fields["sub_total"] = itemInput.SubTotal
-	}
+	// A line can be free, so the subtotal is always written
+	fields["sub_total"] = itemInput.SubTotal
 	fields["updated_at"] = time.Now()
 
 	setClauses := []string{}
@@ -280,8 +280,8 @@ func (repo CartRepository) UpdateItem(itemInput model.CartItem) error {
 
 func (repo CartRepository) GetByUserID(userID string) (model.Cart, error) {
 	var result model.Cart
-	sqlStatement := `SELECT id, total_amount, total_price FROM carts WHERE user_id = $1 AND status = 'active' AND cart_status = 'active'`
-	err := repo.DB.QueryRow(sqlStatement, userID).Scan(&result.ID, &result.TotalAmount, &result.TotalPrice)
+	sqlStatement := `SELECT id, total_amount, total_price, COALESCE(coupon_id, 0) FROM carts WHERE user_id = $1 AND status = 'active' AND cart_status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, userID).Scan(&result.ID, &result.TotalAmount, &result.TotalPrice, &result.CouponID)
 	if err == sql.ErrNoRows {
 		return result, nil
 	} else if err != nil {
@@ -292,8 +292,8 @@ func (repo CartRepository) GetByUserID(userID string) (model.Cart, error) {
 
 func (repo CartRepository) GetByID(id int) (model.Cart, error) {
 	var result model.Cart
-	sqlStatement := `SELECT id, user_id, total_amount, total_price FROM carts WHERE id = $1 AND status = 'active' AND cart_status = 'active'`
-	err := repo.DB.QueryRow(sqlStatement, id).Scan(&result.ID, &result.UserID, &result.TotalAmount, &result.TotalPrice)
+	sqlStatement := `SELECT id, user_id, total_amount, total_price, COALESCE(coupon_id, 0) FROM carts WHERE id = $1 AND status = 'active' AND cart_status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&result.ID, &result.UserID, &result.TotalAmount, &result.TotalPrice, &result.CouponID)
 	if err == sql.ErrNoRows {
 		return result, nil
 	} else if err != nil {
@@ -303,9 +303,18 @@ func (repo CartRepository) GetByID(id int) (model.Cart, error) {
 }
 
 func (repo CartRepository) GetItems(cartId int) ([]model.CartItem, error) {
+	return repo.getItems(cartId, false)
+}
+
+// GetSavedItems returns the lines of the cart saved for later.
+func (repo CartRepository) GetSavedItems(cartID int) ([]model.CartItem, error) {
+	return repo.getItems(cartID, true)
+}
+
+func (repo CartRepository) getItems(cartId int, saved bool) ([]model.CartItem, error) {
 	var cartItems []model.CartItem
-	sqlStatement := `SELECT id, product_id, amount, sub_total FROM cart_items WHERE cart_id = $1`
-	rows, err := repo.DB.Query(sqlStatement, cartId)
+	sqlStatement := `SELECT id, product_id, amount, sub_total, saved_for_later FROM cart_items WHERE cart_id = $1 AND status = 'active' AND saved_for_later = $2 ORDER BY id`
+	rows, err := repo.DB.Query(sqlStatement, cartId, saved)
 	if err != nil {
 		repo.Logger.Error("Failed to execute query", zap.Error(err), zap.String("repository",
 			"Cart"))
@@ -315,7 +324,7 @@ func (repo CartRepository) GetItems(cartId int) ([]model.CartItem, error) {
 
 	for rows.Next() {
 		var item model.CartItem
-		err = rows.Scan(&item.ID, &item.ProductID, &item.Amount, &item.SubTotal)
+		err = rows.Scan(&item.ID, &item.ProductID, &item.Amount, &item.SubTotal, &item.SavedForLater)
 		if err != nil {
 			repo.Logger.Error("Failed to scan row", zap.Error(err), zap.String("repository",
 				"Cart"))
@@ -335,8 +344,9 @@ func (repo CartRepository) GetItems(cartId int) ([]model.CartItem, error) {
 }
 
 func (repo CartRepository) RecalculateTotal(cartID int) error {
-	sqlStatement := `SELECT SUM(amount) as total_amount, SUM(sub_total) as total_price FROM cart_items WHERE cart_id = $1 AND status ='active'`
-	var totalAmount, totalPrice float64
+	sqlStatement := `SELECT COALESCE(SUM(amount), 0) as total_amount, COALESCE(SUM(sub_total), 0) as total_price FROM cart_items WHERE cart_id = $1 AND status ='active' AND NOT saved_for_later`
+	var totalAmount float64
+	var totalPrice model.Money
 	err := repo.DB.QueryRow(sqlStatement, cartID).Scan(&totalAmount, &totalPrice)
 	if err != nil {
 		repo.Logger.Error("Failed to execute query", zap.Error(err), zap.String("repository",
@@ -359,8 +369,8 @@ func (repo CartRepository) RecalculateTotal(cartID int) error {
 
 func (repo CartRepository) GetItemByID(id int) (model.CartItem, error) {
 	var result model.CartItem
-	sqlStatement := `SELECT id, cart_id, product_id, amount, sub_total FROM cart_items WHERE id = $1`
-	err := repo.DB.QueryRow(sqlStatement, id).Scan(&result.ID, &result.CartID, &result.ProductID, &result.Amount, &result.SubTotal)
+	sqlStatement := `SELECT id, cart_id, product_id, amount, sub_total, saved_for_later FROM cart_items WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&result.ID, &result.CartID, &result.ProductID, &result.Amount, &result.SubTotal, &result.SavedForLater)
 	if err == sql.ErrNoRows {
 		return result, nil
 	} else if err != nil {
@@ -372,7 +382,7 @@ func (repo CartRepository) GetItemByID(id int) (model.CartItem, error) {
 
 func (repo CartRepository) GetItemVariants(itemID int) ([]model.CarttemVariant, error) {
 	var result []model.CarttemVariant
-	sqlStatement := `SELECT id, cart_item_id, item_variant_id, option_id, additional_price  FROM cart_item_variants WHERE cart_item_id = $1`
+	sqlStatement := `SELECT id, cart_item_id, item_variant_id, option_id, additional_price  FROM cart_item_variants WHERE cart_item_id = $1 AND status = 'active'`
 	rows, err := repo.DB.Query(sqlStatement, itemID)
 	if err == sql.ErrNoRows {
 		return result, nil
@@ -428,3 +438,183 @@ func (repo CartRepository) UpdateCartStatus(id int) error {
 	}
 	return nil
 }
+
+// SetCoupon attaches a coupon to the cart, or detaches it when couponID is 0.
+func (repo CartRepository) SetCoupon(cartID, couponID int) error {
+	sqlStatement := `UPDATE carts SET coupon_id = NULLIF($1, 0), updated_at = NOW() WHERE id = $2`
+	_, err := repo.DB.Exec(sqlStatement, couponID, cartID)
+	if err != nil {
+		repo.Logger.Error("Failed to set cart coupon", zap.Error(err), zap.String("repository", "Cart"), zap.String("Function", "SetCoupon"))
+		return err
+	}
+	return nil
+}
+
+// FindItem returns the active line of the cart holding the product with
+// exactly the given variant options, or a zero item. saved picks the saved
+// for later lines instead of the cart lines. optionIDs must be sorted. The
+// line excludeID is skipped.
+func (repo CartRepository) FindItem(cartID, productID int, optionIDs []int, excludeID int, saved bool) (model.CartItem, error) {
+	var result model.CartItem
+	if optionIDs == nil {
+		optionIDs = []int{} // a line without variants has the empty set
+	}
+	sqlStatement := `SELECT ci.id, ci.cart_id, ci.product_id, ci.amount, ci.sub_total FROM cart_items ci
+		WHERE ci.cart_id = $1 AND ci.product_id = $2 AND ci.status = 'active' AND ci.id <> $4 AND ci.saved_for_later = $5
+			AND COALESCE((SELECT array_agg(civ.option_id ORDER BY civ.option_id) FROM cart_item_variants civ
+				WHERE civ.cart_item_id = ci.id AND civ.status = 'active'), '{}') = $3::integer[]
+		ORDER BY ci.id LIMIT 1`
+	err := repo.DB.QueryRow(sqlStatement, cartID, productID, pq.Array(optionIDs), excludeID, saved).Scan(&result.ID, &result.CartID, &result.ProductID, &result.Amount, &result.SubTotal)
+	if err == sql.ErrNoRows {
+		return model.CartItem{}, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Failed to find cart item", zap.Error(err), zap.String("repository", "Cart"), zap.String("Function", "FindItem"))
+		return model.CartItem{}, err
+	}
+	return result, nil
+}
+
+// ReplaceItemVariants swaps the variant selection of a line and stores its
+// new subtotal.
+func (repo CartRepository) ReplaceItemVariants(itemID int, variants []model.CartItemVariantDTO, subTotal model.Money) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`UPDATE cart_item_variants SET status = 'deleted', deleted_at = NOW() WHERE cart_item_id = $1 AND status = 'active'`, itemID)
+	if err != nil {
+		repo.Logger.Error("Failed to remove cart item variants", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+		return err
+	}
+	for _, variant := range variants {
+		_, err = tx.Exec(`INSERT INTO cart_item_variants (cart_item_id, item_variant_id, option_id, additional_price) VALUES ($1, $2, $3, $4)`,
+			itemID, variant.VariantID, variant.VariantOptionID, variant.AdditionalPrice)
+		if err != nil {
+			repo.Logger.Error("Failed to add cart item variant", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+			return err
+		}
+	}
+	_, err = tx.Exec(`UPDATE cart_items SET sub_total = $2, updated_at = NOW() WHERE id = $1`, itemID, subTotal)
+	if err != nil {
+		repo.Logger.Error("Failed to update cart item", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "ReplaceItemVariants"))
+		return err
+	}
+	return nil
+}
+
+// MergeItem folds the line fromID into the line into, which takes the
+// combined amount and subtotal, and removes fromID.
+func (repo CartRepository) MergeItem(fromID int, into model.CartItem) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "MergeItem"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "MergeItem"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`UPDATE cart_items SET amount = $2, sub_total = $3, updated_at = NOW() WHERE id = $1 AND status = 'active'`, into.ID, into.Amount, into.SubTotal)
+	if err != nil {
+		repo.Logger.Error("Failed to update cart item", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "MergeItem"))
+		return err
+	}
+	_, err = tx.Exec(`UPDATE cart_items SET status = 'deleted', deleted_at = NOW() WHERE id = $1`, fromID)
+	if err != nil {
+		repo.Logger.Error("Failed to delete cart item", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "MergeItem"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "MergeItem"))
+		return err
+	}
+	return nil
+}
+
+// SetItemSaved moves a line to the saved for later list, or back to the
+// cart, and stores its subtotal.
+func (repo CartRepository) SetItemSaved(itemID int, saved bool, subTotal model.Money) error {
+	sqlStatement := `UPDATE cart_items SET saved_for_later = $2, sub_total = $3, updated_at = NOW() WHERE id = $1 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, itemID, saved, subTotal)
+	if err != nil {
+		repo.Logger.Error("Failed to set cart item saved", zap.Error(err), zap.String("repository", "Cart"), zap.String("Function", "SetItemSaved"))
+		return err
+	}
+	return nil
+}
+
+// CarryOverSavedItems moves the saved for later lines of a checked out cart
+// to a new cart of the same user, so they outlive the order.
+func (repo CartRepository) CarryOverSavedItems(cartID int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "CarryOverSavedItems"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "CarryOverSavedItems"))
+			tx.Rollback()
+		}
+	}()
+
+	if err = carryOverSavedItems(tx, cartID); err != nil {
+		repo.Logger.Error("Failed to move saved cart items", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "CarryOverSavedItems"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Cart"), zap.String("Function", "CarryOverSavedItems"))
+		return err
+	}
+	return nil
+}
+
+// carryOverSavedItems moves the saved for later lines of a cart to a new
+// cart of the same user inside the caller's transaction. A cart without
+// saved lines is left as it is.
+func carryOverSavedItems(tx *sql.Tx, cartID int) error {
+	var saved bool
+	err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM cart_items WHERE cart_id = $1 AND saved_for_later AND status = 'active')`, cartID).Scan(&saved)
+	if err != nil || !saved {
+		return err
+	}
+
+	var newCartID int
+	err = tx.QueryRow(`INSERT INTO carts (user_id) SELECT user_id FROM carts WHERE id = $1 RETURNING id`, cartID).Scan(&newCartID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE cart_items SET cart_id = $2, updated_at = NOW() WHERE cart_id = $1 AND saved_for_later AND status = 'active'`, cartID, newCartID)
+	return err
+}
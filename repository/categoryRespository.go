@@ -22,7 +22,7 @@ var startTime = time.Now()
 func (repo CategoryRepository) GetAll(pagination model.Pagination) ([]model.Category, model.Pagination, error) {
 	var categories []model.Category
 
-	sqlStatement := `SELECT id, name FROM categories LIMIT $1 OFFSET $2`
+	sqlStatement := `SELECT id, name, slug, meta_title, meta_description FROM categories LIMIT $1 OFFSET $2`
 	limit := pagination.PerPage
 	offset := (pagination.Page - 1) / limit
 
@@ -45,7 +45,7 @@ func (repo CategoryRepository) GetAll(pagination model.Pagination) ([]model.Cate
 
 	for rows.Next() {
 		var category model.Category
-		err := rows.Scan(&category.ID, &category.Name)
+		err := rows.Scan(&category.ID, &category.Name, &category.Slug, &category.MetaTitle, &category.MetaDescription)
 		if err != nil {
 			repo.Logger.Error("error when scanning row", zap.Error(err),
 				zap.String("Repository", "Category"),
@@ -81,3 +81,17 @@ func (repo CategoryRepository) CountCategories() (int, error) {
 
 	return totalCount, nil
 }
+
+func (repo CategoryRepository) GetByID(id int) (model.Category, error) {
+	var category model.Category
+	sqlStatement := `SELECT id, name, slug, meta_title, meta_description FROM categories WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&category.ID, &category.Name, &category.Slug, &category.MetaTitle, &category.MetaDescription)
+	if err == sql.ErrNoRows {
+		return category, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error getting category by ID", zap.Error(err), zap.String("Repository", "Category"), zap.String("Function", "GetByID"))
+		return category, err
+	}
+	return category, nil
+}
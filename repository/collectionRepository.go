@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type CollectionRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewCollectionRepository(db *sql.DB, logger *zap.Logger) CollectionRepository {
+	return CollectionRepository{DB: db, Logger: logger}
+}
+
+const collectionColumns = `id, name, slug, description, type, rule`
+
+// collectionNumberColumns maps the numeric fields of collection rules to
+// product columns.
+var collectionNumberColumns = map[string]string{
+	"price":    "price",
+	"discount": "discount",
+	"rating":   "rating",
+	"stock":    "total_stock",
+}
+
+func (repo CollectionRepository) GetAll(pagination model.Pagination) ([]model.Collection, model.Pagination, error) {
+	sqlStatement := `SELECT ` + collectionColumns + `, COUNT(*) OVER () FROM collections
+		WHERE status = 'active' ORDER BY name, id LIMIT $1 OFFSET $2`
+	rows, err := repo.DB.Query(sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving collections", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var collections []model.Collection
+	for rows.Next() {
+		var collection model.Collection
+		err := rows.Scan(&collection.ID, &collection.Name, &collection.Slug, &collection.Description, &collection.Type,
+			&collection.Rule, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning collection", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetAll"))
+			return nil, pagination, err
+		}
+		collections = append(collections, collection)
+	}
+	return collections, pagination, rows.Err()
+}
+
+func (repo CollectionRepository) GetByID(id int) (model.Collection, error) {
+	return repo.get("GetByID", `SELECT `+collectionColumns+` FROM collections WHERE id = $1 AND status = 'active'`, id)
+}
+
+func (repo CollectionRepository) GetBySlug(slug string) (model.Collection, error) {
+	return repo.get("GetBySlug", `SELECT `+collectionColumns+` FROM collections WHERE slug = $1 AND status = 'active'`, slug)
+}
+
+// GetProductIDs returns the products of a manual collection in display order.
+func (repo CollectionRepository) GetProductIDs(collectionID int) ([]int, error) {
+	rows, err := repo.DB.Query(`SELECT product_id FROM collection_products WHERE collection_id = $1 ORDER BY position, product_id`, collectionID)
+	if err != nil {
+		repo.Logger.Error("Error retrieving collection products", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetProductIDs"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var productIDs []int
+	for rows.Next() {
+		var productID int
+		if err := rows.Scan(&productID); err != nil {
+			repo.Logger.Error("Error scanning collection product", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetProductIDs"))
+			return nil, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	return productIDs, rows.Err()
+}
+
+func (repo CollectionRepository) Create(collectionInput model.Collection) (model.Collection, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Create"))
+		return collectionInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO collections (name, slug, description, type, rule) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err = tx.QueryRow(sqlStatement, collectionInput.Name, collectionInput.Slug, collectionInput.Description,
+		collectionInput.Type, collectionInput.Rule).Scan(&collectionInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create collection", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Create"))
+		return collectionInput, err
+	}
+	if err = repo.replaceProducts(tx, collectionInput); err != nil {
+		return collectionInput, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Create"))
+		return collectionInput, err
+	}
+	return collectionInput, nil
+}
+
+func (repo CollectionRepository) Update(collectionInput model.Collection) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Update"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Update"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `UPDATE collections SET name = $2, slug = $3, description = $4, type = $5, rule = $6, updated_at = NOW()
+		WHERE id = $1 AND status = 'active'`
+	_, err = tx.Exec(sqlStatement, collectionInput.ID, collectionInput.Name, collectionInput.Slug, collectionInput.Description,
+		collectionInput.Type, collectionInput.Rule)
+	if err != nil {
+		repo.Logger.Error("Failed to update collection", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Update"))
+		return err
+	}
+	if err = repo.replaceProducts(tx, collectionInput); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Update"))
+		return err
+	}
+	return nil
+}
+
+func (repo CollectionRepository) Delete(id int) error {
+	_, err := repo.DB.Exec(`UPDATE collections SET status = 'deleted', deleted_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete collection", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo CollectionRepository) GetProductTags(productID int) ([]string, error) {
+	rows, err := repo.DB.Query(`SELECT tag FROM product_tags WHERE product_id = $1 ORDER BY tag`, productID)
+	if err != nil {
+		repo.Logger.Error("Error retrieving product tags", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetProductTags"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			repo.Logger.Error("Error scanning product tag", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "GetProductTags"))
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// SetProductTags replaces every tag of a product.
+func (repo CollectionRepository) SetProductTags(productID int, tags []string) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "SetProductTags"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "SetProductTags"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`DELETE FROM product_tags WHERE product_id = $1`, productID)
+	if err != nil {
+		repo.Logger.Error("Failed to clear product tags", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "SetProductTags"))
+		return err
+	}
+	for _, tag := range tags {
+		_, err = tx.Exec(`INSERT INTO product_tags (product_id, tag) VALUES ($1, $2)`, productID, tag)
+		if err != nil {
+			repo.Logger.Error("Failed to save product tag", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "SetProductTags"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "SetProductTags"))
+		return err
+	}
+	return nil
+}
+
+// CollectionFilterSQL returns the conditions narrowing a product query on
+// table to the tag, manual collection and rule conditions of the filter,
+// with their arguments appended to args.
+func CollectionFilterSQL(table string, productFilter model.ProductDTO, args []interface{}) (string, []interface{}) {
+	var sqlStatement strings.Builder
+	if productFilter.Tag != "" {
+		args = append(args, productFilter.Tag)
+		sqlStatement.WriteString(` AND EXISTS (SELECT 1 FROM product_tags ft WHERE ft.product_id = ` + table + `.id AND ft.tag = $` + strconv.Itoa(len(args)) + `)`)
+	}
+	if productFilter.CollectionID != 0 {
+		args = append(args, productFilter.CollectionID)
+		sqlStatement.WriteString(` AND EXISTS (SELECT 1 FROM collection_products fc WHERE fc.product_id = ` + table + `.id AND fc.collection_id = $` + strconv.Itoa(len(args)) + `)`)
+	}
+
+	for _, condition := range productFilter.Conditions {
+		args = append(args, condition.Value)
+		arg := `$` + strconv.Itoa(len(args))
+		switch condition.Field {
+		case "tag":
+			exists := ` AND EXISTS`
+			if condition.Operator == "!=" {
+				exists = ` AND NOT EXISTS`
+			}
+			sqlStatement.WriteString(exists + ` (SELECT 1 FROM product_tags ft WHERE ft.product_id = ` + table + `.id AND ft.tag = ` + arg + `)`)
+		case "category":
+			sqlStatement.WriteString(` AND ` + table + `.category_id ` + distinctOperator(condition.Operator) + ` ` + arg + `::integer`)
+		case "brand":
+			sqlStatement.WriteString(` AND ` + table + `.brand_id ` + distinctOperator(condition.Operator) +
+				` (SELECT fb.id FROM brands fb WHERE fb.slug = ` + arg + ` AND fb.status = 'active')`)
+		default:
+			sqlStatement.WriteString(` AND ` + table + `.` + collectionNumberColumns[condition.Field] + ` ` + condition.Operator + ` ` + arg + `::numeric`)
+		}
+	}
+	return sqlStatement.String(), args
+}
+
+// distinctOperator makes != also match products where the column is NULL.
+func distinctOperator(operator string) string {
+	if operator == "!=" {
+		return "IS DISTINCT FROM"
+	}
+	return "="
+}
+
+func (repo CollectionRepository) replaceProducts(tx *sql.Tx, collection model.Collection) error {
+	_, err := tx.Exec(`DELETE FROM collection_products WHERE collection_id = $1`, collection.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to clear collection products", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "replaceProducts"))
+		return err
+	}
+	for position, productID := range collection.ProductIDs {
+		_, err = tx.Exec(`INSERT INTO collection_products (collection_id, product_id, position) VALUES ($1, $2, $3)`,
+			collection.ID, productID, position)
+		if err != nil {
+			repo.Logger.Error("Failed to save collection product", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", "replaceProducts"))
+			return err
+		}
+	}
+	return nil
+}
+
+func (repo CollectionRepository) get(function, sqlStatement string, args ...interface{}) (model.Collection, error) {
+	var collection model.Collection
+	err := repo.DB.QueryRow(sqlStatement, args...).Scan(&collection.ID, &collection.Name, &collection.Slug,
+		&collection.Description, &collection.Type, &collection.Rule)
+	if err == sql.ErrNoRows {
+		return collection, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving collection", zap.Error(err), zap.String("Repository", "Collection"), zap.String("Function", function))
+		return collection, err
+	}
+	return collection, nil
+}
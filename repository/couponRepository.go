@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCouponUnavailable = errors.New("coupon is not active")
+	ErrCouponUsageLimit  = errors.New("coupon usage limit reached")
+	ErrCouponUserLimit   = errors.New("coupon already used the maximum number of times")
+)
+
+type CouponRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewCouponRepository(db *sql.DB, logger *zap.Logger) CouponRepository {
+	return CouponRepository{DB: db, Logger: logger}
+}
+
+const couponColumns = `id, code, COALESCE(description, ''), coupon_type, discount_value, COALESCE(max_discount, 0), min_spend,
+	starts_at, ends_at, COALESCE(usage_limit, 0), COALESCE(per_user_limit, 0), times_used, COALESCE(user_id, '')`
+
+func scanCoupon(scanner interface{ Scan(...interface{}) error }, coupon *model.Coupon) error {
+	return scanner.Scan(&coupon.ID, &coupon.Code, &coupon.Description, &coupon.CouponType, &coupon.DiscountValue, &coupon.MaxDiscount,
+		&coupon.MinSpend, &coupon.StartsAt, &coupon.EndsAt, &coupon.UsageLimit, &coupon.PerUserLimit, &coupon.TimesUsed, &coupon.UserID)
+}
+
+func (repo CouponRepository) GetByCode(code string) (model.Coupon, error) {
+	var coupon model.Coupon
+	sqlStatement := `SELECT ` + couponColumns + ` FROM coupons WHERE upper(code) = upper($1) AND status = 'active'`
+	err := scanCoupon(repo.DB.QueryRow(sqlStatement, code), &coupon)
+	if err == sql.ErrNoRows {
+		return coupon, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get coupon by code", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "GetByCode"))
+		return coupon, err
+	}
+	coupon.CategoryIDs, err = repo.getCategoryIDs(coupon.ID)
+	return coupon, err
+}
+
+func (repo CouponRepository) GetByID(id int) (model.Coupon, error) {
+	var coupon model.Coupon
+	sqlStatement := `SELECT ` + couponColumns + ` FROM coupons WHERE id = $1 AND status = 'active'`
+	err := scanCoupon(repo.DB.QueryRow(sqlStatement, id), &coupon)
+	if err == sql.ErrNoRows {
+		return coupon, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get coupon by ID", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "GetByID"))
+		return coupon, err
+	}
+	coupon.CategoryIDs, err = repo.getCategoryIDs(coupon.ID)
+	return coupon, err
+}
+
+func (repo CouponRepository) GetAll(pagination model.Pagination) ([]model.Coupon, model.Pagination, error) {
+	sqlStatement := `SELECT ` + couponColumns + ` FROM coupons WHERE status = 'active' ORDER BY id DESC LIMIT $1 OFFSET $2`
+	rows, err := repo.DB.Query(sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving coupons", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var coupons []model.Coupon
+	for rows.Next() {
+		var coupon model.Coupon
+		if err := scanCoupon(rows, &coupon); err != nil {
+			repo.Logger.Error("Error scanning coupon", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "GetAll"))
+			return nil, pagination, err
+		}
+		coupons = append(coupons, coupon)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, pagination, err
+	}
+
+	for i := range coupons {
+		coupons[i].CategoryIDs, err = repo.getCategoryIDs(coupons[i].ID)
+		if err != nil {
+			return nil, pagination, err
+		}
+	}
+
+	countQuery := `SELECT COUNT(*) FROM coupons WHERE status = 'active'`
+	err = repo.DB.QueryRow(countQuery).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting coupons", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	return coupons, pagination, nil
+}
+
+func (repo CouponRepository) Create(couponInput model.Coupon) (model.Coupon, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Create"))
+		return couponInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	couponInput, err = insertCoupon(tx, couponInput)
+	if err != nil {
+		repo.Logger.Error("Failed to create coupon", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Create"))
+		return couponInput, err
+	}
+
+	for _, categoryID := range couponInput.CategoryIDs {
+		_, err = tx.Exec(`INSERT INTO coupon_categories (coupon_id, category_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, couponInput.ID, categoryID)
+		if err != nil {
+			repo.Logger.Error("Failed to add coupon category", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Create"))
+			return couponInput, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Create"))
+		return couponInput, err
+	}
+	return couponInput, nil
+}
+
+// insertCoupon creates a coupon inside the caller's transaction. Categories
+// are added by the caller.
+func insertCoupon(tx *sql.Tx, couponInput model.Coupon) (model.Coupon, error) {
+	sqlStatement := `INSERT INTO coupons (code, description, coupon_type, discount_value, max_discount, min_spend, starts_at, ends_at, usage_limit, per_user_limit, user_id)
+		VALUES ($1, NULLIF($2, ''), $3, $4, NULLIF($5, 0), $6, $7, $8, NULLIF($9, 0), NULLIF($10, 0), NULLIF($11, '')) RETURNING id, times_used`
+	err := tx.QueryRow(sqlStatement, couponInput.Code, couponInput.Description, couponInput.CouponType, couponInput.DiscountValue,
+		couponInput.MaxDiscount, couponInput.MinSpend, couponInput.StartsAt, couponInput.EndsAt, couponInput.UsageLimit,
+		couponInput.PerUserLimit, couponInput.UserID).Scan(&couponInput.ID, &couponInput.TimesUsed)
+	return couponInput, err
+}
+
+func (repo CouponRepository) Delete(id int) error {
+	sqlStatement := `UPDATE coupons SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete coupon", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo CouponRepository) CountUserRedemptions(couponID int, userID string) (int, error) {
+	var count int
+	sqlStatement := `SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2`
+	err := repo.DB.QueryRow(sqlStatement, couponID, userID).Scan(&count)
+	if err != nil {
+		repo.Logger.Error("Failed to count coupon redemptions", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "CountUserRedemptions"))
+		return 0, err
+	}
+	return count, nil
+}
+
+// ReleaseRedemption gives the usage of a failed order back to its coupon.
+func (repo CouponRepository) ReleaseRedemption(orderID int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "ReleaseRedemption"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "ReleaseRedemption"))
+			tx.Rollback()
+		}
+	}()
+
+	var couponID int
+	err = tx.QueryRow(`DELETE FROM coupon_redemptions WHERE order_id = $1 RETURNING coupon_id`, orderID).Scan(&couponID)
+	if err == sql.ErrNoRows {
+		err = nil
+		return tx.Commit()
+	} else if err != nil {
+		repo.Logger.Error("Failed to delete coupon redemption", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "ReleaseRedemption"))
+		return err
+	}
+
+	_, err = tx.Exec(`UPDATE coupons SET times_used = times_used - 1, updated_at = NOW() WHERE id = $1 AND times_used > 0`, couponID)
+	if err != nil {
+		repo.Logger.Error("Failed to release coupon usage", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "ReleaseRedemption"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "ReleaseRedemption"))
+		return err
+	}
+	return nil
+}
+
+func (repo CouponRepository) getCategoryIDs(couponID int) ([]int, error) {
+	rows, err := repo.DB.Query(`SELECT category_id FROM coupon_categories WHERE coupon_id = $1 ORDER BY category_id`, couponID)
+	if err != nil {
+		repo.Logger.Error("Error retrieving coupon categories", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "getCategoryIDs"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categoryIDs []int
+	for rows.Next() {
+		var categoryID int
+		if err := rows.Scan(&categoryID); err != nil {
+			repo.Logger.Error("Error scanning coupon category", zap.Error(err), zap.String("Repository", "Coupon"), zap.String("Function", "getCategoryIDs"))
+			return nil, err
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	return categoryIDs, rows.Err()
+}
+
+// redeemCoupon records the coupon usage of an order inside the order
+// transaction. The coupon row is locked first so concurrent checkouts with
+// the same code are serialised and the limits cannot be overrun.
+func redeemCoupon(tx *sql.Tx, order model.Order) error {
+	var usageLimit, perUserLimit sql.NullInt64
+	var timesUsed int
+	sqlStatement := `SELECT usage_limit, per_user_limit, times_used FROM coupons
+		WHERE id = $1 AND status = 'active' AND starts_at <= NOW() AND ends_at > NOW() FOR UPDATE`
+	err := tx.QueryRow(sqlStatement, order.CouponID).Scan(&usageLimit, &perUserLimit, &timesUsed)
+	if err == sql.ErrNoRows {
+		return ErrCouponUnavailable
+	} else if err != nil {
+		return err
+	}
+	if usageLimit.Valid && int64(timesUsed) >= usageLimit.Int64 {
+		return ErrCouponUsageLimit
+	}
+
+	if perUserLimit.Valid {
+		var used int64
+		err = tx.QueryRow(`SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_id = $1 AND user_id = $2`, order.CouponID, order.UserID).Scan(&used)
+		if err != nil {
+			return err
+		}
+		if used >= perUserLimit.Int64 {
+			return ErrCouponUserLimit
+		}
+	}
+
+	_, err = tx.Exec(`UPDATE coupons SET times_used = times_used + 1, updated_at = NOW() WHERE id = $1`, order.CouponID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO coupon_redemptions (coupon_id, user_id, order_id, discount_amount) VALUES ($1, $2, $3, $4)`,
+		order.CouponID, order.UserID, order.ID, order.Discount)
+	return err
+}
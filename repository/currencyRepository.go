@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type CurrencyRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewCurrencyRepository(db *sql.DB, logger *zap.Logger) CurrencyRepository {
+	return CurrencyRepository{DB: db, Logger: logger}
+}
+
+const currencyColumns = `code, name, symbol, rate, decimals, rounding_increment, is_base, updated_at`
+
+func (repo CurrencyRepository) GetAll() ([]model.Currency, error) {
+	sqlStatement := `SELECT ` + currencyColumns + ` FROM currencies WHERE status = 'active' ORDER BY is_base DESC, code`
+	return repo.query("GetAll", sqlStatement)
+}
+
+func (repo CurrencyRepository) GetByCode(code string) (model.Currency, error) {
+	sqlStatement := `SELECT ` + currencyColumns + ` FROM currencies WHERE code = $1 AND status = 'active'`
+	currencies, err := repo.query("GetByCode", sqlStatement, code)
+	if err != nil || len(currencies) == 0 {
+		return model.Currency{}, err
+	}
+	return currencies[0], nil
+}
+
+func (repo CurrencyRepository) GetBase() (model.Currency, error) {
+	sqlStatement := `SELECT ` + currencyColumns + ` FROM currencies WHERE is_base AND status = 'active'`
+	currencies, err := repo.query("GetBase", sqlStatement)
+	if err != nil || len(currencies) == 0 {
+		return model.Currency{}, err
+	}
+	return currencies[0], nil
+}
+
+// Save creates or updates the given currencies in one transaction. A
+// deleted currency with the same code is brought back.
+func (repo CurrencyRepository) Save(currencies []model.Currency) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", "Save"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", "Save"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO currencies (code, name, symbol, rate, decimals, rounding_increment) VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, symbol = EXCLUDED.symbol, rate = EXCLUDED.rate,
+			decimals = EXCLUDED.decimals, rounding_increment = EXCLUDED.rounding_increment,
+			status = 'active', deleted_at = NULL, updated_at = NOW()`
+	for _, currency := range currencies {
+		_, err = tx.Exec(sqlStatement, currency.Code, currency.Name, currency.Symbol, currency.Rate, currency.Decimals, currency.RoundingIncrement)
+		if err != nil {
+			repo.Logger.Error("Failed to save currency", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", "Save"), zap.String("code", currency.Code))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", "Save"))
+		return err
+	}
+	return nil
+}
+
+func (repo CurrencyRepository) Delete(code string) error {
+	sqlStatement := `UPDATE currencies SET status = 'deleted', deleted_at = NOW() WHERE code = $1 AND NOT is_base`
+	_, err := repo.DB.Exec(sqlStatement, code)
+	if err != nil {
+		repo.Logger.Error("Failed to delete currency", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo CurrencyRepository) query(function, sqlStatement string, args ...interface{}) ([]model.Currency, error) {
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving currencies", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", function))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var currencies []model.Currency
+	for rows.Next() {
+		var currency model.Currency
+		var updatedAt sql.NullTime
+		err := rows.Scan(&currency.Code, &currency.Name, &currency.Symbol, &currency.Rate, &currency.Decimals,
+			&currency.RoundingIncrement, &currency.IsBase, &updatedAt)
+		if err != nil {
+			repo.Logger.Error("Error scanning currency", zap.Error(err), zap.String("Repository", "Currency"), zap.String("Function", function))
+			return nil, err
+		}
+		if updatedAt.Valid {
+			currency.UpdatedAt = &updatedAt.Time
+		}
+		currencies = append(currencies, currency)
+	}
+	return currencies, rows.Err()
+}
@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrFlashSaleEnded    = errors.New("flash sale has ended")
+	ErrFlashSaleSoldOut  = errors.New("flash sale allocation is sold out")
+	ErrFlashSaleCapacity = errors.New("flash sale purchase limit per customer reached")
+)
+
+type FlashSaleRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewFlashSaleRepository(db *sql.DB, logger *zap.Logger) FlashSaleRepository {
+	return FlashSaleRepository{DB: db, Logger: logger}
+}
+
+// GetActiveItems returns the flash sale items that are running right now and
+// still have allocation left.
+func (repo FlashSaleRepository) GetActiveItems() ([]model.FlashSaleItem, error) {
+	sqlStatement := `SELECT fsi.id, fsi.flash_sale_id, fsi.product_id, fsi.sale_price, fsi.allocation, fsi.sold,
+			COALESCE(fsi.per_customer_limit, 0), fs.ends_at
+		FROM flash_sale_items fsi JOIN flash_sales fs ON fs.id = fsi.flash_sale_id
+		WHERE fs.status = 'active' AND fs.starts_at <= NOW() AND fs.ends_at > NOW() AND fsi.sold < fsi.allocation
+		ORDER BY fsi.sale_price ASC`
+	rows, err := repo.DB.Query(sqlStatement)
+	if err != nil {
+		repo.Logger.Error("Error retrieving active flash sale items", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "GetActiveItems"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.FlashSaleItem
+	seen := make(map[int]bool)
+	for rows.Next() {
+		var item model.FlashSaleItem
+		err := rows.Scan(&item.ID, &item.FlashSaleID, &item.ProductID, &item.SalePrice, &item.Allocation, &item.Sold, &item.PerCustomerLimit, &item.EndsAt)
+		if err != nil {
+			repo.Logger.Error("Error scanning flash sale item", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "GetActiveItems"))
+			return nil, err
+		}
+		// overlapping flash sales on one product: the cheapest one wins
+		if seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		item.Remaining = item.Allocation - item.Sold
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// GetCurrent returns the running and upcoming flash sales with their items.
+func (repo FlashSaleRepository) GetCurrent() ([]model.FlashSale, error) {
+	sqlStatement := `SELECT id, name, starts_at, ends_at FROM flash_sales
+		WHERE status = 'active' AND ends_at > NOW() ORDER BY starts_at ASC, id ASC`
+	rows, err := repo.DB.Query(sqlStatement)
+	if err != nil {
+		repo.Logger.Error("Error retrieving flash sales", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "GetCurrent"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flashSales []model.FlashSale
+	for rows.Next() {
+		var flashSale model.FlashSale
+		if err := rows.Scan(&flashSale.ID, &flashSale.Name, &flashSale.StartsAt, &flashSale.EndsAt); err != nil {
+			repo.Logger.Error("Error scanning flash sale", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "GetCurrent"))
+			return nil, err
+		}
+		flashSales = append(flashSales, flashSale)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range flashSales {
+		flashSales[i].Items, err = repo.getItems(flashSales[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return flashSales, nil
+}
+
+func (repo FlashSaleRepository) GetByID(id int) (model.FlashSale, error) {
+	var flashSale model.FlashSale
+	sqlStatement := `SELECT id, name, starts_at, ends_at FROM flash_sales WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&flashSale.ID, &flashSale.Name, &flashSale.StartsAt, &flashSale.EndsAt)
+	if err == sql.ErrNoRows {
+		return flashSale, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get flash sale by ID", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "GetByID"))
+		return flashSale, err
+	}
+	flashSale.Items, err = repo.getItems(flashSale.ID)
+	return flashSale, err
+}
+
+func (repo FlashSaleRepository) Create(flashSaleInput model.FlashSale) (model.FlashSale, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Create"))
+		return flashSaleInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO flash_sales (name, starts_at, ends_at) VALUES ($1, $2, $3) RETURNING id`
+	err = tx.QueryRow(sqlStatement, flashSaleInput.Name, flashSaleInput.StartsAt, flashSaleInput.EndsAt).Scan(&flashSaleInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create flash sale", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Create"))
+		return flashSaleInput, err
+	}
+
+	itemStatement := `INSERT INTO flash_sale_items (flash_sale_id, product_id, sale_price, allocation, per_customer_limit)
+		VALUES ($1, $2, $3, $4, NULLIF($5, 0)) RETURNING id`
+	for i, item := range flashSaleInput.Items {
+		err = tx.QueryRow(itemStatement, flashSaleInput.ID, item.ProductID, item.SalePrice, item.Allocation, item.PerCustomerLimit).Scan(&flashSaleInput.Items[i].ID)
+		if err != nil {
+			repo.Logger.Error("Failed to add flash sale item", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Create"))
+			return flashSaleInput, err
+		}
+		flashSaleInput.Items[i].FlashSaleID = flashSaleInput.ID
+		flashSaleInput.Items[i].Remaining = item.Allocation
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Create"))
+		return flashSaleInput, err
+	}
+	return flashSaleInput, nil
+}
+
+func (repo FlashSaleRepository) Delete(id int) error {
+	sqlStatement := `UPDATE flash_sales SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete flash sale", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+// ReleaseClaims gives the allocation taken by a failed order back.
+func (repo FlashSaleRepository) ReleaseClaims(orderID int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "ReleaseClaims"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "ReleaseClaims"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `WITH released AS (
+			DELETE FROM flash_sale_purchases WHERE order_id = $1 RETURNING flash_sale_item_id, amount
+		)
+		UPDATE flash_sale_items fsi SET sold = fsi.sold - r.amount
+		FROM (SELECT flash_sale_item_id, SUM(amount) AS amount FROM released GROUP BY flash_sale_item_id) r
+		WHERE fsi.id = r.flash_sale_item_id`
+	_, err = tx.Exec(sqlStatement, orderID)
+	if err != nil {
+		repo.Logger.Error("Failed to release flash sale claims", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "ReleaseClaims"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "ReleaseClaims"))
+		return err
+	}
+	return nil
+}
+
+func (repo FlashSaleRepository) getItems(flashSaleID int) ([]model.FlashSaleItem, error) {
+	sqlStatement := `SELECT fsi.id, fsi.flash_sale_id, fsi.product_id, p.name, p.price, fsi.sale_price, fsi.allocation, fsi.sold,
+			COALESCE(fsi.per_customer_limit, 0)
+		FROM flash_sale_items fsi JOIN products p ON p.id = fsi.product_id
+		WHERE fsi.flash_sale_id = $1 AND p.status = 'active' AND p.publish_status = 'published' ORDER BY fsi.id`
+	rows, err := repo.DB.Query(sqlStatement, flashSaleID)
+	if err != nil {
+		repo.Logger.Error("Error retrieving flash sale items", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "getItems"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.FlashSaleItem
+	for rows.Next() {
+		var item model.FlashSaleItem
+		err := rows.Scan(&item.ID, &item.FlashSaleID, &item.ProductID, &item.ProductName, &item.Price, &item.SalePrice,
+			&item.Allocation, &item.Sold, &item.PerCustomerLimit)
+		if err != nil {
+			repo.Logger.Error("Error scanning flash sale item", zap.Error(err), zap.String("Repository", "FlashSale"), zap.String("Function", "getItems"))
+			return nil, err
+		}
+		item.Remaining = item.Allocation - item.Sold
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// claimFlashSales takes the flash sale allocation of an order inside the
+// order transaction. Each item row is locked before the customer's earlier
+// purchases are counted, so concurrent checkouts queue on the row and can
+// neither oversell the allocation nor exceed the per-customer cap. Rows are
+// locked in id order to avoid deadlocks between orders.
+func claimFlashSales(tx *sql.Tx, order model.Order) error {
+	claims := make([]model.FlashSaleClaim, len(order.FlashSales))
+	copy(claims, order.FlashSales)
+	sort.Slice(claims, func(i, j int) bool { return claims[i].FlashSaleItemID < claims[j].FlashSaleItemID })
+
+	for _, claim := range claims {
+		var allocation, sold int
+		var perCustomerLimit sql.NullInt64
+		sqlStatement := `SELECT fsi.allocation, fsi.sold, fsi.per_customer_limit
+			FROM flash_sale_items fsi JOIN flash_sales fs ON fs.id = fsi.flash_sale_id
+			WHERE fsi.id = $1 AND fs.status = 'active' AND fs.starts_at <= NOW() AND fs.ends_at > NOW()
+			FOR UPDATE OF fsi`
+		err := tx.QueryRow(sqlStatement, claim.FlashSaleItemID).Scan(&allocation, &sold, &perCustomerLimit)
+		if err == sql.ErrNoRows {
+			return ErrFlashSaleEnded
+		} else if err != nil {
+			return err
+		}
+		if sold+claim.Amount > allocation {
+			return ErrFlashSaleSoldOut
+		}
+
+		if perCustomerLimit.Valid {
+			var bought int64
+			err = tx.QueryRow(`SELECT COALESCE(SUM(amount), 0) FROM flash_sale_purchases WHERE flash_sale_item_id = $1 AND user_id = $2`,
+				claim.FlashSaleItemID, order.UserID).Scan(&bought)
+			if err != nil {
+				return err
+			}
+			if bought+int64(claim.Amount) > perCustomerLimit.Int64 {
+				return ErrFlashSaleCapacity
+			}
+		}
+
+		_, err = tx.Exec(`UPDATE flash_sale_items SET sold = sold + $1 WHERE id = $2`, claim.Amount, claim.FlashSaleItemID)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`INSERT INTO flash_sale_purchases (flash_sale_item_id, user_id, order_id, amount) VALUES ($1, $2, $3, $4)`,
+			claim.FlashSaleItemID, order.UserID, order.ID, claim.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type LifecycleRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewLifecycleRepository(db *sql.DB, logger *zap.Logger) LifecycleRepository {
+	return LifecycleRepository{DB: db, Logger: logger}
+}
+
+const lifecycleColumns = `id, name, publish_status, publish_at, published_at`
+
+func scanLifecycle(row interface{ Scan(...interface{}) error }) (model.ProductLifecycle, error) {
+	var lifecycle model.ProductLifecycle
+	var publishAt, publishedAt sql.NullTime
+	err := row.Scan(&lifecycle.ProductID, &lifecycle.Name, &lifecycle.PublishStatus, &publishAt, &publishedAt)
+	if publishAt.Valid {
+		lifecycle.PublishAt = &publishAt.Time
+	}
+	if publishedAt.Valid {
+		lifecycle.PublishedAt = &publishedAt.Time
+	}
+	return lifecycle, err
+}
+
+func (repo LifecycleRepository) GetByProductID(productID int) (model.ProductLifecycle, error) {
+	sqlStatement := `SELECT ` + lifecycleColumns + ` FROM products WHERE id = $1 AND status = 'active'`
+	lifecycle, err := scanLifecycle(repo.DB.QueryRow(sqlStatement, productID))
+	if err == sql.ErrNoRows {
+		return model.ProductLifecycle{}, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving product lifecycle", zap.Error(err), zap.String("Repository", "Lifecycle"), zap.String("Function", "GetByProductID"))
+		return lifecycle, err
+	}
+	return lifecycle, nil
+}
+
+// GetAll lists the products in the given publish status, or every product
+// when status is empty. Scheduled products come in publishing order.
+func (repo LifecycleRepository) GetAll(status string, pagination model.Pagination) ([]model.ProductLifecycle, model.Pagination, error) {
+	sqlStatement := `SELECT ` + lifecycleColumns + `, COUNT(*) OVER ()
+		FROM products WHERE status = 'active' AND ($1 = '' OR publish_status::text = $1)
+		ORDER BY publish_at NULLS LAST, id LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, status, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving product lifecycles", zap.Error(err), zap.String("Repository", "Lifecycle"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var lifecycles []model.ProductLifecycle
+	for rows.Next() {
+		var lifecycle model.ProductLifecycle
+		var publishAt, publishedAt sql.NullTime
+		err = rows.Scan(&lifecycle.ProductID, &lifecycle.Name, &lifecycle.PublishStatus, &publishAt, &publishedAt, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning product lifecycle", zap.Error(err), zap.String("Repository", "Lifecycle"), zap.String("Function", "GetAll"))
+			return nil, pagination, err
+		}
+		if publishAt.Valid {
+			lifecycle.PublishAt = &publishAt.Time
+		}
+		if publishedAt.Valid {
+			lifecycle.PublishedAt = &publishedAt.Time
+		}
+		lifecycles = append(lifecycles, lifecycle)
+	}
+	return lifecycles, pagination, rows.Err()
+}
+
+// Update moves a product to a new publish status. published_at is only set
+// the first time a product goes live, so republishing an archived product
+// does not make it new again.
+func (repo LifecycleRepository) Update(productID int, status string, publishAt *time.Time) (model.ProductLifecycle, error) {
+	sqlStatement := `UPDATE products SET publish_status = $2, publish_at = $3,
+			published_at = CASE WHEN $2 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'active'
+		RETURNING ` + lifecycleColumns
+	lifecycle, err := scanLifecycle(repo.DB.QueryRow(sqlStatement, productID, status, publishAt))
+	if err == sql.ErrNoRows {
+		return model.ProductLifecycle{}, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error updating product lifecycle", zap.Error(err), zap.String("Repository", "Lifecycle"), zap.String("Function", "Update"))
+		return lifecycle, err
+	}
+	return lifecycle, nil
+}
+
+// PublishDue publishes the scheduled products whose publish time has passed
+// and returns how many were published.
+func (repo LifecycleRepository) PublishDue() (int64, error) {
+	sqlStatement := `UPDATE products SET publish_status = 'published', published_at = COALESCE(published_at, publish_at), updated_at = NOW()
+		WHERE status = 'active' AND publish_status = 'scheduled' AND publish_at <= NOW()`
+	result, err := repo.DB.Exec(sqlStatement)
+	if err != nil {
+		repo.Logger.Error("Error publishing scheduled products", zap.Error(err), zap.String("Repository", "Lifecycle"), zap.String("Function", "PublishDue"))
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -33,13 +33,32 @@ func (repo OrderRepository) Create(orderInput model.Order) (model.Order, error)
 		}
 	}()
 
-	sqlStatement := `INSERT INTO orders (user_id, address_id, total_amount, total_price, shipping_type, shipping_cost, payment_method) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
-	err = tx.QueryRow(sqlStatement, orderInput.UserID, orderInput.AddressID, orderInput.TotalAmount, orderInput.TotalPrice, orderInput.ShippingType, orderInput.ShippingCost, orderInput.PaymentMethod).Scan(&orderInput.ID)
+	sqlStatement := `INSERT INTO orders (user_id, address_id, total_amount, total_price, shipping_type, shipping_cost, payment_method, coupon_id, discount_amount, promotion_discount,
+			currency_code, exchange_rate, currency_total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, 0), $9, $10, $11, $12, $13) RETURNING id`
+	err = tx.QueryRow(sqlStatement, orderInput.UserID, orderInput.AddressID, orderInput.TotalAmount, orderInput.TotalPrice, orderInput.ShippingType, orderInput.ShippingCost, orderInput.PaymentMethod,
+		orderInput.CouponID, orderInput.Discount, orderInput.PromotionDiscount, orderInput.Currency, orderInput.ExchangeRate, orderInput.CurrencyTotal).Scan(&orderInput.ID)
 	if err != nil {
 		repo.Logger.Error("Failed to create order", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "Create"))
 		return orderInput, err
 	}
 
+	if len(orderInput.FlashSales) > 0 {
+		err = claimFlashSales(tx, orderInput)
+		if err != nil {
+			repo.Logger.Error("Failed to claim flash sale allocation", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "Create"))
+			return orderInput, err
+		}
+	}
+
+	if orderInput.CouponID != 0 {
+		err = redeemCoupon(tx, orderInput)
+		if err != nil {
+			repo.Logger.Error("Failed to redeem coupon", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "Create"))
+			return orderInput, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "Create"))
 		return orderInput, err
@@ -64,8 +83,8 @@ func (repo OrderRepository) AddOrderItem(orderItemInput model.OrderItem) (model.
 		}
 	}()
 
-	sqlStatement := `INSERT INTO order_items (order_id, product_id, amount, subtotal) VALUES ($1, $2, $3, $4) RETURNING id`
-	err = tx.QueryRow(sqlStatement, orderItemInput.OrderID, orderItemInput.ProductID, orderItemInput.Amount, orderItemInput.SubTotal).Scan(&orderItemInput.ID)
+	sqlStatement := `INSERT INTO order_items (order_id, product_id, amount, subtotal, discount) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	err = tx.QueryRow(sqlStatement, orderItemInput.OrderID, orderItemInput.ProductID, orderItemInput.Amount, orderItemInput.SubTotal, orderItemInput.Discount).Scan(&orderItemInput.ID)
 	if err != nil {
 		repo.Logger.Error("Failed to add order item", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "Create"))
 		return orderItemInput, err
@@ -109,6 +128,65 @@ func (repo OrderRepository) AddOrderItemVariant(variantInput model.OrderItemVari
 	return nil
 }
 
+// AddOrderPromotions snapshots the promotions an order item was bought with.
+func (repo OrderRepository) AddOrderPromotions(promotions []model.OrderPromotion) error {
+	if len(promotions) == 0 {
+		return nil
+	}
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "AddOrderPromotions"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "AddOrderPromotions"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO order_promotions (order_id, order_item_id, source, promotion_id, name, amount) VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6)`
+	for _, promotion := range promotions {
+		_, err = tx.Exec(sqlStatement, promotion.OrderID, promotion.OrderItemID, promotion.Source, promotion.PromotionID, promotion.Name, promotion.Amount)
+		if err != nil {
+			repo.Logger.Error("Failed to add order promotion", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "AddOrderPromotions"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Order"), zap.String("Function", "AddOrderPromotions"))
+		return err
+	}
+	return nil
+}
+
+func (repo OrderRepository) GetOrderPromotions(orderID int) ([]model.OrderPromotion, error) {
+	sqlStatement := `SELECT id, order_id, COALESCE(order_item_id, 0), source, promotion_id, name, amount FROM order_promotions WHERE order_id = $1 ORDER BY id`
+	rows, err := repo.DB.Query(sqlStatement, orderID)
+	if err != nil {
+		repo.Logger.Error("Failed to get order promotions", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "GetOrderPromotions"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []model.OrderPromotion
+	for rows.Next() {
+		var promotion model.OrderPromotion
+		err = rows.Scan(&promotion.ID, &promotion.OrderID, &promotion.OrderItemID, &promotion.Source, &promotion.PromotionID, &promotion.Name, &promotion.Amount)
+		if err != nil {
+			repo.Logger.Error("Failed to scan order promotion", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "GetOrderPromotions"))
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
+
 func (repo OrderRepository) UpdateOrderStatus(orderId int, orderStatus string) error {
 	tx, err := repo.DB.Begin()
 	if err != nil {
@@ -142,8 +220,12 @@ func (repo OrderRepository) UpdateOrderStatus(orderId int, orderStatus string) e
 
 func (repo OrderRepository) GetByID(id int) (model.Order, error) {
 	var order model.Order
-	sqlStatement := `SELECT id, address_id, shipping_type, total_amount, total_price, order_status FROM orders WHERE id = $1`
-	err := repo.DB.QueryRow(sqlStatement, id).Scan(&order.ID, &order.AddressID, &order.ShippingType, &order.TotalAmount, &order.TotalPrice, &order.OrderStatus)
+	sqlStatement := `SELECT o.id, o.user_id, o.address_id, o.shipping_type, o.total_amount, o.total_price, o.order_status,
+			COALESCE(o.coupon_id, 0), COALESCE(c.code, ''), o.discount_amount, o.promotion_discount,
+			o.currency_code, o.exchange_rate, COALESCE(o.currency_total, o.total_price)
+		FROM orders o LEFT JOIN coupons c ON c.id = o.coupon_id WHERE o.id = $1`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&order.ID, &order.UserID, &order.AddressID, &order.ShippingType, &order.TotalAmount, &order.TotalPrice, &order.OrderStatus,
+		&order.CouponID, &order.CouponCode, &order.Discount, &order.PromotionDiscount, &order.Currency, &order.ExchangeRate, &order.CurrencyTotal)
 	if err == sql.ErrNoRows {
 		return order, nil
 	} else if err != nil {
@@ -156,7 +238,9 @@ func (repo OrderRepository) GetByID(id int) (model.Order, error) {
 
 func (repo OrderRepository) GetByUserID(userID string) ([]model.Order, error) {
 	var order []model.Order
-	sqlStatement := `SELECT id, total_amount, total_price, order_status FROM orders WHERE user_id = $1`
+	sqlStatement := `SELECT o.id, o.total_amount, o.total_price, o.order_status, COALESCE(c.code, ''), o.discount_amount, o.promotion_discount,
+			o.currency_code, o.exchange_rate, COALESCE(o.currency_total, o.total_price)
+		FROM orders o LEFT JOIN coupons c ON c.id = o.coupon_id WHERE o.user_id = $1`
 	rows, err := repo.DB.Query(sqlStatement, userID)
 	if err != nil {
 		repo.Logger.Error("Failed to get order by user ID", zap.Error(err), zap.String(
@@ -167,7 +251,8 @@ func (repo OrderRepository) GetByUserID(userID string) ([]model.Order, error) {
 
 	for rows.Next() {
 		var o model.Order
-		err := rows.Scan(&o.ID, &o.TotalAmount, &o.TotalPrice, &o.OrderStatus)
+		err := rows.Scan(&o.ID, &o.TotalAmount, &o.TotalPrice, &o.OrderStatus, &o.CouponCode, &o.Discount, &o.PromotionDiscount,
+			&o.Currency, &o.ExchangeRate, &o.CurrencyTotal)
 		if err != nil {
 			repo.Logger.Error("Failed to scan order", zap.Error(err), zap.String("repository", "order"),
 				zap.String("Function", "GetByUserID"))
@@ -181,7 +266,7 @@ func (repo OrderRepository) GetByUserID(userID string) ([]model.Order, error) {
 
 func (repo OrderRepository) GetOrderItems(orderId int) ([]model.OrderItem, error) {
 	var orderItems []model.OrderItem
-	sqlStatement := `SELECT id, order_id, product_id, amount, subtotal FROM order_items WHERE order_id = $1`
+	sqlStatement := `SELECT id, order_id, product_id, amount, subtotal, discount FROM order_items WHERE order_id = $1`
 	rows, err := repo.DB.Query(sqlStatement, orderId)
 	if err != nil {
 		repo.Logger.Error("Failed to get order items by order ID", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "GetOrderItems"))
@@ -191,7 +276,7 @@ func (repo OrderRepository) GetOrderItems(orderId int) ([]model.OrderItem, error
 
 	for rows.Next() {
 		var orderItem model.OrderItem
-		err = rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.ProductID, &orderItem.Amount, &orderItem.SubTotal)
+		err = rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.ProductID, &orderItem.Amount, &orderItem.SubTotal, &orderItem.Discount)
 		if err != nil {
 			repo.Logger.Error("Failed to scan order item", zap.Error(err), zap.String("repository", "order"),
 				zap.String("Function", "GetOrderItems"))
@@ -209,6 +294,19 @@ func (repo OrderRepository) GetOrderItems(orderId int) ([]model.OrderItem, error
 	return orderItems, nil
 }
 
+func (repo OrderRepository) GetOrderItemByID(id int) (model.OrderItem, error) {
+	var orderItem model.OrderItem
+	sqlStatement := `SELECT id, order_id, product_id, amount, subtotal FROM order_items WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.ProductID, &orderItem.Amount, &orderItem.SubTotal)
+	if err == sql.ErrNoRows {
+		return orderItem, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get order item by ID", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "GetOrderItemByID"))
+		return orderItem, err
+	}
+	return orderItem, nil
+}
+
 func (repo OrderRepository) GetOrderItemVariants(itemId int) ([]model.OrderItemVariant, error) {
 	var orderItemVariants []model.OrderItemVariant
 	sqlStatement := `SELECT id, variant_id, option_id price FROM order_item_variants
@@ -243,3 +341,17 @@ func (repo OrderRepository) CountProduct(productID int) (int, error) {
 	}
 	return count, nil
 }
+
+// HasPurchased reports whether the user has a successful order containing
+// the product.
+func (repo OrderRepository) HasPurchased(userID string, productID int) (bool, error) {
+	sqlStatement := `SELECT EXISTS (SELECT 1 FROM order_items oi JOIN orders o ON o.id = oi.order_id
+		WHERE o.user_id = $1 AND oi.product_id = $2 AND o.order_status = 'success' AND oi.status = 'active')`
+	var purchased bool
+	err := repo.DB.QueryRow(sqlStatement, userID, productID).Scan(&purchased)
+	if err != nil {
+		repo.Logger.Error("Failed to check purchase", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "HasPurchased"))
+		return false, err
+	}
+	return purchased, nil
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type PriceHistoryRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewPriceHistoryRepository(db *sql.DB, logger *zap.Logger) PriceHistoryRepository {
+	return PriceHistoryRepository{DB: db, Logger: logger}
+}
+
+func (repo PriceHistoryRepository) GetByProduct(productID int, pagination model.Pagination) ([]model.PriceHistory, model.Pagination, error) {
+	sqlStatement := `SELECT id, product_id, price, discount, final_price, source, recorded_at FROM price_history
+		WHERE product_id = $1 ORDER BY recorded_at DESC, id DESC LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, productID, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving price history", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetByProduct"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var history []model.PriceHistory
+	for rows.Next() {
+		var entry model.PriceHistory
+		err := rows.Scan(&entry.ID, &entry.ProductID, &entry.Price, &entry.Discount, &entry.FinalPrice, &entry.Source, &entry.RecordedAt)
+		if err != nil {
+			repo.Logger.Error("Error scanning price history", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetByProduct"))
+			return nil, pagination, err
+		}
+		history = append(history, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, pagination, err
+	}
+
+	err = repo.DB.QueryRow(`SELECT COUNT(*) FROM price_history WHERE product_id = $1`, productID).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting price history", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetByProduct"))
+		return nil, pagination, err
+	}
+	return history, pagination, nil
+}
+
+// GetPriorPrices returns, for each product, the lowest price applied in the
+// window before its current reduction started: the reference price a
+// discount is shown against. Earlier product discounts, promotions and
+// flash sales count, as recorded in final_price. The current reduction
+// starts at the first reduced entry after the last full price one; a
+// product without a reduction looks back from now. The price in effect at
+// the start of the window counts too.
+func (repo PriceHistoryRepository) GetPriorPrices(productIDs []int, window time.Duration) (map[int]model.Money, error) {
+	sqlStatement := `WITH h AS (
+			SELECT id, product_id, price, final_price, recorded_at FROM price_history WHERE product_id = ANY($1)
+		), reduction AS (
+			SELECT h.product_id, MIN(h.recorded_at) AS started_at FROM h
+			WHERE h.final_price < h.price AND h.recorded_at > COALESCE((SELECT MAX(f.recorded_at) FROM h f
+				WHERE f.product_id = h.product_id AND f.final_price >= f.price), '-infinity')
+			GROUP BY h.product_id
+		), windows AS (
+			SELECT p.id AS product_id, COALESCE(r.started_at, NOW()) AS ends_at
+			FROM unnest($1::integer[]) AS p(id) LEFT JOIN reduction r ON r.product_id = p.id
+		)
+		SELECT w.product_id, MIN(x.final_price) FROM windows w
+		JOIN LATERAL (
+			SELECT h.final_price FROM h WHERE h.product_id = w.product_id
+				AND h.recorded_at >= w.ends_at - make_interval(secs => $2) AND h.recorded_at < w.ends_at
+			UNION ALL
+			(SELECT h.final_price FROM h WHERE h.product_id = w.product_id AND h.recorded_at < w.ends_at - make_interval(secs => $2)
+				ORDER BY h.recorded_at DESC, h.id DESC LIMIT 1)
+		) x ON true
+		GROUP BY w.product_id`
+	rows, err := repo.DB.Query(sqlStatement, pq.Array(productIDs), window.Seconds())
+	if err != nil {
+		repo.Logger.Error("Error retrieving prior prices", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetPriorPrices"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	prior := make(map[int]model.Money, len(productIDs))
+	for rows.Next() {
+		var productID int
+		var price model.Money
+		if err := rows.Scan(&productID, &price); err != nil {
+			repo.Logger.Error("Error scanning prior price", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetPriorPrices"))
+			return nil, err
+		}
+		prior[productID] = price
+	}
+	return prior, rows.Err()
+}
+
+// GetNextPriceChange returns how long until the next promotion or flash
+// sale starts or ends, and false when none is scheduled.
+func (repo PriceHistoryRepository) GetNextPriceChange() (time.Duration, bool, error) {
+	sqlStatement := `SELECT EXTRACT(EPOCH FROM MIN(t) - NOW()) FROM (
+			SELECT starts_at AS t FROM promotions WHERE status = 'active' AND starts_at > NOW()
+			UNION ALL SELECT ends_at FROM promotions WHERE status = 'active' AND ends_at > NOW()
+			UNION ALL SELECT starts_at FROM flash_sales WHERE status = 'active' AND starts_at > NOW()
+			UNION ALL SELECT ends_at FROM flash_sales WHERE status = 'active' AND ends_at > NOW()
+		) changes`
+	var seconds sql.NullFloat64
+	err := repo.DB.QueryRow(sqlStatement).Scan(&seconds)
+	if err != nil {
+		repo.Logger.Error("Error retrieving next price change", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetNextPriceChange"))
+		return 0, false, err
+	}
+	if !seconds.Valid {
+		return 0, false, nil
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), true, nil
+}
+
+// GetLatestPrices returns the last recorded final price of every product.
+func (repo PriceHistoryRepository) GetLatestPrices() (map[int]model.Money, error) {
+	sqlStatement := `SELECT DISTINCT ON (product_id) product_id, final_price FROM price_history
+		ORDER BY product_id, recorded_at DESC, id DESC`
+	rows, err := repo.DB.Query(sqlStatement)
+	if err != nil {
+		repo.Logger.Error("Error retrieving latest prices", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetLatestPrices"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	latest := make(map[int]model.Money)
+	for rows.Next() {
+		var productID int
+		var price model.Money
+		if err := rows.Scan(&productID, &price); err != nil {
+			repo.Logger.Error("Error scanning latest price", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "GetLatestPrices"))
+			return nil, err
+		}
+		latest[productID] = price
+	}
+	return latest, rows.Err()
+}
+
+func (repo PriceHistoryRepository) Record(entries []model.PriceHistory) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "Record"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "Record"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO price_history (product_id, price, discount, final_price, source) VALUES ($1, $2, $3, $4, $5)`
+	for _, entry := range entries {
+		_, err = tx.Exec(sqlStatement, entry.ProductID, entry.Price, entry.Discount, entry.FinalPrice, entry.Source)
+		if err != nil {
+			repo.Logger.Error("Failed to record price", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "Record"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "PriceHistory"), zap.String("Function", "Record"))
+		return err
+	}
+	return nil
+}
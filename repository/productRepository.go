@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"go.uber.org/zap"
 )
@@ -21,10 +20,10 @@ func NewProductRepository(db *sql.DB, logger *zap.Logger) ProductRepository {
 
 func (repo ProductRepository) GetByID(id int) (model.Product, error) {
 	var product model.Product
-	sqlStatement := `SELECT id, name, description, price, discount, rating, photo_url, has_variant, total_stock FROM products WHERE id = $1 AND status = 'active'`
+	sqlStatement := `SELECT id, name, description, COALESCE(category_id, 0), price, discount, rating, photo_url, has_variant, total_stock, is_best_selling, COALESCE(brand_id, 0), slug, meta_title, meta_description, publish_status FROM products WHERE id = $1 AND status = 'active'`
 
 	repo.Logger.Info("running query", zap.String("query", sqlStatement), zap.String("Repository", "Product"), zap.String("Function", "GetByID"))
-	err := repo.DB.QueryRow(sqlStatement, id).Scan(&product.ID, &product.Name, &product.Description, &product.Price, &product.Discount, &product.Rating, &product.PhotoURL, &product.HasVariant, &product.TotalStock)
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&product.ID, &product.Name, &product.Description, &product.CategoryID, &product.Price, &product.Discount, &product.Rating, &product.PhotoURL, &product.HasVariant, &product.TotalStock, &product.SpecialProduct.IsBestSelling, &product.BrandID, &product.Slug, &product.MetaTitle, &product.MetaDescription, &product.PublishStatus)
 	if err == sql.ErrNoRows {
 		repo.Logger.Info("product not found",
 			zap.Int("product id", id),
@@ -42,7 +41,6 @@ func (repo ProductRepository) GetByID(id int) (model.Product, error) {
 		return product, err
 	}
 
-	product.PriceAfterDiscount = helper.CalculateDiscountPrice(product.Price, product.Discount)
 	return product, nil
 }
 
@@ -52,9 +50,9 @@ func (repo ProductRepository) GetAll(productFilter model.ProductDTO, pagination
 
 	// Build base SQL query
 	sqlStatement := `
-        SELECT id, name, description, price, discount, rating, photo_url, has_variant, total_stock 
+        SELECT id, name, description, COALESCE(category_id, 0), price, discount, rating, photo_url, has_variant, total_stock, is_best_selling, COALESCE(brand_id, 0), slug
         FROM products
-        WHERE status = 'active'
+        WHERE status = 'active' AND publish_status = 'published'
     `
 
 	// Add filters if provided
@@ -68,6 +66,26 @@ func (repo ProductRepository) GetAll(productFilter model.ProductDTO, pagination
 		filterArgs = append(filterArgs, productFilter.CategoryID)
 	}
 
+	if productFilter.BrandID != 0 {
+		sqlStatement += " AND brand_id = $" + fmt.Sprint(len(filterArgs)+1)
+		filterArgs = append(filterArgs, productFilter.BrandID)
+	}
+
+	var attributeSQL string
+	attributeSQL, filterArgs = AttributeFilterSQL("products.id", productFilter.Attributes, filterArgs)
+	sqlStatement += attributeSQL
+
+	var collectionSQL string
+	collectionSQL, filterArgs = CollectionFilterSQL("products", productFilter, filterArgs)
+	sqlStatement += collectionSQL
+
+	// A manual collection lists its products in the order it was given
+	if productFilter.CollectionID != 0 {
+		sqlStatement += ` ORDER BY (SELECT position FROM collection_products
+			WHERE collection_id = $` + fmt.Sprint(len(filterArgs)+1) + ` AND product_id = products.id), id`
+		filterArgs = append(filterArgs, productFilter.CollectionID)
+	}
+
 	// Add pagination
 	sqlStatement += " LIMIT $" + fmt.Sprint(len(filterArgs)+1) + " OFFSET $" + fmt.Sprint(len(filterArgs)+2)
 	filterArgs = append(filterArgs, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
@@ -104,12 +122,16 @@ func (repo ProductRepository) GetAll(productFilter model.ProductDTO, pagination
 			&product.ID,
 			&product.Name,
 			&product.Description,
+			&product.CategoryID,
 			&product.Price,
 			&product.Discount,
 			&product.Rating,
 			&product.PhotoURL,
 			&product.HasVariant,
 			&product.TotalStock,
+			&product.SpecialProduct.IsBestSelling,
+			&product.BrandID,
+			&product.Slug,
 		); err != nil {
 			repo.Logger.Error("Error scanning product", zap.Error(err),
 				zap.String("Repository", "Product"),
@@ -122,18 +144,7 @@ func (repo ProductRepository) GetAll(productFilter model.ProductDTO, pagination
 		if err != nil {
 			return nil, pagination, err
 		}
-		orderedProducts, err := repo.CountProductFromOrder(product.ID)
-		if err != nil {
-			return nil, pagination, err
-		}
-		product.PriceAfterDiscount = helper.CalculateDiscountPrice(product.Price, product.Discount)
-
 		product.SpecialProduct.IsNewProduct = isNewProduct
-		if orderedProducts > 10 {
-			product.SpecialProduct.IsBestSelling = true
-		} else {
-			product.SpecialProduct.IsBestSelling = false
-		}
 		products = append(products, product)
 	}
 
@@ -158,7 +169,7 @@ func (repo ProductRepository) GetAll(productFilter model.ProductDTO, pagination
 
 func (repo ProductRepository) CountProducts(productFilter model.ProductDTO) (int, error) {
 	// Base query
-	countQuery := `SELECT COUNT(*) FROM products WHERE status = 'active'`
+	countQuery := `SELECT COUNT(*) FROM products WHERE status = 'active' AND publish_status = 'published'`
 	countArgs := []interface{}{}
 	countArgIndex := 1
 
@@ -175,6 +186,18 @@ func (repo ProductRepository) CountProducts(productFilter model.ProductDTO) (int
 		countArgIndex++
 	}
 
+	if productFilter.BrandID != 0 {
+		countQuery += ` AND brand_id = $` + fmt.Sprint(countArgIndex)
+		countArgs = append(countArgs, productFilter.BrandID)
+		countArgIndex++
+	}
+
+	attributeSQL, countArgs := AttributeFilterSQL("products.id", productFilter.Attributes, countArgs)
+	countQuery += attributeSQL
+
+	collectionSQL, countArgs := CollectionFilterSQL("products", productFilter, countArgs)
+	countQuery += collectionSQL
+
 	// Execute count query
 	var totalCount int
 	repo.Logger.Info("Execute count query", zap.String("query", countQuery), zap.String("Repository", "Product"), zap.String("Function", "CountProducts"))
@@ -191,7 +214,7 @@ func (repo ProductRepository) CountProducts(productFilter model.ProductDTO) (int
 }
 
 func (repo ProductRepository) GetNewProducts(id int) (bool, error) {
-	sqlStatement := `SELECT(created_at > NOW() - INTERVAL '30 days') AS is_new_product FROM products WHERE id = $1 AND status = 'active';`
+	sqlStatement := `SELECT COALESCE(published_at > NOW() - INTERVAL '30 days', false) AS is_new_product FROM products WHERE id = $1 AND status = 'active';`
 	var isNewProduct bool
 
 	repo.Logger.Info("run sql statement", zap.String("query", sqlStatement), zap.String("Repository", "Product"), zap.String("Function", "GetNewProduct"))
@@ -211,73 +234,81 @@ func (repo ProductRepository) GetNewProducts(id int) (bool, error) {
 	return isNewProduct, nil
 }
 
-func (repo ProductRepository) GetWeeklyPromo(pagination model.Pagination) ([]model.WeeklyPromo, model.Pagination, error) {
-	sqlStatement := `SELECT id, product_id, start_date, end_date, promo_discount 
-			FROM weekly_promos WHERE status = 'active' 
-			AND start_date <= CURRENT_DATE AND end_date >= date_trunc('week', CURRENT_DATE)`
+// activePromotionCondition matches products that have at least one running promotion.
+const activePromotionCondition = `EXISTS (
+		SELECT 1 FROM promotions pr
+		WHERE pr.status = 'active' AND pr.starts_at <= NOW() AND pr.ends_at > NOW()
+		AND (pr.target_type = 'all'
+			OR (pr.target_type = 'category' AND pr.target_id = p.category_id)
+			OR (pr.target_type = 'product' AND pr.target_id = p.id))
+	)`
 
-	var weeklyPromos []model.WeeklyPromo
+func (repo ProductRepository) GetOnPromotion(pagination model.Pagination) ([]model.Product, model.Pagination, error) {
+	var products []model.Product
+	sqlStatement := `SELECT p.id, p.name, p.description, COALESCE(p.category_id, 0), p.price, p.discount, p.rating, p.photo_url, p.has_variant, p.total_stock, p.is_best_selling, COALESCE(p.brand_id, 0), p.slug
+		FROM products p
+		WHERE p.status = 'active' AND p.publish_status = 'published' AND ` + activePromotionCondition + `
+		ORDER BY p.id LIMIT $1 OFFSET $2`
 
-	repo.Logger.Info("run sql statement", zap.String("query", sqlStatement), zap.String("Repository", "Product"), zap.String("Function", "GetWeeklyPromo"))
-	rows, err := repo.DB.Query(sqlStatement)
+	repo.Logger.Info("run sql statement", zap.String("query", sqlStatement), zap.String("Repository", "Product"), zap.String("Function", "GetOnPromotion"))
+	rows, err := repo.DB.Query(sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
 	if err != nil {
-		repo.Logger.Error("Error getting weekly promo", zap.Error(err),
+		repo.Logger.Error("Error getting products on promotion", zap.Error(err),
 			zap.String("Repository", "Product"),
-			zap.String("Function", "GetWeeklyPromo"),
+			zap.String("Function", "GetOnPromotion"),
 			zap.Duration("duration", time.Since(startTime)))
 		return nil, pagination, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		var weeklyPromo model.WeeklyPromo
-		err = rows.Scan(&weeklyPromo.ID, &weeklyPromo.ProductID, &weeklyPromo.StartDate, &weeklyPromo.EndDate, &weeklyPromo.PromoDiscount)
+		var product model.Product
+		err = rows.Scan(&product.ID, &product.Name, &product.Description, &product.CategoryID, &product.Price, &product.Discount,
+			&product.Rating, &product.PhotoURL, &product.HasVariant, &product.TotalStock, &product.SpecialProduct.IsBestSelling, &product.BrandID, &product.Slug)
 		if err != nil {
-			repo.Logger.Error("Error scanning weekly promo", zap.Error(err),
+			repo.Logger.Error("Error scanning product on promotion", zap.Error(err),
 				zap.String("Repository", "Product"),
-				zap.String("Function", "GetWeeklyPromo"),
+				zap.String("Function", "GetOnPromotion"),
 				zap.Duration("duration", time.Since(startTime)))
 			return nil, pagination, err
 		}
-		weeklyPromos = append(weeklyPromos, weeklyPromo)
+		products = append(products, product)
 	}
-	return weeklyPromos, pagination, nil
-}
 
-func (repo ProductRepository) GetPromoProduct(productId int) (model.WeeklyPromo, error) {
-	var weeklyPromo model.WeeklyPromo
-	sqlStatement := `SELECT id, start_date, end_date, promo_discount 
-	FROM weekly_promos WHERE product_id = $1 AND status = 'active' 
-	AND start_date <= CURRENT_DATE AND end_date >= date_trunc('week', CURRENT_DATE)`
-
-	repo.Logger.Info("running query", zap.String("query", sqlStatement), zap.String("Repository", "Product"), zap.String("Function", "GetByID"))
-	err := repo.DB.QueryRow(sqlStatement, productId).Scan(&weeklyPromo.ID, &weeklyPromo.StartDate, &weeklyPromo.EndDate, &weeklyPromo.PromoDiscount)
-	if err == sql.ErrNoRows {
-		repo.Logger.Info("product not found",
-			zap.Int("product id", productId),
-			zap.String("Repository", "Product"),
-			zap.String("Function", "GetByID"),
-			zap.Duration("duration", time.Since(startTime)))
-
-		return weeklyPromo, nil
-	} else if err != nil {
-		repo.Logger.Error("error getting product by id",
-			zap.Error(err),
+	countQuery := `SELECT COUNT(*) FROM products p WHERE p.status = 'active' AND p.publish_status = 'published' AND ` + activePromotionCondition
+	err = repo.DB.QueryRow(countQuery).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting products on promotion", zap.Error(err),
 			zap.String("Repository", "Product"),
-			zap.String("Function", "GetByID"),
+			zap.String("Function", "GetOnPromotion"),
 			zap.Duration("duration", time.Since(startTime)))
-		return weeklyPromo, err
+		return nil, pagination, err
 	}
-	return weeklyPromo, nil
+	return products, pagination, nil
 }
 
-func (repo ProductRepository) CountProductFromOrder(productID int) (int, error) {
-	sqlStatement := `SELECT COUNT(*) FROM order_items WHERE product_id = $1`
-	var count int
-	err := repo.DB.QueryRow(sqlStatement, productID).Scan(&count)
+// GetAllActive returns every active product without pagination, for
+// background jobs.
+func (repo ProductRepository) GetAllActive() ([]model.Product, error) {
+	sqlStatement := `SELECT id, name, description, COALESCE(category_id, 0), price, discount, rating, photo_url, has_variant, total_stock, is_best_selling, COALESCE(brand_id, 0), slug
+		FROM products WHERE status = 'active' ORDER BY id`
+	rows, err := repo.DB.Query(sqlStatement)
 	if err != nil {
-		repo.Logger.Error("Failed to count products by ID", zap.Error(err), zap.String("repository", "Order"), zap.String("Function", "CountProduct"))
-		return 0, err
+		repo.Logger.Error("Error retrieving active products", zap.Error(err), zap.String("Repository", "Product"), zap.String("Function", "GetAllActive"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []model.Product
+	for rows.Next() {
+		var product model.Product
+		err = rows.Scan(&product.ID, &product.Name, &product.Description, &product.CategoryID, &product.Price, &product.Discount,
+			&product.Rating, &product.PhotoURL, &product.HasVariant, &product.TotalStock, &product.SpecialProduct.IsBestSelling, &product.BrandID, &product.Slug)
+		if err != nil {
+			repo.Logger.Error("Error scanning active product", zap.Error(err), zap.String("Repository", "Product"), zap.String("Function", "GetAllActive"))
+			return nil, err
+		}
+		products = append(products, product)
 	}
-	return count, nil
+	return products, rows.Err()
 }
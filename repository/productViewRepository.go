@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type ProductViewRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewProductViewRepository(db *sql.DB, logger *zap.Logger) ProductViewRepository {
+	return ProductViewRepository{DB: db, Logger: logger}
+}
+
+// Record stores one view of a product. userID and guestToken may be empty.
+func (repo ProductViewRepository) Record(productID int, userID, guestToken string) error {
+	sqlStatement := `INSERT INTO product_views (product_id, user_id, guest_token) VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))`
+	_, err := repo.DB.Exec(sqlStatement, productID, userID, guestToken)
+	if err != nil {
+		repo.Logger.Error("Failed to record product view", zap.Error(err), zap.String("Repository", "ProductView"), zap.String("Function", "Record"))
+		return err
+	}
+	return nil
+}
+
+// GetRecentlyViewed returns the latest distinct products viewed by the user,
+// or by the guest token when there is no user, newest first.
+func (repo ProductViewRepository) GetRecentlyViewed(userID, guestToken string, limit int) ([]model.RecentlyViewed, error) {
+	column, owner := "user_id", userID
+	if userID == "" {
+		column, owner = "guest_token", guestToken
+	}
+	sqlStatement := `SELECT v.product_id, MAX(v.viewed_at) AS last_viewed FROM product_views v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.` + column + ` = $1 AND p.status = 'active' AND p.publish_status = 'published'
+		GROUP BY v.product_id ORDER BY last_viewed DESC LIMIT $2`
+	rows, err := repo.DB.Query(sqlStatement, owner, limit)
+	if err != nil {
+		repo.Logger.Error("Error retrieving recently viewed products", zap.Error(err), zap.String("Repository", "ProductView"), zap.String("Function", "GetRecentlyViewed"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []model.RecentlyViewed
+	for rows.Next() {
+		var view model.RecentlyViewed
+		if err := rows.Scan(&view.Product.ID, &view.ViewedAt); err != nil {
+			repo.Logger.Error("Error scanning recently viewed product", zap.Error(err), zap.String("Repository", "ProductView"), zap.String("Function", "GetRecentlyViewed"))
+			return nil, err
+		}
+		views = append(views, view)
+	}
+	return views, rows.Err()
+}
+
+// GetTrending ranks active, in stock products by views since the given time.
+func (repo ProductViewRepository) GetTrending(since time.Time, pagination model.Pagination) ([]model.TrendingProduct, model.Pagination, error) {
+	sqlStatement := `SELECT v.product_id, COUNT(*) AS views, COUNT(*) OVER () FROM product_views v
+		JOIN products p ON p.id = v.product_id
+		WHERE v.viewed_at >= $1 AND p.status = 'active' AND p.publish_status = 'published' AND p.total_stock > 0
+		GROUP BY v.product_id ORDER BY views DESC, v.product_id LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, since, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving trending products", zap.Error(err), zap.String("Repository", "ProductView"), zap.String("Function", "GetTrending"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var trending []model.TrendingProduct
+	for rows.Next() {
+		var product model.TrendingProduct
+		if err := rows.Scan(&product.Product.ID, &product.ViewCount, &pagination.CountData); err != nil {
+			repo.Logger.Error("Error scanning trending product", zap.Error(err), zap.String("Repository", "ProductView"), zap.String("Function", "GetTrending"))
+			return nil, pagination, err
+		}
+		trending = append(trending, product)
+	}
+	return trending, pagination, rows.Err()
+}
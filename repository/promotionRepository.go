@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type PromotionRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewPromotionRepository(db *sql.DB, logger *zap.Logger) PromotionRepository {
+	return PromotionRepository{DB: db, Logger: logger}
+}
+
+const promotionColumns = `id, name, COALESCE(description, ''), target_type, COALESCE(target_id, 0), discount_type, discount_value,
+	priority, stackable, starts_at, ends_at`
+
+func scanPromotion(scanner interface{ Scan(...interface{}) error }, promotion *model.Promotion) error {
+	return scanner.Scan(&promotion.ID, &promotion.Name, &promotion.Description, &promotion.TargetType, &promotion.TargetID,
+		&promotion.DiscountType, &promotion.DiscountValue, &promotion.Priority, &promotion.Stackable, &promotion.StartsAt, &promotion.EndsAt)
+}
+
+// GetActive returns every promotion running right now.
+func (repo PromotionRepository) GetActive() ([]model.Promotion, error) {
+	sqlStatement := `SELECT ` + promotionColumns + ` FROM promotions
+		WHERE status = 'active' AND starts_at <= NOW() AND ends_at > NOW()`
+	return repo.query("GetActive", sqlStatement)
+}
+
+func (repo PromotionRepository) GetAll(pagination model.Pagination) ([]model.Promotion, model.Pagination, error) {
+	sqlStatement := `SELECT ` + promotionColumns + ` FROM promotions WHERE status = 'active'
+		ORDER BY starts_at DESC, id DESC LIMIT $1 OFFSET $2`
+	promotions, err := repo.query("GetAll", sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM promotions WHERE status = 'active'`
+	err = repo.DB.QueryRow(countQuery).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting promotions", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	return promotions, pagination, nil
+}
+
+func (repo PromotionRepository) GetByID(id int) (model.Promotion, error) {
+	var promotion model.Promotion
+	sqlStatement := `SELECT ` + promotionColumns + ` FROM promotions WHERE id = $1 AND status = 'active'`
+	err := scanPromotion(repo.DB.QueryRow(sqlStatement, id), &promotion)
+	if err == sql.ErrNoRows {
+		return promotion, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get promotion by ID", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "GetByID"))
+		return promotion, err
+	}
+	return promotion, nil
+}
+
+func (repo PromotionRepository) Create(promotionInput model.Promotion) (model.Promotion, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO promotions (name, description, target_type, target_id, discount_type, discount_value, priority, stackable, starts_at, ends_at)
+		VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, 0), $5, $6, $7, $8, $9, $10) RETURNING id`
+	err = tx.QueryRow(sqlStatement, promotionInput.Name, promotionInput.Description, promotionInput.TargetType, promotionInput.TargetID,
+		promotionInput.DiscountType, promotionInput.DiscountValue, promotionInput.Priority, promotionInput.Stackable,
+		promotionInput.StartsAt, promotionInput.EndsAt).Scan(&promotionInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create promotion", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Create"))
+		return promotionInput, err
+	}
+	return promotionInput, nil
+}
+
+func (repo PromotionRepository) Update(promotionInput model.Promotion) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Update"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Update"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `UPDATE promotions SET name = $1, description = NULLIF($2, ''), target_type = $3, target_id = NULLIF($4, 0),
+			discount_type = $5, discount_value = $6, priority = $7, stackable = $8, starts_at = $9, ends_at = $10, updated_at = NOW()
+		WHERE id = $11 AND status = 'active'`
+	_, err = tx.Exec(sqlStatement, promotionInput.Name, promotionInput.Description, promotionInput.TargetType, promotionInput.TargetID,
+		promotionInput.DiscountType, promotionInput.DiscountValue, promotionInput.Priority, promotionInput.Stackable,
+		promotionInput.StartsAt, promotionInput.EndsAt, promotionInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to update promotion", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Update"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Update"))
+		return err
+	}
+	return nil
+}
+
+func (repo PromotionRepository) Delete(id int) error {
+	sqlStatement := `UPDATE promotions SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete promotion", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo PromotionRepository) query(function, sqlStatement string, args ...interface{}) ([]model.Promotion, error) {
+	repo.Logger.Info("running query", zap.String("query", sqlStatement), zap.String("Repository", "Promotion"), zap.String("Function", function))
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving promotions", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", function))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promotions []model.Promotion
+	for rows.Next() {
+		var promotion model.Promotion
+		if err := scanPromotion(rows, &promotion); err != nil {
+			repo.Logger.Error("Error scanning promotion", zap.Error(err), zap.String("Repository", "Promotion"), zap.String("Function", function))
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
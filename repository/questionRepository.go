@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type QuestionRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewQuestionRepository(db *sql.DB, logger *zap.Logger) QuestionRepository {
+	return QuestionRepository{DB: db, Logger: logger}
+}
+
+// questionVoteTables maps vote targets to the tables holding their counts.
+var questionVoteTables = map[string]string{
+	model.QuestionVoteQuestion: "product_questions",
+	model.QuestionVoteAnswer:   "product_answers",
+}
+
+func (repo QuestionRepository) CreateQuestion(questionInput model.Question) (model.Question, error) {
+	sqlStatement := `INSERT INTO product_questions (product_id, user_id, question) VALUES ($1, $2, $3)
+		RETURNING id, moderation_status, created_at`
+	err := repo.DB.QueryRow(sqlStatement, questionInput.ProductID, questionInput.UserID, questionInput.Question).
+		Scan(&questionInput.ID, &questionInput.ModerationStatus, &questionInput.CreatedAt)
+	if err != nil {
+		repo.Logger.Error("Failed to create question", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "CreateQuestion"))
+		return questionInput, err
+	}
+	return questionInput, nil
+}
+
+func (repo QuestionRepository) GetQuestionByID(id int) (model.Question, error) {
+	var question model.Question
+	sqlStatement := `SELECT id, product_id, user_id, question, moderation_status, COALESCE(moderation_note, ''), upvote_count, created_at
+		FROM product_questions WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&question.ID, &question.ProductID, &question.UserID, &question.Question,
+		&question.ModerationStatus, &question.ModerationNote, &question.UpvoteCount, &question.CreatedAt)
+	if err == sql.ErrNoRows {
+		return question, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get question by ID", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetQuestionByID"))
+		return question, err
+	}
+	return question, nil
+}
+
+func (repo QuestionRepository) GetQuestions(questionFilter model.QuestionFilter, pagination model.Pagination) ([]model.Question, model.Pagination, error) {
+	var filterArgs []interface{}
+	sqlStatement := `SELECT q.id, q.product_id, u.name, q.question, q.moderation_status, COALESCE(q.moderation_note, ''),
+			q.upvote_count, q.created_at, COUNT(*) OVER ()
+		FROM product_questions q
+		JOIN users u ON u.id = q.user_id
+		WHERE q.status = 'active'`
+
+	if questionFilter.ProductID != 0 {
+		filterArgs = append(filterArgs, questionFilter.ProductID)
+		sqlStatement += ` AND q.product_id = $` + fmt.Sprint(len(filterArgs))
+	}
+	if questionFilter.ModerationStatus != "" {
+		filterArgs = append(filterArgs, questionFilter.ModerationStatus)
+		sqlStatement += ` AND q.moderation_status = $` + fmt.Sprint(len(filterArgs))
+	}
+
+	switch questionFilter.SortBy {
+	case model.QuestionSortUpvotes:
+		sqlStatement += ` ORDER BY q.upvote_count DESC, q.created_at DESC`
+	default:
+		sqlStatement += ` ORDER BY q.created_at DESC`
+	}
+
+	sqlStatement += " LIMIT $" + fmt.Sprint(len(filterArgs)+1) + " OFFSET $" + fmt.Sprint(len(filterArgs)+2)
+	filterArgs = append(filterArgs, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+
+	rows, err := repo.DB.Query(sqlStatement, filterArgs...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving questions", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetQuestions"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var questions []model.Question
+	for rows.Next() {
+		var question model.Question
+		if err := rows.Scan(&question.ID, &question.ProductID, &question.UserName, &question.Question, &question.ModerationStatus,
+			&question.ModerationNote, &question.UpvoteCount, &question.CreatedAt, &pagination.CountData); err != nil {
+			repo.Logger.Error("Error scanning question", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetQuestions"))
+			return nil, pagination, err
+		}
+		questions = append(questions, question)
+	}
+	return questions, pagination, rows.Err()
+}
+
+func (repo QuestionRepository) CreateAnswer(answerInput model.Answer) (model.Answer, error) {
+	sqlStatement := `INSERT INTO product_answers (question_id, user_id, answer, is_admin, is_verified_buyer, moderation_status, moderated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $4 THEN NOW() END) RETURNING id, created_at`
+	err := repo.DB.QueryRow(sqlStatement, answerInput.QuestionID, answerInput.UserID, answerInput.Answer, answerInput.IsAdmin,
+		answerInput.IsVerifiedBuyer, answerInput.ModerationStatus).Scan(&answerInput.ID, &answerInput.CreatedAt)
+	if err != nil {
+		repo.Logger.Error("Failed to create answer", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "CreateAnswer"))
+		return answerInput, err
+	}
+	return answerInput, nil
+}
+
+func (repo QuestionRepository) GetAnswerByID(id int) (model.Answer, error) {
+	var answer model.Answer
+	sqlStatement := `SELECT id, question_id, user_id, answer, is_admin, is_verified_buyer, moderation_status, COALESCE(moderation_note, ''),
+			upvote_count, created_at
+		FROM product_answers WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&answer.ID, &answer.QuestionID, &answer.UserID, &answer.Answer, &answer.IsAdmin,
+		&answer.IsVerifiedBuyer, &answer.ModerationStatus, &answer.ModerationNote, &answer.UpvoteCount, &answer.CreatedAt)
+	if err == sql.ErrNoRows {
+		return answer, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get answer by ID", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetAnswerByID"))
+		return answer, err
+	}
+	return answer, nil
+}
+
+// GetApprovedAnswers returns the approved answers of the questions, admin
+// answers first and then by upvotes.
+func (repo QuestionRepository) GetApprovedAnswers(questionIDs []int) ([]model.Answer, error) {
+	sqlStatement := `SELECT a.id, a.question_id, u.name, a.answer, a.is_admin, a.is_verified_buyer, a.upvote_count, a.created_at
+		FROM product_answers a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.question_id = ANY($1) AND a.moderation_status = 'approved' AND a.status = 'active'
+		ORDER BY a.is_admin DESC, a.upvote_count DESC, a.created_at`
+	rows, err := repo.DB.Query(sqlStatement, pq.Array(questionIDs))
+	if err != nil {
+		repo.Logger.Error("Error retrieving answers", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetApprovedAnswers"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []model.Answer
+	for rows.Next() {
+		var answer model.Answer
+		if err := rows.Scan(&answer.ID, &answer.QuestionID, &answer.UserName, &answer.Answer, &answer.IsAdmin, &answer.IsVerifiedBuyer,
+			&answer.UpvoteCount, &answer.CreatedAt); err != nil {
+			repo.Logger.Error("Error scanning answer", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetApprovedAnswers"))
+			return nil, err
+		}
+		answers = append(answers, answer)
+	}
+	return answers, rows.Err()
+}
+
+// GetAnswers lists answers with the moderation status, oldest first, for the
+// moderation queue.
+func (repo QuestionRepository) GetAnswers(moderationStatus string, pagination model.Pagination) ([]model.Answer, model.Pagination, error) {
+	sqlStatement := `SELECT a.id, a.question_id, u.name, a.answer, a.is_admin, a.is_verified_buyer, a.moderation_status,
+			COALESCE(a.moderation_note, ''), a.upvote_count, a.created_at, COUNT(*) OVER ()
+		FROM product_answers a
+		JOIN users u ON u.id = a.user_id
+		WHERE a.status = 'active' AND a.moderation_status = $1
+		ORDER BY a.created_at LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, moderationStatus, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving answers", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetAnswers"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var answers []model.Answer
+	for rows.Next() {
+		var answer model.Answer
+		if err := rows.Scan(&answer.ID, &answer.QuestionID, &answer.UserName, &answer.Answer, &answer.IsAdmin, &answer.IsVerifiedBuyer,
+			&answer.ModerationStatus, &answer.ModerationNote, &answer.UpvoteCount, &answer.CreatedAt, &pagination.CountData); err != nil {
+			repo.Logger.Error("Error scanning answer", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "GetAnswers"))
+			return nil, pagination, err
+		}
+		answers = append(answers, answer)
+	}
+	return answers, pagination, rows.Err()
+}
+
+func (repo QuestionRepository) UpdateQuestionStatus(id int, moderationStatus, note string) error {
+	sqlStatement := `UPDATE product_questions SET moderation_status = $1, moderation_note = $2, moderated_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, moderationStatus, note, id)
+	if err != nil {
+		repo.Logger.Error("Failed to update question status", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "UpdateQuestionStatus"))
+		return err
+	}
+	return nil
+}
+
+func (repo QuestionRepository) UpdateAnswerStatus(id int, moderationStatus, note string) error {
+	sqlStatement := `UPDATE product_answers SET moderation_status = $1, moderation_note = $2, moderated_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, moderationStatus, note, id)
+	if err != nil {
+		repo.Logger.Error("Failed to update answer status", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "UpdateAnswerStatus"))
+		return err
+	}
+	return nil
+}
+
+// AddUpvote records an upvote on a question or an answer. It returns false
+// when the user has already upvoted it.
+func (repo QuestionRepository) AddUpvote(targetType string, targetID int, userID string) (bool, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "AddUpvote"))
+		return false, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "AddUpvote"))
+			tx.Rollback()
+		}
+	}()
+
+	result, err := tx.Exec(`INSERT INTO question_votes (target_type, target_id, user_id) VALUES ($1, $2, $3)
+		ON CONFLICT (target_type, target_id, user_id) DO NOTHING`, targetType, targetID, userID)
+	if err != nil {
+		repo.Logger.Error("Failed to insert question vote", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "AddUpvote"))
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		tx.Rollback()
+		return false, err
+	}
+
+	_, err = tx.Exec(`UPDATE `+questionVoteTables[targetType]+` SET upvote_count = upvote_count + 1 WHERE id = $1`, targetID)
+	if err != nil {
+		repo.Logger.Error("Failed to increment upvote count", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "AddUpvote"))
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Question"), zap.String("Function", "AddUpvote"))
+		return false, err
+	}
+	return true, nil
+}
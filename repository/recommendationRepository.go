@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -18,15 +19,20 @@ func NewRecommendationRepository(db *sql.DB, logger *zap.Logger) RecommendationR
 	return RecommendationRepository{DB: db, Logger: logger}
 }
 
+// recommendationLive limits a query to entries that are shown right now.
+const recommendationLive = `r.status = 'active' AND p.status = 'active' AND p.publish_status = 'published'
+	AND (r.starts_at IS NULL OR r.starts_at <= NOW()) AND (r.ends_at IS NULL OR r.ends_at > NOW())`
+
+const recommendationColumns = `r.id, p.id, p.name, COALESCE(r.photo_url, ''), r.is_recommended, r.set_in_banner,
+	r.title, r.subtitle, COALESCE(r.path_url, ''), r.display_order, r.starts_at, r.ends_at`
+
 func (repo *RecommendationRepository) GetRecommendations(recommendFilter model.RecommendationDTO, pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
-	var recommendations []model.Recommendation
 	var filterArgs []interface{}
 	var argIndex = 1
 
-	sqlStatement := `SELECT r.id, p.id, p.name, r.photo_url, r.is_recommended, r.set_in_banner,
-				r.title, r.subtitle FROM recommendations r 
-				JOIN products p ON p.id = r.product_id 
-				WHERE p.status = 'active'`
+	sqlStatement := `SELECT ` + recommendationColumns + ` FROM recommendations r
+				JOIN products p ON p.id = r.product_id
+				WHERE ` + recommendationLive
 
 	if recommendFilter.IsRecommended {
 		sqlStatement += ` AND is_recommended = $` + fmt.Sprint(argIndex)
@@ -39,42 +45,14 @@ func (repo *RecommendationRepository) GetRecommendations(recommendFilter model.R
 		argIndex++
 	}
 
-	sqlStatement += " LIMIT $" + fmt.Sprint(len(filterArgs)+1) + " OFFSET $" + fmt.Sprint(len(filterArgs)+2)
+	sqlStatement += " ORDER BY r.display_order, r.id LIMIT $" + fmt.Sprint(len(filterArgs)+1) + " OFFSET $" + fmt.Sprint(len(filterArgs)+2)
 	filterArgs = append(filterArgs, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
 
-	rows, err := repo.DB.Query(sqlStatement, filterArgs...)
+	recommendations, err := repo.query("GetRecommendations", sqlStatement, filterArgs...)
 	if err != nil {
-		repo.Logger.Error("error getting recommendations", zap.Error(err))
-		return nil, pagination, err
-	}
-	defer rows.Close()
-
-	if err := rows.Err(); err != nil {
-		repo.Logger.Error("Error during rows iteration", zap.Error(err))
 		return nil, pagination, err
 	}
 
-	for rows.Next() {
-		var product model.Product
-		var recommendation model.Recommendation
-
-		if err := rows.Scan(&recommendation.ID,
-			&product.ID,
-			&product.Name,
-			&recommendation.PhotoUrl,
-			&recommendation.IsRecommended,
-			&recommendation.SetInBanner,
-			&recommendation.Title,
-			&recommendation.Subtitle); err != nil {
-			repo.Logger.Error("error scanning rows", zap.Error(err))
-			return nil, pagination, err
-		}
-
-		recommendation.Product = product
-		recommendation.PathUrl = fmt.Sprintf("/api/products/%d", product.ID)
-		recommendations = append(recommendations, recommendation)
-	}
-
 	totalCount, err := repo.CountRecommendations(recommendFilter)
 	if err != nil {
 		return nil, pagination, err
@@ -85,13 +63,12 @@ func (repo *RecommendationRepository) GetRecommendations(recommendFilter model.R
 
 func (repo RecommendationRepository) CountRecommendations(recommendFilter model.RecommendationDTO) (int, error) {
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM recommendations WHERE status = 'active'`
+	countQuery := `SELECT COUNT(*) FROM recommendations r JOIN products p ON p.id = r.product_id WHERE ` + recommendationLive
 	countArgs := []interface{}{}
 	countArgIndex := 1
 
 	if recommendFilter.IsRecommended {
-		countQuery += ` AND is_recommended =
-		 $` + fmt.Sprint(countArgIndex)
+		countQuery += ` AND is_recommended = $` + fmt.Sprint(countArgIndex)
 		countArgs = append(countArgs, recommendFilter.IsRecommended)
 		countArgIndex++
 	}
@@ -103,7 +80,7 @@ func (repo RecommendationRepository) CountRecommendations(recommendFilter model.
 
 	repo.Logger.Info("running query", zap.String("query", countQuery),
 		zap.String("Repository", "Recommendation"),
-		zap.String("Function", "CountCategories"),
+		zap.String("Function", "CountRecommendations"),
 		zap.Int("args_count", len(countArgs)),
 		// Optionally, mask sensitive data in args for logging purposes
 		zap.Any("args", countArgs))
@@ -119,3 +96,320 @@ func (repo RecommendationRepository) CountRecommendations(recommendFilter model.
 
 	return totalCount, nil
 }
+
+// GetAll lists every entry that is not deleted, including scheduled and
+// ended ones, for the admin.
+func (repo RecommendationRepository) GetAll(pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
+	sqlStatement := `SELECT ` + recommendationColumns + ` FROM recommendations r
+		JOIN products p ON p.id = r.product_id
+		WHERE r.status = 'active' ORDER BY r.display_order, r.id LIMIT $1 OFFSET $2`
+	recommendations, err := repo.query("GetAll", sqlStatement, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM recommendations WHERE status = 'active'`
+	err = repo.DB.QueryRow(countQuery).Scan(&pagination.CountData)
+	if err != nil {
+		repo.Logger.Error("Error counting recommendations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	return recommendations, pagination, nil
+}
+
+func (repo RecommendationRepository) GetByID(id int) (model.Recommendation, error) {
+	sqlStatement := `SELECT ` + recommendationColumns + ` FROM recommendations r
+		JOIN products p ON p.id = r.product_id WHERE r.id = $1 AND r.status = 'active'`
+	recommendations, err := repo.query("GetByID", sqlStatement, id)
+	if err != nil || len(recommendations) == 0 {
+		return model.Recommendation{}, err
+	}
+	return recommendations[0], nil
+}
+
+func (repo RecommendationRepository) Create(recommendationInput model.Recommendation) (model.Recommendation, error) {
+	sqlStatement := `INSERT INTO recommendations (product_id, is_recommended, set_in_banner, title, subtitle, photo_url, path_url, display_order, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8, $9, $10) RETURNING id`
+	err := repo.DB.QueryRow(sqlStatement, recommendationInput.Product.ID, recommendationInput.IsRecommended, recommendationInput.SetInBanner,
+		recommendationInput.Title, recommendationInput.Subtitle, recommendationInput.PhotoUrl, recommendationInput.PathUrl,
+		recommendationInput.DisplayOrder, recommendationInput.StartsAt, recommendationInput.EndsAt).Scan(&recommendationInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to create recommendation", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "Create"))
+		return recommendationInput, err
+	}
+	return recommendationInput, nil
+}
+
+func (repo RecommendationRepository) Update(recommendationInput model.Recommendation) error {
+	sqlStatement := `UPDATE recommendations SET product_id = $1, is_recommended = $2, set_in_banner = $3, title = $4, subtitle = $5,
+			photo_url = NULLIF($6, ''), path_url = NULLIF($7, ''), display_order = $8, starts_at = $9, ends_at = $10, updated_at = NOW()
+		WHERE id = $11 AND status = 'active'`
+	_, err := repo.DB.Exec(sqlStatement, recommendationInput.Product.ID, recommendationInput.IsRecommended, recommendationInput.SetInBanner,
+		recommendationInput.Title, recommendationInput.Subtitle, recommendationInput.PhotoUrl, recommendationInput.PathUrl,
+		recommendationInput.DisplayOrder, recommendationInput.StartsAt, recommendationInput.EndsAt, recommendationInput.ID)
+	if err != nil {
+		repo.Logger.Error("Failed to update recommendation", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "Update"))
+		return err
+	}
+	return nil
+}
+
+func (repo RecommendationRepository) Delete(id int) error {
+	sqlStatement := `UPDATE recommendations SET status = 'deleted', deleted_at = NOW() WHERE id = $1`
+	_, err := repo.DB.Exec(sqlStatement, id)
+	if err != nil {
+		repo.Logger.Error("Failed to delete recommendation", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "Delete"))
+		return err
+	}
+	return nil
+}
+
+func (repo RecommendationRepository) query(function, sqlStatement string, args ...interface{}) ([]model.Recommendation, error) {
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("error getting recommendations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", function))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recommendations []model.Recommendation
+	for rows.Next() {
+		var recommendation model.Recommendation
+		var startsAt, endsAt sql.NullTime
+		if err := rows.Scan(&recommendation.ID,
+			&recommendation.Product.ID,
+			&recommendation.Product.Name,
+			&recommendation.PhotoUrl,
+			&recommendation.IsRecommended,
+			&recommendation.SetInBanner,
+			&recommendation.Title,
+			&recommendation.Subtitle,
+			&recommendation.PathUrl,
+			&recommendation.DisplayOrder,
+			&startsAt,
+			&endsAt); err != nil {
+			repo.Logger.Error("error scanning rows", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", function))
+			return nil, err
+		}
+
+		if startsAt.Valid {
+			recommendation.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			recommendation.EndsAt = &endsAt.Time
+		}
+		// Entries without a target link open the product page.
+		if recommendation.PathUrl == "" {
+			recommendation.PathUrl = fmt.Sprintf("/api/products/%d", recommendation.Product.ID)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, rows.Err()
+}
+
+// GetPersonalised builds a feed for the user from the products they bought,
+// have in their cart or wishlisted. Products bought together with those come
+// first; products from the same categories fill up the rest. Purchased,
+// carted, deleted and out of stock products are left out.
+func (repo RecommendationRepository) GetPersonalised(userID string, pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
+	sqlStatement := `WITH purchased AS (
+			SELECT DISTINCT oi.product_id FROM order_items oi JOIN orders o ON o.id = oi.order_id
+			WHERE o.user_id = $1 AND o.order_status = 'success' AND oi.status = 'active'
+		), carted AS (
+			SELECT DISTINCT ci.product_id FROM cart_items ci JOIN carts c ON c.id = ci.cart_id
+			WHERE c.user_id = $1 AND c.status = 'active' AND c.cart_status = 'active' AND ci.status = 'active'
+		), seeds AS (
+			SELECT product_id, 3 AS weight FROM purchased
+			UNION ALL SELECT product_id, 2 FROM carted
+			UNION ALL SELECT product_id, 1 FROM wishlist WHERE user_id = $1 AND status = 'active'
+		), candidates AS (
+			SELECT pa.associated_product_id AS product_id, 1 AS tier, SUM(s.weight * pa.confidence * pa.lift) AS score
+			FROM seeds s JOIN product_associations pa ON pa.product_id = s.product_id
+			GROUP BY pa.associated_product_id
+			UNION ALL
+			SELECT p.id, 2, a.weight + p.rating / 5 FROM products p
+			JOIN (SELECT sp.category_id, SUM(s.weight) AS weight FROM seeds s
+				JOIN products sp ON sp.id = s.product_id GROUP BY sp.category_id) a ON a.category_id = p.category_id
+		), ranked AS (
+			SELECT DISTINCT ON (product_id) product_id, tier, score FROM candidates ORDER BY product_id, tier, score DESC
+		)
+		SELECT p.id, p.name, COALESCE(p.photo_url, ''), r.tier, COUNT(*) OVER ()
+		FROM ranked r JOIN products p ON p.id = r.product_id
+		WHERE p.status = 'active' AND p.publish_status = 'published' AND p.total_stock > 0
+			AND r.product_id NOT IN (SELECT product_id FROM purchased)
+			AND r.product_id NOT IN (SELECT product_id FROM carted)
+		ORDER BY r.tier, r.score DESC, p.id LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, userID, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving personalised recommendations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "GetPersonalised"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var recommendations []model.Recommendation
+	for rows.Next() {
+		var recommendation model.Recommendation
+		var tier int
+		err := rows.Scan(&recommendation.Product.ID, &recommendation.Product.Name, &recommendation.PhotoUrl, &tier, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning personalised recommendation", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "GetPersonalised"))
+			return nil, pagination, err
+		}
+		recommendation.Title = recommendation.Product.Name
+		recommendation.PathUrl = fmt.Sprintf("/api/products/%d", recommendation.Product.ID)
+		recommendation.Reason = model.RecommendationReasonCategory
+		if tier == 1 {
+			recommendation.Reason = model.RecommendationReasonBoughtTogether
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, pagination, rows.Err()
+}
+
+// GetCuratedForUser returns the curated recommendations as a fallback feed
+// for the user, leaving out what they already bought and what is out of
+// stock, like GetPersonalised.
+func (repo RecommendationRepository) GetCuratedForUser(userID string, pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
+	sqlStatement := `SELECT ` + recommendationColumns + `, COUNT(*) OVER () FROM recommendations r
+		JOIN products p ON p.id = r.product_id
+		WHERE ` + recommendationLive + ` AND r.is_recommended AND p.total_stock > 0
+			AND p.id NOT IN (SELECT oi.product_id FROM order_items oi JOIN orders o ON o.id = oi.order_id
+				WHERE o.user_id = $1 AND o.order_status = 'success' AND oi.status = 'active')
+		ORDER BY r.display_order, r.id LIMIT $2 OFFSET $3`
+	rows, err := repo.DB.Query(sqlStatement, userID, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+	if err != nil {
+		repo.Logger.Error("Error retrieving curated recommendations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "GetCuratedForUser"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	var recommendations []model.Recommendation
+	for rows.Next() {
+		var recommendation model.Recommendation
+		var startsAt, endsAt sql.NullTime
+		err := rows.Scan(&recommendation.ID, &recommendation.Product.ID, &recommendation.Product.Name, &recommendation.PhotoUrl,
+			&recommendation.IsRecommended, &recommendation.SetInBanner, &recommendation.Title, &recommendation.Subtitle,
+			&recommendation.PathUrl, &recommendation.DisplayOrder, &startsAt, &endsAt, &pagination.CountData)
+		if err != nil {
+			repo.Logger.Error("Error scanning curated recommendation", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "GetCuratedForUser"))
+			return nil, pagination, err
+		}
+		if startsAt.Valid {
+			recommendation.StartsAt = &startsAt.Time
+		}
+		if endsAt.Valid {
+			recommendation.EndsAt = &endsAt.Time
+		}
+		if recommendation.PathUrl == "" {
+			recommendation.PathUrl = fmt.Sprintf("/api/products/%d", recommendation.Product.ID)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations, pagination, rows.Err()
+}
+
+// RefreshAssociations rebuilds product_associations from the products that
+// appear together in successful orders. Pairs seen in fewer than minSupport
+// orders are dropped as noise.
+func (repo RecommendationRepository) RefreshAssociations(minSupport int) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "RefreshAssociations"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "RefreshAssociations"))
+			tx.Rollback()
+		}
+	}()
+
+	_, err = tx.Exec(`DELETE FROM product_associations`)
+	if err != nil {
+		repo.Logger.Error("Failed to clear product associations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "RefreshAssociations"))
+		return err
+	}
+
+	sqlStatement := `WITH baskets AS (
+			SELECT DISTINCT oi.order_id, oi.product_id FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE o.order_status = 'success' AND oi.status = 'active'
+		), total AS (
+			SELECT COUNT(DISTINCT order_id)::numeric AS orders FROM baskets
+		), product_counts AS (
+			SELECT product_id, COUNT(*)::numeric AS orders FROM baskets GROUP BY product_id
+		), pairs AS (
+			SELECT a.product_id, b.product_id AS associated_product_id, COUNT(*) AS support_count
+			FROM baskets a JOIN baskets b ON b.order_id = a.order_id AND b.product_id <> a.product_id
+			GROUP BY a.product_id, b.product_id
+			HAVING COUNT(*) >= $1
+		)
+		INSERT INTO product_associations (product_id, associated_product_id, support_count, confidence, lift, computed_at)
+		SELECT p.product_id, p.associated_product_id, p.support_count,
+			p.support_count / pa.orders,
+			(p.support_count / pa.orders) / (pb.orders / t.orders),
+			NOW()
+		FROM pairs p
+		JOIN product_counts pa ON pa.product_id = p.product_id
+		JOIN product_counts pb ON pb.product_id = p.associated_product_id
+		CROSS JOIN total t`
+	_, err = tx.Exec(sqlStatement, minSupport)
+	if err != nil {
+		repo.Logger.Error("Failed to compute product associations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "RefreshAssociations"))
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", "RefreshAssociations"))
+		return err
+	}
+	return nil
+}
+
+// GetAssociations returns the products most often bought with the given
+// product, best lift first. Deleted, unpublished and out of stock products are
+// skipped.
+func (repo RecommendationRepository) GetAssociations(productID, limit int) ([]model.ProductAssociation, error) {
+	sqlStatement := `SELECT pa.associated_product_id, pa.support_count, pa.confidence, pa.lift
+		FROM product_associations pa JOIN products p ON p.id = pa.associated_product_id
+		WHERE pa.product_id = $1 AND p.status = 'active' AND p.publish_status = 'published' AND p.total_stock > 0
+		ORDER BY pa.lift DESC, pa.confidence DESC, pa.support_count DESC LIMIT $2`
+	return repo.queryAssociations("GetAssociations", sqlStatement, productID, limit)
+}
+
+// GetCartAssociations ranks the products bought together with any of the
+// given products, leaving out the given products themselves.
+func (repo RecommendationRepository) GetCartAssociations(productIDs []int, limit int) ([]model.ProductAssociation, error) {
+	sqlStatement := `SELECT pa.associated_product_id, SUM(pa.support_count), MAX(pa.confidence), MAX(pa.lift)
+		FROM product_associations pa JOIN products p ON p.id = pa.associated_product_id
+		WHERE pa.product_id = ANY($1) AND NOT (pa.associated_product_id = ANY($1))
+			AND p.status = 'active' AND p.publish_status = 'published' AND p.total_stock > 0
+		GROUP BY pa.associated_product_id
+		ORDER BY MAX(pa.lift) DESC, MAX(pa.confidence) DESC, SUM(pa.support_count) DESC LIMIT $2`
+	return repo.queryAssociations("GetCartAssociations", sqlStatement, pq.Array(productIDs), limit)
+}
+
+func (repo RecommendationRepository) queryAssociations(function, sqlStatement string, args ...interface{}) ([]model.ProductAssociation, error) {
+	rows, err := repo.DB.Query(sqlStatement, args...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving product associations", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", function))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var associations []model.ProductAssociation
+	for rows.Next() {
+		var association model.ProductAssociation
+		err := rows.Scan(&association.Product.ID, &association.SupportCount, &association.Confidence, &association.Lift)
+		if err != nil {
+			repo.Logger.Error("Error scanning product association", zap.Error(err), zap.String("Repository", "Recommendation"), zap.String("Function", function))
+			return nil, err
+		}
+		associations = append(associations, association)
+	}
+	return associations, rows.Err()
+}
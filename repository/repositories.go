@@ -16,6 +16,23 @@ type MainRepository struct {
 	VariantRepository        VariantRepository
 	WishlistRepository       WishlistRepository
 	CartRepository           CartRepository
+	ReviewRepository         ReviewRepository
+	PromotionRepository      PromotionRepository
+	CouponRepository         CouponRepository
+	FlashSaleRepository      FlashSaleRepository
+	CartPromotionRepository  CartPromotionRepository
+	ProductViewRepository    ProductViewRepository
+	BestSellerRepository     BestSellerRepository
+	PriceHistoryRepository   PriceHistoryRepository
+	CurrencyRepository       CurrencyRepository
+	TranslationRepository    TranslationRepository
+	AttributeRepository      AttributeRepository
+	BrandRepository          BrandRepository
+	CollectionRepository     CollectionRepository
+	SlugRepository           SlugRepository
+	QuestionRepository       QuestionRepository
+	LifecycleRepository      LifecycleRepository
+	AbandonedCartRepository  AbandonedCartRepository
 }
 
 func NewMainRepository(db *sql.DB, log *zap.Logger) MainRepository {
@@ -29,5 +46,22 @@ func NewMainRepository(db *sql.DB, log *zap.Logger) MainRepository {
 		VariantRepository:        NewVariantRepository(db, log),
 		WishlistRepository:       NewWishlistRepository(db, log),
 		CartRepository:           NewCartRepository(db, log),
+		ReviewRepository:         NewReviewRepository(db, log),
+		PromotionRepository:      NewPromotionRepository(db, log),
+		CouponRepository:         NewCouponRepository(db, log),
+		FlashSaleRepository:      NewFlashSaleRepository(db, log),
+		CartPromotionRepository:  NewCartPromotionRepository(db, log),
+		ProductViewRepository:    NewProductViewRepository(db, log),
+		BestSellerRepository:     NewBestSellerRepository(db, log),
+		PriceHistoryRepository:   NewPriceHistoryRepository(db, log),
+		CurrencyRepository:       NewCurrencyRepository(db, log),
+		TranslationRepository:    NewTranslationRepository(db, log),
+		AttributeRepository:      NewAttributeRepository(db, log),
+		BrandRepository:          NewBrandRepository(db, log),
+		CollectionRepository:     NewCollectionRepository(db, log),
+		SlugRepository:           NewSlugRepository(db, log),
+		QuestionRepository:       NewQuestionRepository(db, log),
+		LifecycleRepository:      NewLifecycleRepository(db, log),
+		AbandonedCartRepository:  NewAbandonedCartRepository(db, log),
 	}
 }
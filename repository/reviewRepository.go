@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type ReviewRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewReviewRepository(db *sql.DB, logger *zap.Logger) ReviewRepository {
+	return ReviewRepository{DB: db, Logger: logger}
+}
+
+func (repo ReviewRepository) Create(reviewInput model.Review) (model.Review, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "Create"))
+		return reviewInput, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "Create"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `INSERT INTO reviews (order_item_id, product_id, user_id, rating, comment) VALUES ($1, $2, $3, $4, $5) RETURNING id, review_status, created_at`
+	repo.Logger.Info("Execute query", zap.String("query", sqlStatement), zap.String("Repository", "Review"), zap.String("Function", "Create"))
+	err = tx.QueryRow(sqlStatement, reviewInput.OrderItemID, reviewInput.ProductID, reviewInput.UserID, reviewInput.Rating, reviewInput.Comment).
+		Scan(&reviewInput.ID, &reviewInput.ReviewStatus, &reviewInput.CreatedAt)
+	if err != nil {
+		repo.Logger.Error("Failed to create review", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "Create"))
+		return reviewInput, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "Create"))
+		return reviewInput, err
+	}
+	return reviewInput, nil
+}
+
+func (repo ReviewRepository) GetByID(id int) (model.Review, error) {
+	var review model.Review
+	sqlStatement := `SELECT id, order_item_id, product_id, user_id, rating, COALESCE(comment, ''), review_status,
+			COALESCE(moderation_note, ''), helpful_count, report_count, created_at
+		FROM reviews WHERE id = $1 AND status = 'active'`
+
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&review.ID, &review.OrderItemID, &review.ProductID, &review.UserID, &review.Rating, &review.Comment,
+		&review.ReviewStatus, &review.ModerationNote, &review.HelpfulCount, &review.ReportCount, &review.CreatedAt)
+	if err == sql.ErrNoRows {
+		return review, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get review by ID", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "GetByID"))
+		return review, err
+	}
+	return review, nil
+}
+
+func (repo ReviewRepository) GetByOrderItemID(orderItemID int) (model.Review, error) {
+	var review model.Review
+	sqlStatement := `SELECT id, review_status FROM reviews WHERE order_item_id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, orderItemID).Scan(&review.ID, &review.ReviewStatus)
+	if err == sql.ErrNoRows {
+		return review, nil
+	} else if err != nil {
+		repo.Logger.Error("Failed to get review by order item ID", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "GetByOrderItemID"))
+		return review, err
+	}
+	return review, nil
+}
+
+func (repo ReviewRepository) GetAll(reviewFilter model.ReviewFilter, pagination model.Pagination) ([]model.Review, model.Pagination, error) {
+	var reviews []model.Review
+	var filterArgs []interface{}
+
+	sqlStatement := `SELECT r.id, r.product_id, u.name, r.rating, COALESCE(r.comment, ''), r.review_status,
+			COALESCE(r.moderation_note, ''), r.helpful_count, r.report_count, r.created_at
+		FROM reviews r
+		JOIN users u ON u.id = r.user_id
+		WHERE r.status = 'active'`
+
+	if reviewFilter.ProductID != 0 {
+		filterArgs = append(filterArgs, reviewFilter.ProductID)
+		sqlStatement += ` AND r.product_id = $` + fmt.Sprint(len(filterArgs))
+	}
+	if reviewFilter.ReviewStatus != "" {
+		filterArgs = append(filterArgs, reviewFilter.ReviewStatus)
+		sqlStatement += ` AND r.review_status = $` + fmt.Sprint(len(filterArgs))
+	}
+
+	switch reviewFilter.SortBy {
+	case model.ReviewSortHelpful:
+		sqlStatement += ` ORDER BY r.helpful_count DESC, r.created_at DESC`
+	default:
+		sqlStatement += ` ORDER BY r.created_at DESC`
+	}
+
+	sqlStatement += " LIMIT $" + fmt.Sprint(len(filterArgs)+1) + " OFFSET $" + fmt.Sprint(len(filterArgs)+2)
+	filterArgs = append(filterArgs, pagination.PerPage, (pagination.Page-1)*pagination.PerPage)
+
+	repo.Logger.Info("running query", zap.String("query", sqlStatement), zap.String("Repository", "Review"), zap.String("Function", "GetAll"), zap.Any("args", filterArgs))
+	rows, err := repo.DB.Query(sqlStatement, filterArgs...)
+	if err != nil {
+		repo.Logger.Error("Error retrieving reviews", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var review model.Review
+		if err := rows.Scan(&review.ID, &review.ProductID, &review.UserName, &review.Rating, &review.Comment, &review.ReviewStatus,
+			&review.ModerationNote, &review.HelpfulCount, &review.ReportCount, &review.CreatedAt); err != nil {
+			repo.Logger.Error("Error scanning review", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "GetAll"))
+			return nil, pagination, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		repo.Logger.Error("Error during rows iteration", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "GetAll"))
+		return nil, pagination, err
+	}
+
+	totalCount, err := repo.CountReviews(reviewFilter)
+	if err != nil {
+		return nil, pagination, err
+	}
+	pagination.CountData = totalCount
+	return reviews, pagination, nil
+}
+
+func (repo ReviewRepository) CountReviews(reviewFilter model.ReviewFilter) (int, error) {
+	var totalCount int
+	var countArgs []interface{}
+	countQuery := `SELECT COUNT(*) FROM reviews WHERE status = 'active'`
+
+	if reviewFilter.ProductID != 0 {
+		countArgs = append(countArgs, reviewFilter.ProductID)
+		countQuery += ` AND product_id = $` + fmt.Sprint(len(countArgs))
+	}
+	if reviewFilter.ReviewStatus != "" {
+		countArgs = append(countArgs, reviewFilter.ReviewStatus)
+		countQuery += ` AND review_status = $` + fmt.Sprint(len(countArgs))
+	}
+
+	err := repo.DB.QueryRow(countQuery, countArgs...).Scan(&totalCount)
+	if err != nil {
+		repo.Logger.Error("Error counting reviews", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "CountReviews"))
+		return 0, err
+	}
+	return totalCount, nil
+}
+
+// UpdateStatus moderates a review and refreshes the product rating in the
+// same transaction, so products.rating only ever reflects approved reviews.
+func (repo ReviewRepository) UpdateStatus(id int, reviewStatus, note string) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "UpdateStatus"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "UpdateStatus"))
+			tx.Rollback()
+		}
+	}()
+
+	var productID int
+	sqlStatement := `UPDATE reviews SET review_status = $1, moderation_note = $2, moderated_at = NOW(), updated_at = NOW()
+		WHERE id = $3 AND status = 'active' RETURNING product_id`
+	err = tx.QueryRow(sqlStatement, reviewStatus, note, id).Scan(&productID)
+	if err != nil {
+		repo.Logger.Error("Failed to update review status", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "UpdateStatus"))
+		return err
+	}
+
+	if err = repo.recalculateProductRating(tx, productID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "UpdateStatus"))
+		return err
+	}
+	return nil
+}
+
+// AddHelpfulVote records a helpful vote. It returns false when the user has
+// already voted for the review.
+func (repo ReviewRepository) AddHelpfulVote(reviewID int, userID string) (bool, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddHelpfulVote"))
+		return false, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddHelpfulVote"))
+			tx.Rollback()
+		}
+	}()
+
+	inserted, err := repo.insertVote(tx, reviewID, userID, model.ReviewVoteHelpful, "")
+	if err != nil || !inserted {
+		tx.Rollback()
+		return false, err
+	}
+
+	sqlStatement := `UPDATE reviews SET helpful_count = helpful_count + 1 WHERE id = $1`
+	_, err = tx.Exec(sqlStatement, reviewID)
+	if err != nil {
+		repo.Logger.Error("Failed to increment helpful count", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddHelpfulVote"))
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddHelpfulVote"))
+		return false, err
+	}
+	return true, nil
+}
+
+// AddReport records an abuse report. Once an approved review collects
+// reportThreshold reports it is sent back to the moderation queue and no
+// longer counts toward the product rating.
+func (repo ReviewRepository) AddReport(reviewID int, userID, reason string, reportThreshold int) (bool, error) {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddReport"))
+		return false, err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddReport"))
+			tx.Rollback()
+		}
+	}()
+
+	inserted, err := repo.insertVote(tx, reviewID, userID, model.ReviewVoteReport, reason)
+	if err != nil || !inserted {
+		tx.Rollback()
+		return false, err
+	}
+
+	var productID, reportCount int
+	var reviewStatus string
+	sqlStatement := `UPDATE reviews SET report_count = report_count + 1 WHERE id = $1 RETURNING product_id, report_count, review_status`
+	err = tx.QueryRow(sqlStatement, reviewID).Scan(&productID, &reportCount, &reviewStatus)
+	if err != nil {
+		repo.Logger.Error("Failed to increment report count", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddReport"))
+		return false, err
+	}
+
+	if reportThreshold > 0 && reportCount >= reportThreshold && reviewStatus == model.ReviewStatusApproved {
+		sqlStatement = `UPDATE reviews SET review_status = 'pending', updated_at = NOW() WHERE id = $1`
+		_, err = tx.Exec(sqlStatement, reviewID)
+		if err != nil {
+			repo.Logger.Error("Failed to send review back to moderation", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddReport"))
+			return false, err
+		}
+		if err = repo.recalculateProductRating(tx, productID); err != nil {
+			return false, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "AddReport"))
+		return false, err
+	}
+	return true, nil
+}
+
+func (repo ReviewRepository) insertVote(tx *sql.Tx, reviewID int, userID, voteType, reason string) (bool, error) {
+	sqlStatement := `INSERT INTO review_votes (review_id, user_id, vote_type, reason) VALUES ($1, $2, $3, NULLIF($4, ''))
+		ON CONFLICT (review_id, user_id, vote_type) DO NOTHING`
+	result, err := tx.Exec(sqlStatement, reviewID, userID, voteType, reason)
+	if err != nil {
+		repo.Logger.Error("Failed to insert review vote", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "insertVote"))
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (repo ReviewRepository) recalculateProductRating(tx *sql.Tx, productID int) error {
+	sqlStatement := `UPDATE products SET rating = COALESCE((
+			SELECT ROUND(AVG(rating), 1) FROM reviews
+			WHERE product_id = $1 AND review_status = 'approved' AND status = 'active'
+		), 0), updated_at = NOW() WHERE id = $1`
+	_, err := tx.Exec(sqlStatement, productID)
+	if err != nil {
+		repo.Logger.Error("Failed to recalculate product rating", zap.Error(err), zap.String("Repository", "Review"), zap.String("Function", "recalculateProductRating"))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"go.uber.org/zap"
+)
+
+type SlugRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewSlugRepository(db *sql.DB, logger *zap.Logger) SlugRepository {
+	return SlugRepository{DB: db, Logger: logger}
+}
+
+// slugTables maps the entity types carrying slugs to their tables.
+var slugTables = map[string]string{
+	model.SlugEntityProduct:  "products",
+	model.SlugEntityCategory: "categories",
+}
+
+func (repo SlugRepository) GetSEO(entityType string, id int) (model.SEO, error) {
+	seo := model.SEO{EntityType: entityType}
+	sqlStatement := `SELECT id, name, slug, meta_title, meta_description FROM ` + slugTables[entityType] + ` WHERE id = $1 AND status = 'active'`
+	err := repo.DB.QueryRow(sqlStatement, id).Scan(&seo.EntityID, &seo.Name, &seo.Slug, &seo.MetaTitle, &seo.MetaDescription)
+	if err == sql.ErrNoRows {
+		return seo, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving seo", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "GetSEO"))
+		return seo, err
+	}
+	return seo, nil
+}
+
+// GetEntityID returns the ID of the record currently holding the slug,
+// deleted ones included, or 0.
+func (repo SlugRepository) GetEntityID(entityType, slug string) (int, error) {
+	var id int
+	err := repo.DB.QueryRow(`SELECT id FROM `+slugTables[entityType]+` WHERE slug = $1`, slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving slug", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "GetEntityID"))
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetRedirect returns the ID of the record that formerly used the slug, or 0.
+func (repo SlugRepository) GetRedirect(entityType, slug string) (int, error) {
+	var id int
+	err := repo.DB.QueryRow(`SELECT entity_id FROM slug_redirects WHERE entity_type = $1 AND slug = $2`, entityType, slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		repo.Logger.Error("Error retrieving slug redirect", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "GetRedirect"))
+		return 0, err
+	}
+	return id, nil
+}
+
+// Save stores the name, slug and metadata of a record. A replaced oldSlug is
+// kept as a redirect to the record.
+func (repo SlugRepository) Save(seo model.SEO, oldSlug string) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+			tx.Rollback()
+		}
+	}()
+
+	sqlStatement := `UPDATE ` + slugTables[seo.EntityType] + ` SET name = $2, slug = $3, meta_title = $4, meta_description = $5, updated_at = NOW()
+		WHERE id = $1`
+	_, err = tx.Exec(sqlStatement, seo.EntityID, seo.Name, seo.Slug, seo.MetaTitle, seo.MetaDescription)
+	if err != nil {
+		repo.Logger.Error("Failed to save seo", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+		return err
+	}
+
+	// A former slug taken back resolves directly, so it no longer redirects
+	_, err = tx.Exec(`DELETE FROM slug_redirects WHERE entity_type = $1 AND entity_id = $2 AND slug = $3`, seo.EntityType, seo.EntityID, seo.Slug)
+	if err != nil {
+		repo.Logger.Error("Failed to clear slug redirect", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+		return err
+	}
+	if oldSlug != "" && oldSlug != seo.Slug {
+		_, err = tx.Exec(`INSERT INTO slug_redirects (entity_type, entity_id, slug) VALUES ($1, $2, $3)
+			ON CONFLICT (entity_type, slug) DO UPDATE SET entity_id = EXCLUDED.entity_id, created_at = NOW()`,
+			seo.EntityType, seo.EntityID, oldSlug)
+		if err != nil {
+			repo.Logger.Error("Failed to save slug redirect", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Slug"), zap.String("Function", "Save"))
+		return err
+	}
+	return nil
+}
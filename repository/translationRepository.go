@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+type TranslationRepository struct {
+	DB     *sql.DB
+	Logger *zap.Logger
+}
+
+func NewTranslationRepository(db *sql.DB, logger *zap.Logger) TranslationRepository {
+	return TranslationRepository{DB: db, Logger: logger}
+}
+
+// GetByEntities returns the translations of the given entities in a locale,
+// or in every locale when locale is empty.
+func (repo TranslationRepository) GetByEntities(entityType string, ids []int, locale string) ([]model.Translation, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	sqlStatement := `SELECT entity_type, entity_id, locale, field, value FROM translations
+		WHERE entity_type = $1 AND entity_id = ANY($2) AND ($3 = '' OR locale = $3)
+		ORDER BY locale, entity_id, field`
+	rows, err := repo.DB.Query(sqlStatement, entityType, pq.Array(ids), locale)
+	if err != nil {
+		repo.Logger.Error("Error retrieving translations", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "GetByEntities"))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []model.Translation
+	for rows.Next() {
+		var translation model.Translation
+		err := rows.Scan(&translation.EntityType, &translation.EntityID, &translation.Locale, &translation.Field, &translation.Value)
+		if err != nil {
+			repo.Logger.Error("Error scanning translation", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "GetByEntities"))
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+// Replace swaps every translation of the given entities in a locale for
+// the given ones, in one transaction. With no translations it only
+// removes the locale.
+func (repo TranslationRepository) Replace(locale string, entities map[string][]int, translations []model.Translation) error {
+	tx, err := repo.DB.Begin()
+	if err != nil {
+		repo.Logger.Error("Failed to start transaction", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "Replace"))
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p) // Re-panic after rollback
+		} else if err != nil {
+			repo.Logger.Error("Error executing transaction", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "Replace"))
+			tx.Rollback()
+		}
+	}()
+
+	for entityType, ids := range entities {
+		_, err = tx.Exec(`DELETE FROM translations WHERE entity_type = $1 AND entity_id = ANY($2) AND locale = $3`, entityType, pq.Array(ids), locale)
+		if err != nil {
+			repo.Logger.Error("Failed to delete translations", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "Replace"))
+			return err
+		}
+	}
+
+	sqlStatement := `INSERT INTO translations (entity_type, entity_id, locale, field, value) VALUES ($1, $2, $3, $4, $5)`
+	for _, translation := range translations {
+		_, err = tx.Exec(sqlStatement, translation.EntityType, translation.EntityID, locale, translation.Field, translation.Value)
+		if err != nil {
+			repo.Logger.Error("Failed to insert translation", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "Replace"))
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		repo.Logger.Error("Failed to commit transaction", zap.Error(err), zap.String("Repository", "Translation"), zap.String("Function", "Replace"))
+		return err
+	}
+	return nil
+}
@@ -68,10 +68,10 @@ func (repo *UserRepository) Create(userInput model.User) error {
 
 func (repo *UserRepository) Login(userLogin model.UserDTO) (model.User, error) {
 	var user model.User
-	sqlStatement := `SELECT id, password FROM users WHERE (email = $1 OR phone_number = $1) AND status = 'active'`
+	sqlStatement := `SELECT id, password, role FROM users WHERE (email = $1 OR phone_number = $1) AND status = 'active'`
 
 	repo.Logger.Info("Executing query", zap.String("query", sqlStatement), zap.String("Repository", "User"), zap.String("Function", "Login"))
-	err := repo.DB.QueryRow(sqlStatement, userLogin.EmailOrPhoneNumber).Scan(&user.ID, &user.PasswordHashed)
+	err := repo.DB.QueryRow(sqlStatement, userLogin.EmailOrPhoneNumber).Scan(&user.ID, &user.PasswordHashed, &user.Role)
 
 	if err == sql.ErrNoRows {
 		repo.Logger.Error("User not found", zap.Error(err),
@@ -51,7 +51,9 @@ func (repo *WishlistRepository) Create(wishlistInput model.WishlistDTO) error {
 
 func (repo *WishlistRepository) GetAll(userID string, pagination model.Pagination) ([]model.Wishlist, model.Pagination, error) {
 	var wishlist []model.Wishlist
-	sqlStatement := `SELECT product_id FROM wishlist WHERE user_id = $1 AND status = 'active' LIMIT $2 OFFSET $3`
+	sqlStatement := `SELECT w.product_id FROM wishlist w JOIN products p ON p.id = w.product_id
+		WHERE w.user_id = $1 AND w.status = 'active' AND p.status = 'active' AND p.publish_status = 'published'
+		LIMIT $2 OFFSET $3`
 	limit := pagination.PerPage
 	offset := (pagination.Page - 1) / limit
 
@@ -115,7 +117,8 @@ func (repo *WishlistRepository) Delete(userID string, wishlistID int) error {
 
 func (repo *WishlistRepository) CountWishlist(userID string) (int, error) {
 	var totalCount int
-	countQuery := `SELECT COUNT(*) FROM wishlist WHERE user_id = $1 AND status = 'active';`
+	countQuery := `SELECT COUNT(*) FROM wishlist w JOIN products p ON p.id = w.product_id
+		WHERE w.user_id = $1 AND w.status = 'active' AND p.status = 'active' AND p.publish_status = 'published';`
 
 	repo.Logger.Info("Execute query", zap.String("query", countQuery), zap.String("Repository", "Wishlist"), zap.String("Function", "CountWishlist"))
 	err := repo.DB.QueryRow(countQuery, userID).Scan(&totalCount)
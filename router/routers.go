@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/database"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/handlers"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/job"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/middleware"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/service"
@@ -26,22 +27,43 @@ func InitRouter() (*chi.Mux, *zap.Logger, string, error) {
 	}
 
 	repositories := repository.NewMainRepository(db, logger)
-	services := service.NewMainService(repositories, logger)
+	services := service.NewMainService(repositories, logger, config)
 	handlers := handlers.NewMainHandler(services, logger, config)
 	middleware := middleware.NewMiddleware(logger, config)
 
+	job.Start(logger, "frequently bought together", config.Recommendation.RefreshInterval, services.RecommendationService.RefreshAssociations)
+	job.Start(logger, "best sellers", config.BestSeller.RefreshInterval, services.BestSellerService.RefreshRanks)
+	job.StartTriggered(logger, "price snapshot", config.PriceHistory.SnapshotInterval, services.PriceHistoryService.NextPriceChange,
+		services.PriceHistoryService.Changes, services.PriceHistoryService.SnapshotPrices)
+	job.Start(logger, "scheduled publishing", config.Product.PublishInterval, services.LifecycleService.PublishScheduled)
+	job.Start(logger, "abandoned carts", config.AbandonedCart.CheckInterval, services.AbandonedCartService.ProcessAbandonedCarts)
+
 	r.Route("/api", func(r chi.Router) {
 		r.Post("/register", handlers.UserHandler.RegisterHanlder)
 		r.Get("/login", handlers.UserHandler.LoginHandler)
 
 		r.Get("/categories", handlers.CategoryHandler.GetAllCategoryHandler)
+		r.Get("/flash-sales", handlers.FlashSaleHandler.GetFlashSalesHandler)
+		r.Get("/currencies", handlers.CurrencyHandler.GetCurrenciesHandler)
+		r.Get("/brands", handlers.BrandHandler.GetBrandsHandler)
+		r.Get("/brands/{slug}/products", handlers.BrandHandler.GetBrandProductsHandler)
+		r.Get("/collections/{slug}", handlers.CollectionHandler.GetCollectionProductsHandler)
 
 		r.Route("/products", func(r chi.Router) {
 			r.Get("/", handlers.ProductHandler.GetAllProductHandler)
-			r.Get("/{id}", handlers.ProductHandler.GetProductByIdHandler)
-			r.Get("/recommendation", handlers.RecommendationHandler.GetRecommendationsHandler)
+			r.With(middleware.OptionalAuthMiddleware).Get("/{id}", handlers.ProductHandler.GetProductByIdHandler)
+			r.With(middleware.OptionalAuthMiddleware).Get("/slug/{slug}", handlers.ProductHandler.GetProductBySlugHandler)
+			r.Get("/trending", handlers.ProductViewHandler.GetTrendingHandler)
+			r.Get("/facets", handlers.ProductHandler.GetProductFacetsHandler)
+			r.Get("/compare", handlers.ProductHandler.CompareProductsHandler)
+			r.Get("/best-sellers", handlers.ProductHandler.GetBestSellersHandler)
+			r.With(middleware.OptionalAuthMiddleware).Get("/recommendation", handlers.RecommendationHandler.GetRecommendationsHandler)
 			r.Get("/banner", handlers.RecommendationHandler.GetBannerProduct)
 			r.Get("/weekly-promo", handlers.ProductHandler.GetWeeklyPromotionsHandler)
+			r.Get("/{id}/reviews", handlers.ReviewHandler.GetProductReviewsHandler)
+			r.Get("/{id}/questions", handlers.QuestionHandler.GetProductQuestionsHandler)
+			r.With(middleware.AuthMiddleware).Post("/{id}/questions", handlers.QuestionHandler.AskQuestionHandler)
+			r.Get("/{id}/frequently-bought-together", handlers.RecommendationHandler.GetFrequentlyBoughtTogetherHandler)
 		})
 
 		r.With(middleware.AuthMiddleware).Route("/wishlist", func(r chi.Router) {
@@ -50,6 +72,8 @@ func InitRouter() (*chi.Mux, *zap.Logger, string, error) {
 			r.Delete("/remove/{id}", handlers.WishlistHandler.RemoveProductFromWishlistHandler)
 		})
 
+		r.With(middleware.OptionalAuthMiddleware).Get("/user/recently-viewed", handlers.ProductViewHandler.GetRecentlyViewedHandler)
+
 		r.With(middleware.AuthMiddleware).Route("/user", func(r chi.Router) {
 			r.Route("/address", func(r chi.Router) {
 				r.Post("/", handlers.AddressHandler.AddAddressHandler)
@@ -66,6 +90,13 @@ func InitRouter() (*chi.Mux, *zap.Logger, string, error) {
 			r.Get("/", handlers.CartHandler.GetUserCart)
 			r.Delete("/remove-item/{id}", handlers.CartHandler.DeleteItemHandler)
 			r.Put("/update-item/{id}", handlers.CartHandler.UpdateCartItemHandler)
+			r.Patch("/items/{id}", handlers.CartHandler.UpdateCartItemVariantsHandler)
+			r.Post("/items/{id}/save-for-later", handlers.CartHandler.SaveForLaterHandler)
+			r.Post("/items/{id}/move-to-cart", handlers.CartHandler.MoveToCartHandler)
+			r.Post("/validate", handlers.CartHandler.ValidateCartHandler)
+			r.Post("/coupon", handlers.CouponHandler.ApplyCouponHandler)
+			r.Delete("/coupon", handlers.CouponHandler.RemoveCouponHandler)
+			r.Get("/complete-your-order", handlers.RecommendationHandler.CompleteYourOrderHandler)
 		})
 
 		r.With(middleware.AuthMiddleware).Route("/orders", func(r chi.Router) {
@@ -73,6 +104,113 @@ func InitRouter() (*chi.Mux, *zap.Logger, string, error) {
 			r.Get("/", handlers.OrderHandler.GetOrderHistoryHandler)
 			r.Get("/{id}", handlers.OrderHandler.GetOrderDetailsHandler)
 		})
+
+		r.With(middleware.AuthMiddleware).Route("/reviews", func(r chi.Router) {
+			r.Post("/", handlers.ReviewHandler.SubmitReviewHandler)
+			r.Post("/{id}/helpful", handlers.ReviewHandler.MarkHelpfulHandler)
+			r.Post("/{id}/report", handlers.ReviewHandler.ReportReviewHandler)
+		})
+
+		r.With(middleware.AuthMiddleware).Route("/questions", func(r chi.Router) {
+			r.Post("/{id}/answers", handlers.QuestionHandler.AnswerQuestionHandler)
+			r.Post("/{id}/upvote", handlers.QuestionHandler.UpvoteQuestionHandler)
+		})
+		r.With(middleware.AuthMiddleware).Post("/answers/{id}/upvote", handlers.QuestionHandler.UpvoteAnswerHandler)
+
+		r.With(middleware.AuthMiddleware, middleware.AdminMiddleware).Route("/admin", func(r chi.Router) {
+			r.Route("/reviews", func(r chi.Router) {
+				r.Get("/", handlers.ReviewHandler.GetModerationQueueHandler)
+				r.Patch("/{id}", handlers.ReviewHandler.ModerateReviewHandler)
+			})
+
+			r.Route("/questions", func(r chi.Router) {
+				r.Get("/", handlers.QuestionHandler.GetQuestionQueueHandler)
+				r.Patch("/{id}", handlers.QuestionHandler.ModerateQuestionHandler)
+			})
+
+			r.Route("/answers", func(r chi.Router) {
+				r.Get("/", handlers.QuestionHandler.GetAnswerQueueHandler)
+				r.Patch("/{id}", handlers.QuestionHandler.ModerateAnswerHandler)
+			})
+
+			r.Get("/products", handlers.LifecycleHandler.GetProductLifecyclesHandler)
+			r.Route("/products/{id}", func(r chi.Router) {
+				r.Get("/lifecycle", handlers.LifecycleHandler.GetProductLifecycleHandler)
+				r.Put("/lifecycle", handlers.LifecycleHandler.UpdateProductLifecycleHandler)
+				r.Get("/price-history", handlers.ProductHandler.GetPriceHistoryHandler)
+				r.Get("/translations", handlers.ProductHandler.GetProductTranslationsHandler)
+				r.Put("/translations/{locale}", handlers.ProductHandler.SaveProductTranslationHandler)
+				r.Delete("/translations/{locale}", handlers.ProductHandler.DeleteProductTranslationHandler)
+				r.Put("/attributes", handlers.ProductHandler.SetProductAttributesHandler)
+				r.Put("/brand", handlers.BrandHandler.SetProductBrandHandler)
+				r.Put("/tags", handlers.CollectionHandler.SetProductTagsHandler)
+				r.Put("/seo", handlers.SEOHandler.UpdateProductSEOHandler)
+			})
+
+			r.Route("/brands", func(r chi.Router) {
+				r.Post("/", handlers.BrandHandler.CreateBrandHandler)
+				r.Put("/{id}", handlers.BrandHandler.UpdateBrandHandler)
+				r.Delete("/{id}", handlers.BrandHandler.DeleteBrandHandler)
+			})
+
+			r.Route("/collections", func(r chi.Router) {
+				r.Get("/", handlers.CollectionHandler.GetCollectionsHandler)
+				r.Post("/", handlers.CollectionHandler.CreateCollectionHandler)
+				r.Get("/{id}", handlers.CollectionHandler.GetCollectionHandler)
+				r.Put("/{id}", handlers.CollectionHandler.UpdateCollectionHandler)
+				r.Delete("/{id}", handlers.CollectionHandler.DeleteCollectionHandler)
+			})
+
+			r.Route("/attributes", func(r chi.Router) {
+				r.Get("/", handlers.AttributeHandler.GetAttributesHandler)
+				r.Post("/", handlers.AttributeHandler.CreateAttributeHandler)
+				r.Put("/{id}", handlers.AttributeHandler.UpdateAttributeHandler)
+				r.Delete("/{id}", handlers.AttributeHandler.DeleteAttributeHandler)
+			})
+
+			r.Put("/categories/{id}/translations/{locale}", handlers.CategoryHandler.SaveCategoryTranslationHandler)
+			r.Put("/categories/{id}/seo", handlers.SEOHandler.UpdateCategorySEOHandler)
+
+			r.Route("/currencies", func(r chi.Router) {
+				r.Post("/import", handlers.CurrencyHandler.ImportCurrenciesHandler)
+				r.Put("/{code}", handlers.CurrencyHandler.SaveCurrencyHandler)
+				r.Delete("/{code}", handlers.CurrencyHandler.DeleteCurrencyHandler)
+			})
+
+			r.Route("/recommendations", func(r chi.Router) {
+				r.Get("/", handlers.RecommendationHandler.GetAllRecommendationsHandler)
+				r.Post("/", handlers.RecommendationHandler.CreateRecommendationHandler)
+				r.Put("/{id}", handlers.RecommendationHandler.UpdateRecommendationHandler)
+				r.Delete("/{id}", handlers.RecommendationHandler.DeleteRecommendationHandler)
+				r.Put("/{id}/translations/{locale}", handlers.RecommendationHandler.SaveRecommendationTranslationHandler)
+			})
+
+			r.Route("/promotions", func(r chi.Router) {
+				r.Get("/", handlers.PromotionHandler.GetAllPromotionsHandler)
+				r.Post("/", handlers.PromotionHandler.CreatePromotionHandler)
+				r.Put("/{id}", handlers.PromotionHandler.UpdatePromotionHandler)
+				r.Delete("/{id}", handlers.PromotionHandler.DeletePromotionHandler)
+			})
+
+			r.Route("/cart-promotions", func(r chi.Router) {
+				r.Get("/", handlers.CartPromotionHandler.GetAllCartPromotionsHandler)
+				r.Post("/", handlers.CartPromotionHandler.CreateCartPromotionHandler)
+				r.Delete("/{id}", handlers.CartPromotionHandler.DeleteCartPromotionHandler)
+			})
+
+			r.Get("/abandoned-carts/stats", handlers.AbandonedCartHandler.GetAbandonedCartStatsHandler)
+
+			r.Route("/coupons", func(r chi.Router) {
+				r.Get("/", handlers.CouponHandler.GetAllCouponsHandler)
+				r.Post("/", handlers.CouponHandler.CreateCouponHandler)
+				r.Delete("/{id}", handlers.CouponHandler.DeleteCouponHandler)
+			})
+
+			r.Route("/flash-sales", func(r chi.Router) {
+				r.Post("/", handlers.FlashSaleHandler.CreateFlashSaleHandler)
+				r.Delete("/{id}", handlers.FlashSaleHandler.DeleteFlashSaleHandler)
+			})
+		})
 	})
 
 	return r, logger, config.Port, nil
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/notify"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var ErrAbandonedCartInvalid = errors.New("invalid abandoned cart request")
+
+type AbandonedCartService struct {
+	Repo     repository.MainRepository
+	Logger   *zap.Logger
+	Notifier notify.Notifier
+	Config   util.AbandonedCartConfig
+}
+
+func NewAbandonedCartService(repo repository.MainRepository, logger *zap.Logger, notifier notify.Notifier, config util.AbandonedCartConfig) AbandonedCartService {
+	return AbandonedCartService{Repo: repo, Logger: logger, Notifier: notifier, Config: config}
+}
+
+// ProcessAbandonedCarts expires the carts left untouched past ExpireAfter
+// and reminds the owners of the carts idle past IdleAfter. A reminder that
+// fails is tried again on the next run.
+func (s AbandonedCartService) ProcessAbandonedCarts() error {
+	now := time.Now()
+	var activeAfter time.Time
+	if s.Config.ExpireAfter > 0 {
+		activeAfter = now.Add(-s.Config.ExpireAfter)
+		expired, err := s.Repo.AbandonedCartRepository.ExpireIdle(activeAfter)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			s.Logger.Info("expired abandoned carts", zap.Int64("carts", expired), zap.String("service", "AbandonedCart"))
+		}
+	}
+	if s.Config.IdleAfter <= 0 {
+		return nil
+	}
+
+	carts, err := s.Repo.AbandonedCartRepository.GetAbandoned(now.Add(-s.Config.IdleAfter), activeAfter)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, cart := range carts {
+		if err := s.remind(cart); err != nil {
+			s.Logger.Error("error send cart reminder", zap.Error(err), zap.Int("cart_id", cart.CartID), zap.String("service", "AbandonedCart"))
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cart reminders failed", failed, len(carts))
+	}
+	return nil
+}
+
+// remind notifies the owner of an abandoned cart. A cart without a coupon
+// gets a personal one when coupons are configured. The reminder and its
+// coupon are stored before the notification goes out, so a retry after a
+// failed send reuses them instead of minting another coupon.
+func (s AbandonedCartService) remind(cart model.AbandonedCart) error {
+	reminder, err := s.Repo.AbandonedCartRepository.GetPendingReminder(cart.CartID)
+	if err != nil {
+		return err
+	}
+
+	var coupon model.Coupon
+	switch {
+	case reminder.ID != 0 && reminder.CouponID != 0:
+		coupon, err = s.Repo.CouponRepository.GetByID(reminder.CouponID)
+		if err != nil {
+			return err
+		}
+	case reminder.ID == 0:
+		reminder = model.CartReminder{CartID: cart.CartID, UserID: cart.UserID, CartTotal: cart.TotalPrice}
+		var newCoupon *model.Coupon
+		if s.Config.CouponPercent > 0 && s.Config.CouponValidFor > 0 && cart.CouponID == 0 {
+			coupon = s.reminderCoupon(cart.UserID)
+			newCoupon = &coupon
+		}
+		reminder, err = s.Repo.AbandonedCartRepository.CreateReminder(reminder, newCoupon)
+		if err != nil {
+			return err
+		}
+	}
+
+	notification := model.Notification{
+		Type:        model.NotificationAbandonedCart,
+		UserID:      cart.UserID,
+		Name:        cart.Name,
+		Email:       cart.Email,
+		PhoneNumber: cart.PhoneNumber,
+		Subject:     "You left something in your cart",
+		Message:     fmt.Sprintf("You have %d item(s) worth %s waiting in your cart.", cart.TotalAmount, cart.TotalPrice),
+		Data: map[string]interface{}{
+			"cart_id":      cart.CartID,
+			"total_amount": cart.TotalAmount,
+			"total_price":  cart.TotalPrice,
+		},
+		CreatedAt: time.Now(),
+	}
+	if coupon.ID != 0 {
+		notification.Message += fmt.Sprintf(" Use code %s for %s%% off until %s.", coupon.Code, coupon.DiscountValue, coupon.EndsAt.Format("2 Jan 2006 15:04"))
+		notification.Data["coupon_code"] = coupon.Code
+		notification.Data["coupon_ends_at"] = coupon.EndsAt
+	}
+
+	if err := s.Notifier.Notify(notification); err != nil {
+		return err
+	}
+	return s.Repo.AbandonedCartRepository.MarkSent(reminder.ID)
+}
+
+// reminderCoupon builds a single use percentage coupon only the user can
+// redeem.
+func (s AbandonedCartService) reminderCoupon(userID string) model.Coupon {
+	now := time.Now()
+	code := "CART-" + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:10])
+	return model.Coupon{
+		Code:          code,
+		Description:   "Abandoned cart reminder",
+		CouponType:    model.DiscountTypePercentage,
+		DiscountValue: model.NewMoney(s.Config.CouponPercent).Round(2),
+		StartsAt:      now,
+		EndsAt:        now.Add(s.Config.CouponValidFor),
+		UsageLimit:    1,
+		PerUserLimit:  1,
+		UserID:        userID,
+	}
+}
+
+// GetStats sums up the reminders of the last days days.
+func (s AbandonedCartService) GetStats(days int) (model.AbandonedCartStats, error) {
+	if days == 0 {
+		days = 30
+	}
+	if days < 0 {
+		return model.AbandonedCartStats{}, fmt.Errorf("%w: days must be positive", ErrAbandonedCartInvalid)
+	}
+	stats, err := s.Repo.AbandonedCartRepository.GetStats(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return stats, err
+	}
+	if stats.RemindersSent > 0 {
+		stats.ConversionRate = float64(stats.Converted) / float64(stats.RemindersSent)
+	}
+	return stats, nil
+}
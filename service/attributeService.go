@@ -0,0 +1,402 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAttributeNotFound = errors.New("attribute not found")
+	ErrAttributeInvalid  = errors.New("invalid attribute")
+)
+
+var attributeCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+type AttributeService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+}
+
+func NewAttributeService(repo repository.MainRepository, logger *zap.Logger) AttributeService {
+	return AttributeService{Repo: repo, Logger: logger}
+}
+
+func (s AttributeService) GetAttributes(categoryID int) ([]model.Attribute, error) {
+	return s.Repo.AttributeRepository.GetAll(categoryID)
+}
+
+func (s AttributeService) CreateAttribute(attributeInput model.AttributeDTO) (model.Attribute, error) {
+	attribute, err := validateAttribute(attributeInput)
+	if err != nil {
+		return attribute, err
+	}
+	if attribute.CategoryID != 0 {
+		category, err := s.Repo.CategoryRepository.GetByID(attribute.CategoryID)
+		if err != nil {
+			s.Logger.Error("error get category", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "CreateAttribute"))
+			return attribute, err
+		}
+		if category.ID == 0 {
+			return attribute, fmt.Errorf("%w: %d", ErrCategoryNotFound, attribute.CategoryID)
+		}
+	}
+	if err := s.checkCode(attribute); err != nil {
+		return attribute, err
+	}
+	return s.Repo.AttributeRepository.Create(attribute)
+}
+
+// UpdateAttribute changes the name, code, unit, filterable flag and order
+// of an attribute. Its category and data type stay as they were.
+func (s AttributeService) UpdateAttribute(id int, attributeInput model.AttributeDTO) (model.Attribute, error) {
+	existing, err := s.getAttribute(id)
+	if err != nil {
+		return existing, err
+	}
+	attributeInput.CategoryID = existing.CategoryID
+	attributeInput.DataType = existing.DataType
+	attribute, err := validateAttribute(attributeInput)
+	if err != nil {
+		return attribute, err
+	}
+	attribute.ID = id
+	if err := s.checkCode(attribute); err != nil {
+		return attribute, err
+	}
+	if err := s.Repo.AttributeRepository.Update(attribute); err != nil {
+		return attribute, err
+	}
+	return attribute, nil
+}
+
+func (s AttributeService) DeleteAttribute(id int) error {
+	if _, err := s.getAttribute(id); err != nil {
+		return err
+	}
+	return s.Repo.AttributeRepository.Delete(id)
+}
+
+// checkCode makes sure no other attribute of the same category uses the
+// attribute's code.
+func (s AttributeService) checkCode(attribute model.Attribute) error {
+	attributes, err := s.Repo.AttributeRepository.GetByCodes([]string{attribute.Code})
+	if err != nil {
+		s.Logger.Error("error get attributes", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "checkCode"))
+		return err
+	}
+	for _, other := range attributes {
+		if other.ID != attribute.ID && other.CategoryID == attribute.CategoryID {
+			return fmt.Errorf("%w: code %s is already used", ErrAttributeInvalid, attribute.Code)
+		}
+	}
+	return nil
+}
+
+func (s AttributeService) getAttribute(id int) (model.Attribute, error) {
+	attribute, err := s.Repo.AttributeRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get attribute", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "getAttribute"))
+		return attribute, err
+	}
+	if attribute.ID == 0 {
+		return attribute, fmt.Errorf("%w: %d", ErrAttributeNotFound, id)
+	}
+	return attribute, nil
+}
+
+// SetProductAttributes replaces the attribute values of a product. Every
+// attribute must apply to the product's category, and every value must
+// match the attribute's data type. A null value leaves the attribute out.
+func (s AttributeService) SetProductAttributes(productID int, valuesInput []model.ProductAttributeDTO) ([]model.ProductAttribute, error) {
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "SetProductAttributes"))
+		return nil, err
+	}
+	if product.ID == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+
+	attributes, err := s.Repo.AttributeRepository.GetAll(product.CategoryID)
+	if err != nil {
+		s.Logger.Error("error get attributes", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "SetProductAttributes"))
+		return nil, err
+	}
+	byID := make(map[int]model.Attribute)
+	for _, attribute := range attributes {
+		if attribute.CategoryID == 0 || attribute.CategoryID == product.CategoryID {
+			byID[attribute.ID] = attribute
+		}
+	}
+
+	seen := make(map[int]bool)
+	var values []model.ProductAttribute
+	for _, valueInput := range valuesInput {
+		attribute, ok := byID[valueInput.AttributeID]
+		if !ok {
+			return nil, fmt.Errorf("%w: attribute %d does not apply to product %d", ErrAttributeInvalid, valueInput.AttributeID, productID)
+		}
+		if seen[attribute.ID] {
+			return nil, fmt.Errorf("%w: attribute %s is given more than once", ErrAttributeInvalid, attribute.Code)
+		}
+		seen[attribute.ID] = true
+		if valueInput.Value == nil {
+			continue
+		}
+
+		value := model.ProductAttribute{
+			AttributeID: attribute.ID,
+			Code:        attribute.Code,
+			Name:        attribute.Name,
+			DataType:    attribute.DataType,
+			Unit:        attribute.Unit,
+		}
+		switch v := valueInput.Value.(type) {
+		case string:
+			if attribute.DataType != model.AttributeTypeText || strings.TrimSpace(v) == "" || len(v) > 255 {
+				return nil, attributeValueError(attribute)
+			}
+			value.Value = strings.TrimSpace(v)
+		case float64:
+			if attribute.DataType != model.AttributeTypeNumber {
+				return nil, attributeValueError(attribute)
+			}
+			value.Value = v
+		case bool:
+			if attribute.DataType != model.AttributeTypeBoolean {
+				return nil, attributeValueError(attribute)
+			}
+			value.Value = v
+		default:
+			return nil, attributeValueError(attribute)
+		}
+		values = append(values, value)
+	}
+
+	if err := s.Repo.AttributeRepository.SaveProductValues(productID, values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func attributeValueError(attribute model.Attribute) error {
+	return fmt.Errorf("%w: %s needs a %s value", ErrAttributeInvalid, attribute.Code, attribute.DataType)
+}
+
+// ResolveFilters checks the listing filters against the attributes and
+// parses their values. Text values are matched without case, boolean values
+// must be true or false, and number values can be exact numbers or one
+// "min..max" range with either end left open.
+func (s AttributeService) ResolveFilters(filters []model.AttributeFilter) ([]model.AttributeFilter, error) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	var codes []string
+	for _, filter := range filters {
+		codes = append(codes, filter.Code)
+	}
+	attributes, err := s.Repo.AttributeRepository.GetByCodes(codes)
+	if err != nil {
+		s.Logger.Error("error get attributes", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "ResolveFilters"))
+		return nil, err
+	}
+	dataTypes := make(map[string]string)
+	for _, attribute := range attributes {
+		if attribute.IsFilterable {
+			dataTypes[attribute.Code] = attribute.DataType
+		}
+	}
+
+	resolved := make([]model.AttributeFilter, 0, len(filters))
+	for _, filter := range filters {
+		dataType, ok := dataTypes[filter.Code]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot filter on %q", ErrAttributeInvalid, filter.Code)
+		}
+		filter.DataType = dataType
+		switch dataType {
+		case model.AttributeTypeBoolean:
+			for _, value := range filter.Values {
+				if value != "true" && value != "false" {
+					return nil, fmt.Errorf("%w: %s must be true or false", ErrAttributeInvalid, filter.Code)
+				}
+			}
+		case model.AttributeTypeNumber:
+			filter, err = parseNumberFilter(filter)
+			if err != nil {
+				return nil, err
+			}
+		}
+		resolved = append(resolved, filter)
+	}
+	return resolved, nil
+}
+
+func parseNumberFilter(filter model.AttributeFilter) (model.AttributeFilter, error) {
+	var values []string
+	for _, value := range filter.Values {
+		lower, upper, isRange := strings.Cut(value, "..")
+		if !isRange {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return filter, fmt.Errorf("%w: %s must be a number or a min..max range", ErrAttributeInvalid, filter.Code)
+			}
+			values = append(values, value)
+			continue
+		}
+		if filter.Min != nil || filter.Max != nil {
+			return filter, fmt.Errorf("%w: %s can only have one range", ErrAttributeInvalid, filter.Code)
+		}
+		bounds := []struct {
+			value  string
+			target **float64
+		}{{lower, &filter.Min}, {upper, &filter.Max}}
+		for _, bound := range bounds {
+			if bound.value == "" {
+				continue
+			}
+			number, err := strconv.ParseFloat(bound.value, 64)
+			if err != nil {
+				return filter, fmt.Errorf("%w: %s must be a number or a min..max range", ErrAttributeInvalid, filter.Code)
+			}
+			*bound.target = &number
+		}
+	}
+	if len(values) > 0 && (filter.Min != nil || filter.Max != nil) {
+		return filter, fmt.Errorf("%w: %s cannot mix numbers and a range", ErrAttributeInvalid, filter.Code)
+	}
+	filter.Values = values
+	return filter, nil
+}
+
+// GetFacets counts the products of the listing per value of every
+// filterable attribute of the category. The counts of an attribute ignore
+// that attribute's own filter, so the other values stay selectable.
+func (s AttributeService) GetFacets(productFilter model.ProductDTO) ([]model.Facet, error) {
+	filters, err := s.ResolveFilters(productFilter.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	attributes, err := s.Repo.AttributeRepository.GetAll(productFilter.CategoryID)
+	if err != nil {
+		s.Logger.Error("error get attributes", zap.Error(err), zap.String("service", "Attribute"), zap.String("function", "GetFacets"))
+		return nil, err
+	}
+
+	var unfiltered []int
+	filtered := make(map[int]bool)
+	for _, attribute := range attributes {
+		if !attribute.IsFilterable {
+			continue
+		}
+		if hasFilter(filters, attribute.Code) {
+			filtered[attribute.ID] = true
+		} else {
+			unfiltered = append(unfiltered, attribute.ID)
+		}
+	}
+
+	productFilter.Attributes = filters
+	values, err := s.Repo.AttributeRepository.GetFacetValues(unfiltered, productFilter)
+	if err != nil {
+		return nil, err
+	}
+	for _, attribute := range attributes {
+		if !filtered[attribute.ID] {
+			continue
+		}
+		productFilter.Attributes = withoutFilter(filters, attribute.Code)
+		own, err := s.Repo.AttributeRepository.GetFacetValues([]int{attribute.ID}, productFilter)
+		if err != nil {
+			return nil, err
+		}
+		values[attribute.ID] = own[attribute.ID]
+	}
+
+	facets := []model.Facet{}
+	for _, attribute := range attributes {
+		if !attribute.IsFilterable || len(values[attribute.ID]) == 0 {
+			continue
+		}
+		facet := model.Facet{Attribute: attribute, Values: values[attribute.ID]}
+		if attribute.DataType == model.AttributeTypeNumber {
+			for _, value := range facet.Values {
+				number, err := strconv.ParseFloat(value.Value, 64)
+				if err != nil {
+					continue
+				}
+				if facet.Min == nil || number < *facet.Min {
+					facet.Min = &number
+				}
+				if facet.Max == nil || number > *facet.Max {
+					max := number
+					facet.Max = &max
+				}
+			}
+			sort.SliceStable(facet.Values, func(i, j int) bool {
+				a, _ := strconv.ParseFloat(facet.Values[i].Value, 64)
+				b, _ := strconv.ParseFloat(facet.Values[j].Value, 64)
+				return a < b
+			})
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+func hasFilter(filters []model.AttributeFilter, code string) bool {
+	for _, filter := range filters {
+		if filter.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutFilter(filters []model.AttributeFilter, code string) []model.AttributeFilter {
+	var others []model.AttributeFilter
+	for _, filter := range filters {
+		if filter.Code != code {
+			others = append(others, filter)
+		}
+	}
+	return others
+}
+
+func validateAttribute(attributeInput model.AttributeDTO) (model.Attribute, error) {
+	attribute := model.Attribute{
+		CategoryID:   attributeInput.CategoryID,
+		Code:         strings.ToLower(strings.TrimSpace(attributeInput.Code)),
+		Name:         strings.TrimSpace(attributeInput.Name),
+		DataType:     attributeInput.DataType,
+		Unit:         strings.TrimSpace(attributeInput.Unit),
+		IsFilterable: true,
+		DisplayOrder: attributeInput.DisplayOrder,
+	}
+	if attributeInput.IsFilterable != nil {
+		attribute.IsFilterable = *attributeInput.IsFilterable
+	}
+
+	if !attributeCodePattern.MatchString(attribute.Code) {
+		return attribute, fmt.Errorf("%w: code must be lowercase letters, digits or underscores", ErrAttributeInvalid)
+	}
+	if attribute.Name == "" {
+		return attribute, fmt.Errorf("%w: name is required", ErrAttributeInvalid)
+	}
+	switch attribute.DataType {
+	case model.AttributeTypeText, model.AttributeTypeNumber, model.AttributeTypeBoolean:
+	default:
+		return attribute, fmt.Errorf("%w: data_type must be text, number or boolean", ErrAttributeInvalid)
+	}
+	if attribute.CategoryID < 0 {
+		return attribute, fmt.Errorf("%w: invalid category_id", ErrAttributeInvalid)
+	}
+	return attribute, nil
+}
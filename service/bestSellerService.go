@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+var ErrBestSellerWindow = errors.New("unknown best seller window")
+
+type BestSellerService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+	Config  util.BestSellerConfig
+}
+
+func NewBestSellerService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, config util.BestSellerConfig) BestSellerService {
+	return BestSellerService{Repo: repo, Logger: logger, Pricing: pricing, Config: config}
+}
+
+// RefreshRanks recomputes the ranks of every configured window. It runs as
+// a background job.
+func (s BestSellerService) RefreshRanks() error {
+	var windows []int
+	for _, window := range s.Config.Windows {
+		if window > 0 {
+			windows = append(windows, window)
+		}
+	}
+	return s.Repo.BestSellerRepository.RefreshRanks(windows, s.Config.FlagWindow, s.Config.FlagTopN)
+}
+
+// GetBestSellers lists the products of one window, best first. A zero
+// window uses the window behind the is_best_selling flag.
+func (s BestSellerService) GetBestSellers(windowDays int, pagination model.Pagination) ([]model.BestSeller, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+
+	if windowDays == 0 {
+		windowDays = s.Config.FlagWindow
+	}
+	if !s.hasWindow(windowDays) {
+		return nil, pagination, fmt.Errorf("%w: window must be one of %v", ErrBestSellerWindow, s.Config.Windows)
+	}
+
+	bestSellers, pagination, err := s.Repo.BestSellerRepository.GetByWindow(windowDays, pagination)
+	if err != nil {
+		return nil, pagination, err
+	}
+	products := make([]model.Product, len(bestSellers))
+	for i, bestSeller := range bestSellers {
+		products[i], err = s.Repo.ProductRepository.GetByID(bestSeller.Product.ID)
+		if err != nil {
+			s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "BestSeller"), zap.String("function", "GetBestSellers"))
+			return nil, pagination, err
+		}
+	}
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		return nil, pagination, err
+	}
+	for i := range bestSellers {
+		bestSellers[i].Product = products[i]
+	}
+	return bestSellers, pagination, nil
+}
+
+func (s BestSellerService) hasWindow(windowDays int) bool {
+	for _, window := range s.Config.Windows {
+		if window == windowDays {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrBrandNotFound = errors.New("brand not found")
+	ErrBrandInvalid  = errors.New("invalid brand")
+)
+
+type BrandService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Product ProductService
+}
+
+func NewBrandService(repo repository.MainRepository, logger *zap.Logger, product ProductService) BrandService {
+	return BrandService{Repo: repo, Logger: logger, Product: product}
+}
+
+func (s BrandService) GetBrands(pagination model.Pagination) ([]model.Brand, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.BrandRepository.GetAll(pagination)
+}
+
+// GetBrandProducts lists the products of the brand with the given slug,
+// with the same filters as the product listing.
+func (s BrandService) GetBrandProducts(slug string, productFilter model.ProductDTO, pagination model.Pagination) (model.Brand, []model.Product, model.Pagination, error) {
+	brand, err := s.Repo.BrandRepository.GetBySlug(slug)
+	if err != nil {
+		s.Logger.Error("error get brand", zap.Error(err), zap.String("service", "Brand"), zap.String("function", "GetBrandProducts"))
+		return brand, nil, pagination, err
+	}
+	if brand.ID == 0 {
+		return brand, nil, pagination, fmt.Errorf("%w: %s", ErrBrandNotFound, slug)
+	}
+
+	productFilter.BrandID = brand.ID
+	products, pagination, err := s.Product.GetAllProduct(productFilter, pagination)
+	if err != nil {
+		return brand, nil, pagination, err
+	}
+	for i := range products {
+		products[i].Brand = &brand
+	}
+	return brand, products, pagination, nil
+}
+
+func (s BrandService) CreateBrand(brandInput model.BrandDTO) (model.Brand, error) {
+	brand, err := s.validateBrand(0, brandInput)
+	if err != nil {
+		return brand, err
+	}
+	return s.Repo.BrandRepository.Create(brand)
+}
+
+func (s BrandService) UpdateBrand(id int, brandInput model.BrandDTO) (model.Brand, error) {
+	if _, err := s.getBrand(id); err != nil {
+		return model.Brand{}, err
+	}
+	brand, err := s.validateBrand(id, brandInput)
+	if err != nil {
+		return brand, err
+	}
+	if err := s.Repo.BrandRepository.Update(brand); err != nil {
+		return brand, err
+	}
+	return brand, nil
+}
+
+func (s BrandService) DeleteBrand(id int) error {
+	if _, err := s.getBrand(id); err != nil {
+		return err
+	}
+	return s.Repo.BrandRepository.Delete(id)
+}
+
+// SetProductBrand assigns a brand to a product, or removes it when the
+// brand is null.
+func (s BrandService) SetProductBrand(productID int, brandInput model.ProductBrandDTO) error {
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Brand"), zap.String("function", "SetProductBrand"))
+		return err
+	}
+	if product.ID == 0 {
+		return fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+
+	var brandID int
+	if brandInput.BrandID != nil {
+		brand, err := s.getBrand(*brandInput.BrandID)
+		if err != nil {
+			return err
+		}
+		brandID = brand.ID
+	}
+	return s.Repo.BrandRepository.SetProductBrand(productID, brandID)
+}
+
+func (s BrandService) getBrand(id int) (model.Brand, error) {
+	brand, err := s.Repo.BrandRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get brand", zap.Error(err), zap.String("service", "Brand"), zap.String("function", "getBrand"))
+		return brand, err
+	}
+	if brand.ID == 0 {
+		return brand, fmt.Errorf("%w: %d", ErrBrandNotFound, id)
+	}
+	return brand, nil
+}
+
+func (s BrandService) validateBrand(id int, brandInput model.BrandDTO) (model.Brand, error) {
+	brand := model.Brand{
+		ID:          id,
+		Name:        strings.TrimSpace(brandInput.Name),
+		Slug:        helper.Slugify(brandInput.Slug),
+		LogoURL:     strings.TrimSpace(brandInput.LogoURL),
+		Description: strings.TrimSpace(brandInput.Description),
+	}
+	if brand.Name == "" {
+		return brand, fmt.Errorf("%w: name is required", ErrBrandInvalid)
+	}
+	if brand.Slug == "" {
+		brand.Slug = helper.Slugify(brand.Name)
+	}
+	if brand.Slug == "" {
+		return brand, fmt.Errorf("%w: name needs letters or digits for a slug", ErrBrandInvalid)
+	}
+
+	existing, err := s.Repo.BrandRepository.GetBySlug(brand.Slug)
+	if err != nil {
+		s.Logger.Error("error get brand", zap.Error(err), zap.String("service", "Brand"), zap.String("function", "validateBrand"))
+		return brand, err
+	}
+	if existing.ID != 0 && existing.ID != id {
+		return brand, fmt.Errorf("%w: slug %s is already used", ErrBrandInvalid, brand.Slug)
+	}
+	return brand, nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCartPromotionNotFound = errors.New("cart promotion not found")
+	ErrCartPromotionInvalid  = errors.New("invalid cart promotion")
+)
+
+type CartPromotionService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+}
+
+func NewCartPromotionService(repo repository.MainRepository, logger *zap.Logger) CartPromotionService {
+	return CartPromotionService{Repo: repo, Logger: logger}
+}
+
+func (s CartPromotionService) GetAllCartPromotions(pagination model.Pagination) ([]model.CartPromotion, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.CartPromotionRepository.GetAll(pagination)
+}
+
+func (s CartPromotionService) CreateCartPromotion(promotionInput model.CartPromotionDTO) (model.CartPromotion, error) {
+	promotion, err := s.validateCartPromotion(promotionInput)
+	if err != nil {
+		return promotion, err
+	}
+	return s.Repo.CartPromotionRepository.Create(promotion)
+}
+
+func (s CartPromotionService) DeleteCartPromotion(id int) error {
+	existing, err := s.Repo.CartPromotionRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get cart promotion by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrCartPromotionNotFound
+	}
+	return s.Repo.CartPromotionRepository.Delete(id)
+}
+
+func (s CartPromotionService) validateCartPromotion(promotionInput model.CartPromotionDTO) (model.CartPromotion, error) {
+	promotion := model.CartPromotion{
+		Name:          strings.TrimSpace(promotionInput.Name),
+		RuleType:      promotionInput.RuleType,
+		DiscountValue: promotionInput.DiscountValue,
+		ProductIDs:    promotionInput.ProductIDs,
+		StartsAt:      promotionInput.StartsAt,
+		EndsAt:        promotionInput.EndsAt,
+	}
+
+	if promotion.Name == "" {
+		return promotion, fmt.Errorf("%w: name is required", ErrCartPromotionInvalid)
+	}
+	if len(promotion.ProductIDs) == 0 {
+		return promotion, fmt.Errorf("%w: product_ids is required", ErrCartPromotionInvalid)
+	}
+	seen := make(map[int]bool)
+	for _, productID := range promotion.ProductIDs {
+		if seen[productID] {
+			return promotion, fmt.Errorf("%w: product %d is listed twice", ErrCartPromotionInvalid, productID)
+		}
+		seen[productID] = true
+
+		product, err := s.Repo.ProductRepository.GetByID(productID)
+		if err != nil {
+			s.Logger.Error("error get product by id", zap.Error(err))
+			return promotion, err
+		}
+		if product.ID == 0 {
+			return promotion, fmt.Errorf("%w: product %d not found", ErrCartPromotionInvalid, productID)
+		}
+	}
+
+	switch promotion.RuleType {
+	case model.CartRuleBuyXGetY:
+		promotion.BuyQuantity = promotionInput.BuyQuantity
+		promotion.GetQuantity = promotionInput.GetQuantity
+		if promotion.BuyQuantity <= 0 || promotion.GetQuantity <= 0 {
+			return promotion, fmt.Errorf("%w: buy_quantity and get_quantity must be greater than 0", ErrCartPromotionInvalid)
+		}
+		if promotion.DiscountValue == 0 {
+			promotion.DiscountValue = 100
+		}
+	case model.CartRuleBundle:
+		if len(promotion.ProductIDs) < 2 {
+			return promotion, fmt.Errorf("%w: a bundle needs at least 2 products", ErrCartPromotionInvalid)
+		}
+	case model.CartRuleTiered:
+		if len(promotionInput.Tiers) == 0 {
+			return promotion, fmt.Errorf("%w: tiers is required", ErrCartPromotionInvalid)
+		}
+		promotion.DiscountValue = 0
+		promotion.Tiers = append(promotion.Tiers, promotionInput.Tiers...)
+		sort.Slice(promotion.Tiers, func(i, j int) bool { return promotion.Tiers[i].MinQuantity < promotion.Tiers[j].MinQuantity })
+		for i, tier := range promotion.Tiers {
+			if tier.MinQuantity <= 0 || tier.DiscountValue <= 0 || tier.DiscountValue > 100 {
+				return promotion, fmt.Errorf("%w: each tier needs a min_quantity and a discount_value between 0 and 100", ErrCartPromotionInvalid)
+			}
+			if i > 0 && tier.MinQuantity == promotion.Tiers[i-1].MinQuantity {
+				return promotion, fmt.Errorf("%w: tier min_quantity must be unique", ErrCartPromotionInvalid)
+			}
+		}
+	default:
+		return promotion, fmt.Errorf("%w: rule_type must be buy_x_get_y, bundle or tiered", ErrCartPromotionInvalid)
+	}
+
+	if promotion.RuleType != model.CartRuleTiered && (promotion.DiscountValue <= 0 || promotion.DiscountValue > 100) {
+		return promotion, fmt.Errorf("%w: discount_value must be between 0 and 100", ErrCartPromotionInvalid)
+	}
+	if promotion.StartsAt.IsZero() || !promotion.EndsAt.After(promotion.StartsAt) {
+		return promotion, fmt.Errorf("%w: ends_at must be after starts_at", ErrCartPromotionInvalid)
+	}
+	return promotion, nil
+}
@@ -2,6 +2,8 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"sort"
 
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
@@ -9,13 +11,20 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	ErrCartItemNotFound   = errors.New("cart item not found")
+	ErrCartVariantInvalid = errors.New("invalid variant selection")
+)
+
 type CartService struct {
-	Repo   repository.MainRepository
-	Logger *zap.Logger
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+	Coupon  CouponService
 }
 
-func NewCartService(repo repository.MainRepository, logger *zap.Logger) CartService {
-	return CartService{Repo: repo, Logger: logger}
+func NewCartService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, coupon CouponService) CartService {
+	return CartService{Repo: repo, Logger: logger, Pricing: pricing, Coupon: coupon}
 }
 
 func (s *CartService) AddProductToCart(userID string, CartInput model.CartItemDTO) error {
@@ -47,36 +56,66 @@ func (s *CartService) AddProductToCart(userID string, CartInput model.CartItemDT
 		s.Logger.Error("error get product by id", zap.Error(err))
 		return err
 	}
+	if product.ID == 0 || product.PublishStatus != model.PublishStatusPublished {
+		return errors.New("product not found")
+	}
+
+	if CartInput.Amount == 0 {
+		CartInput.Amount = 1
+	}
 
-	weekly, err := s.Repo.ProductRepository.GetPromoProduct(product.ID)
+	var optionIDs []int
+	if product.HasVariant {
+		optionIDs = variantOptionIDs(CartInput.Variant)
+	}
+	additionalPrice, err := s.Pricing.VariantAdditionalPrice(optionIDs)
 	if err != nil {
-		s.Logger.Error("error get weekly promo by product id", zap.Error(err))
 		return err
 	}
-	if weekly.ID != 0 {
-		product.PriceAfterDiscount = helper.CalculateDiscountPrice(product.PriceAfterDiscount, weekly.PromoDiscount)
+
+	// The same product with the same options goes on the existing line
+	existingItem, err := s.Repo.CartRepository.FindItem(cart.ID, product.ID, optionIDs, 0, false)
+	if err != nil {
+		s.Logger.Error("error find cart item", zap.Error(err))
+		return err
 	}
-	if CartInput.Amount == 0 {
-		CartInput.Amount = 1
+	if existingItem.ID != 0 {
+		existingItem.Amount += CartInput.Amount
+		price, err := s.Pricing.PriceItem(product, additionalPrice, existingItem.Amount)
+		if err != nil {
+			s.Logger.Error("error price cart item", zap.Error(err))
+			return err
+		}
+		existingItem.SubTotal = price.SubTotal
+		if err = s.Repo.CartRepository.UpdateItem(existingItem); err != nil {
+			s.Logger.Error("error update item in cart", zap.Error(err))
+			return err
+		}
+		return s.Repo.CartRepository.RecalculateTotal(cart.ID)
+	}
+
+	price, err := s.Pricing.PriceItem(product, additionalPrice, CartInput.Amount)
+	if err != nil {
+		s.Logger.Error("error price cart item", zap.Error(err))
+		return err
 	}
+
 	cartItem := model.CartItem{
 		ProductID: CartInput.ProductID,
 		Amount:    CartInput.Amount,
-		SubTotal:  product.PriceAfterDiscount * float64(CartInput.Amount),
+		SubTotal:  price.SubTotal,
 		Product:   product,
 		CartID:    cart.ID,
 	}
-	additionalPrice, err := s.AddItemToCart(cartItem, CartInput.Variant)
+	err = s.AddItemToCart(cartItem, CartInput.Variant)
 	if err != nil {
 		s.Logger.Error("error add item to second cart", zap.Error(err))
 		return err
 	}
 
-	cart.TotalAmount += cartItem.Amount
-	cart.TotalPrice += (additionalPrice + product.PriceAfterDiscount) * float64(CartInput.Amount)
-	err = s.UpdateCart(cart)
+	err = s.Repo.CartRepository.RecalculateTotal(cart.ID)
 	if err != nil {
-		s.Logger.Error("error update second cart", zap.Error(err))
+		s.Logger.Error("error recalculate total amount in cart", zap.Error(err))
 		return err
 	}
 	return nil
@@ -92,26 +131,61 @@ func (s *CartService) GetCartByUserID(userID string) (model.Cart, error) {
 		return model.Cart{}, err
 	}
 
+	pricing, err := s.Pricing.PriceCart(cartItems)
+	if err != nil {
+		s.Logger.Error("error price cart", zap.Error(err))
+		return model.Cart{}, err
+	}
+
+	newCartItem, err := s.describeVariants(pricing.Items)
+	if err != nil {
+		return model.Cart{}, err
+	}
+	cart.Items = newCartItem
+	cart.SubTotal = pricing.SubTotal
+	cart.Discounts = pricing.Discounts
+	cart.DiscountTotal = pricing.DiscountTotal
+	cart.TotalPrice = pricing.Total
+
+	savedItems, err := s.Repo.CartRepository.GetSavedItems(cart.ID)
+	if err != nil {
+		return model.Cart{}, err
+	}
+	savedItems, err = s.Pricing.PriceLines(savedItems)
+	if err != nil {
+		s.Logger.Error("error price saved items", zap.Error(err))
+		return model.Cart{}, err
+	}
+	cart.SavedItems, err = s.describeVariants(savedItems)
+	if err != nil {
+		return model.Cart{}, err
+	}
+
+	cart.Coupon, err = s.Coupon.CartCoupon(userID, cart, newCartItem)
+	if err != nil {
+		s.Logger.Error("error get cart coupon", zap.Error(err))
+		return model.Cart{}, err
+	}
+	return cart, nil
+}
+
+// describeVariants fills in the variant and option of every selection of
+// the priced lines.
+func (s *CartService) describeVariants(items []model.CartItem) ([]model.CartItem, error) {
 	var newCartItem []model.CartItem
-	for _, item := range cartItems {
-		product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
-		if err != nil {
-			s.Logger.Error("error get product by id", zap.Error(err))
-			return model.Cart{}, err
-		}
-		item.Product = product
+	for _, item := range items {
 		NewVariants := []model.CarttemVariant{}
-		if product.HasVariant {
+		if item.Product.HasVariant {
 			for _, variantItem := range item.ItemVariant {
 				variant, err := s.Repo.VariantRepository.GetVariantByID(int(variantItem.VariantID.Int64))
 				if err != nil {
 					s.Logger.Error("error get variant by id", zap.Error(err))
-					return model.Cart{}, err
+					return nil, err
 				}
 				option, err := s.Repo.VariantRepository.GetVariantOptionByID(int(variantItem.OptionID.Int64))
 				if err != nil {
 					s.Logger.Error("error get variant option by id", zap.Error(err))
-					return model.Cart{}, err
+					return nil, err
 				}
 
 				variantItem.Variant = variant
@@ -122,39 +196,189 @@ func (s *CartService) GetCartByUserID(userID string) (model.Cart, error) {
 		}
 		newCartItem = append(newCartItem, item)
 	}
-	cart.Items = newCartItem
-	return cart, nil
+	return newCartItem, nil
+}
+
+// ValidateCart revalidates the user's cart before checkout.
+func (s *CartService) ValidateCart(userID string) (model.CartValidation, error) {
+	cart, err := s.Repo.CartRepository.GetByUserID(userID)
+	if err != nil {
+		return model.CartValidation{}, err
+	}
+	cart.UserID = userID
+	validation, err := s.Revalidate(cart)
+	if err != nil {
+		return validation, err
+	}
+
+	validation.Cart.Items, err = s.describeVariants(validation.Cart.Items)
+	if err != nil {
+		return validation, err
+	}
+	validation.Cart.Coupon, err = s.Coupon.CartCoupon(userID, validation.Cart, validation.Cart.Items)
+	if err != nil {
+		s.Logger.Error("error get cart coupon", zap.Error(err))
+		return validation, err
+	}
+	return validation, nil
+}
+
+// Revalidate reprices every line of the cart with the current product,
+// variant, promotion and flash sale data. Lines whose product or option is
+// gone or short of stock are flagged and left out of the pricing. Lines
+// whose price moved are flagged and keep the new subtotal, and the cart
+// totals are recalculated.
+func (s *CartService) Revalidate(cart model.Cart) (model.CartValidation, error) {
+	validation := model.CartValidation{Valid: true, Issues: []model.CartIssue{}, Cart: cart}
+	if cart.ID == 0 {
+		return validation, nil
+	}
+	items, err := s.Repo.CartRepository.GetItems(cart.ID)
+	if err != nil {
+		s.Logger.Error("error get cart items", zap.Error(err))
+		return validation, err
+	}
+
+	// Lines of the same product or option draw on the same stock
+	productAmounts := make(map[int]int)
+	optionAmounts := make(map[int]int)
+	for _, item := range items {
+		productAmounts[item.ProductID] += item.Amount
+		for _, variant := range item.ItemVariant {
+			if variant.OptionID.Valid {
+				optionAmounts[int(variant.OptionID.Int64)] += item.Amount
+			}
+		}
+	}
+
+	var available []model.CartItem
+	storedSubTotals := make(map[int]model.Money, len(items))
+	for _, item := range items {
+		issue, err := s.lineIssue(item, productAmounts, optionAmounts)
+		if err != nil {
+			return validation, err
+		}
+		if issue != nil {
+			validation.Issues = append(validation.Issues, *issue)
+			validation.Valid = false
+			continue
+		}
+		storedSubTotals[item.ID] = item.SubTotal
+		available = append(available, item)
+	}
+
+	pricing, err := s.Pricing.PriceCart(available)
+	if err != nil {
+		s.Logger.Error("error price cart", zap.Error(err))
+		return validation, err
+	}
+	repriced := false
+	validation.Cart.TotalAmount = 0
+	for _, item := range pricing.Items {
+		validation.Cart.TotalAmount += item.Amount
+		stored := helper.RoundPrice(storedSubTotals[item.ID])
+		if stored == item.SubTotal {
+			continue
+		}
+		validation.Issues = append(validation.Issues, model.CartIssue{
+			CartItemID:  item.ID,
+			ProductID:   item.ProductID,
+			Reason:      model.CartIssuePriceChanged,
+			Message:     "price has changed since the product was added",
+			OldSubTotal: stored,
+			NewSubTotal: item.SubTotal,
+		})
+		err = s.Repo.CartRepository.UpdateItem(model.CartItem{ID: item.ID, SubTotal: item.SubTotal})
+		if err != nil {
+			s.Logger.Error("error update item in cart", zap.Error(err))
+			return validation, err
+		}
+		repriced = true
+	}
+	if repriced {
+		err = s.Repo.CartRepository.RecalculateTotal(cart.ID)
+		if err != nil {
+			s.Logger.Error("error recalculate total amount in cart", zap.Error(err))
+			return validation, err
+		}
+	}
+
+	validation.Pricing = pricing
+	validation.Cart.Items = pricing.Items
+	validation.Cart.SubTotal = pricing.SubTotal
+	validation.Cart.Discounts = pricing.Discounts
+	validation.Cart.DiscountTotal = pricing.DiscountTotal
+	validation.Cart.TotalPrice = pricing.Total
+	return validation, nil
 }
 
-func (s *CartService) AddItemToCart(itemInput model.CartItem, itemVariantInput []model.CartItemVariantDTO) (float64, error) {
-	var AdditionalPrice = 0.0
+// lineIssue returns why a cart line cannot be ordered anymore, or nil.
+// productAmounts and optionAmounts hold the quantity the whole cart takes
+// of each product and variant option.
+func (s *CartService) lineIssue(item model.CartItem, productAmounts, optionAmounts map[int]int) (*model.CartIssue, error) {
+	issue := &model.CartIssue{CartItemID: item.ID, ProductID: item.ProductID}
+	product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err))
+		return nil, err
+	}
+	if product.ID == 0 || product.PublishStatus != model.PublishStatusPublished {
+		issue.Reason, issue.Message = model.CartIssueUnavailable, "product is no longer available"
+		return issue, nil
+	}
+
+	if productAmounts[product.ID] > product.TotalStock {
+		issue.Reason = model.CartIssueOutOfStock
+		issue.Message = fmt.Sprintf("only %d left in stock", max(product.TotalStock, 0))
+		return issue, nil
+	}
+	if !product.HasVariant {
+		return nil, nil
+	}
+
+	if len(item.ItemVariant) == 0 {
+		issue.Reason, issue.Message = model.CartIssueVariantUnavailable, "no option is selected"
+		return issue, nil
+	}
+	for _, variant := range item.ItemVariant {
+		if !variant.OptionID.Valid {
+			issue.Reason, issue.Message = model.CartIssueVariantUnavailable, "selected option is no longer available"
+			return issue, nil
+		}
+		option, err := s.Repo.VariantRepository.GetVariantOptionByID(int(variant.OptionID.Int64))
+		if err != nil {
+			s.Logger.Error("error get variant option by id", zap.Error(err))
+			return nil, err
+		}
+		if option.ID == 0 {
+			issue.Reason, issue.Message = model.CartIssueVariantUnavailable, "selected option is no longer available"
+			return issue, nil
+		}
+		if optionAmounts[option.ID] > option.Stock {
+			issue.Reason = model.CartIssueOutOfStock
+			issue.Message = fmt.Sprintf("only %d left in stock", max(option.Stock, 0))
+			return issue, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *CartService) AddItemToCart(itemInput model.CartItem, itemVariantInput []model.CartItemVariantDTO) error {
 	item, err := s.Repo.CartRepository.AddItem(itemInput)
 	if err != nil {
 		s.Logger.Error("error add item to second cart", zap.Error(err))
-		return AdditionalPrice, err
+		return err
 	}
 	if item.Product.HasVariant {
 		for _, v := range itemVariantInput {
 			err := s.AddVariantItem(item.ID, v)
 			if err != nil {
 				s.Logger.Error("error add variant item to second cart", zap.Error(err))
-				return AdditionalPrice, err
-			}
-
-			for _, pv := range item.Product.Variant {
-				if v.VariantID == pv.ID {
-					for _, option := range pv.VariantOption {
-						if v.VariantOptionID == option.ID {
-							AdditionalPrice += option.AdditionalPrice
-							break
-						}
-					}
-				}
+				return err
 			}
 		}
-
 	}
-	return AdditionalPrice, nil
+	return nil
 }
 
 func (s *CartService) AddVariantItem(cartItemID int, variantInput model.CartItemVariantDTO) error {
@@ -186,42 +410,193 @@ func (s *CartService) UpdateItemInCart(userId string, itemInput model.CartItem)
 		return errors.New("UserID mismatch for cart")
 	}
 
-	product, err := s.Repo.ProductRepository.GetByID(itemInput.ProductID)
+	itemVariant, err := s.Repo.CartRepository.GetItemVariants(itemInput.ID)
 	if err != nil {
-		s.Logger.Error("error get product by id", zap.Error(err))
+		s.Logger.Error("error get item variants", zap.Error(err))
 		return err
 	}
+	itemInput.ItemVariant = itemVariant
 
-	weekly, err := s.Repo.ProductRepository.GetPromoProduct(product.ID)
+	_, price, err := s.Pricing.PriceCartItem(itemInput)
 	if err != nil {
-		s.Logger.Error("error get weekly promo by product id", zap.Error(err))
+		s.Logger.Error("error price cart item", zap.Error(err))
 		return err
 	}
-	if weekly.ID != 0 {
-		product.PriceAfterDiscount = helper.CalculateDiscountPrice(product.PriceAfterDiscount, weekly.PromoDiscount)
+
+	itemInput.SubTotal = price.SubTotal
+	err = s.Repo.CartRepository.UpdateItem(itemInput)
+	if err != nil {
+		s.Logger.Error("error update item in cart", zap.Error(err))
+		return err
 	}
+	err = s.Repo.CartRepository.RecalculateTotal(cart.ID)
+	if err != nil {
+		s.Logger.Error("error recalculate total amount in cart", zap.Error(err))
+		return err
+	}
+	return nil
+}
 
-	var additional_costs float64
-	if product.HasVariant {
-		itemVariant, err := s.Repo.CartRepository.GetItemVariants(itemInput.ID)
+// UpdateItemVariants changes the variant options of a cart line. When the
+// new selection matches another line of the same product, the two lines are
+// merged.
+func (s *CartService) UpdateItemVariants(userID string, cartItemID int, variantInput model.CartItemVariantsDTO) error {
+	item, cart, err := s.userCartItem(userID, cartItemID)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err))
+		return err
+	}
+	if product.ID == 0 || product.PublishStatus != model.PublishStatusPublished {
+		return fmt.Errorf("%w: %d", ErrProductNotFound, item.ProductID)
+	}
+	variants, err := s.validVariants(product, variantInput.Variant)
+	if err != nil {
+		return err
+	}
+
+	optionIDs := variantOptionIDs(variants)
+	additionalPrice, err := s.Pricing.VariantAdditionalPrice(optionIDs)
+	if err != nil {
+		return err
+	}
+
+	existingItem, err := s.Repo.CartRepository.FindItem(cart.ID, product.ID, optionIDs, item.ID, item.SavedForLater)
+	if err != nil {
+		s.Logger.Error("error find cart item", zap.Error(err))
+		return err
+	}
+	if existingItem.ID != 0 {
+		existingItem.Amount += item.Amount
+		price, err := s.Pricing.PriceItem(product, additionalPrice, existingItem.Amount)
+		if err != nil {
+			s.Logger.Error("error price cart item", zap.Error(err))
+			return err
+		}
+		existingItem.SubTotal = price.SubTotal
+		err = s.Repo.CartRepository.MergeItem(item.ID, existingItem)
+		if err != nil {
+			s.Logger.Error("error merge cart items", zap.Error(err))
+			return err
+		}
+	} else {
+		price, err := s.Pricing.PriceItem(product, additionalPrice, item.Amount)
+		if err != nil {
+			s.Logger.Error("error price cart item", zap.Error(err))
+			return err
+		}
+		err = s.Repo.CartRepository.ReplaceItemVariants(item.ID, variants, price.SubTotal)
 		if err != nil {
-			s.Logger.Error("error get item variants", zap.Error(err))
+			s.Logger.Error("error replace cart item variants", zap.Error(err))
 			return err
 		}
+	}
 
-		if len(itemVariant) > 0 {
-			for _, variant := range itemVariant {
-				additional_costs += variant.AdditionalPrice
-			}
+	err = s.Repo.CartRepository.RecalculateTotal(cart.ID)
+	if err != nil {
+		s.Logger.Error("error recalculate total amount in cart", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// userCartItem returns a line of the user's cart and the cart itself.
+func (s *CartService) userCartItem(userID string, cartItemID int) (model.CartItem, model.Cart, error) {
+	item, err := s.Repo.CartRepository.GetItemByID(cartItemID)
+	if err != nil {
+		s.Logger.Error("error get cart item by id", zap.Error(err))
+		return item, model.Cart{}, err
+	}
+	if item.ID == 0 {
+		return item, model.Cart{}, fmt.Errorf("%w: %d", ErrCartItemNotFound, cartItemID)
+	}
+	cart, err := s.Repo.CartRepository.GetByID(item.CartID)
+	if err != nil {
+		s.Logger.Error("error get cart by id", zap.Error(err))
+		return item, cart, err
+	}
+	if cart.ID == 0 || cart.UserID != userID {
+		return item, cart, fmt.Errorf("%w: %d", ErrCartItemNotFound, cartItemID)
+	}
+	return item, cart, nil
+}
+
+// SaveForLater moves a cart line to the saved for later list. The line
+// keeps its variant selection and no longer counts towards the cart totals.
+func (s *CartService) SaveForLater(userID string, cartItemID int) error {
+	return s.setItemSaved(userID, cartItemID, true)
+}
+
+// MoveToCart moves a saved line back into the cart at the current price.
+func (s *CartService) MoveToCart(userID string, cartItemID int) error {
+	return s.setItemSaved(userID, cartItemID, false)
+}
+
+// setItemSaved moves a line between the cart and the saved for later list.
+// A line matching one already on the other side is merged into it.
+func (s *CartService) setItemSaved(userID string, cartItemID int, saved bool) error {
+	item, cart, err := s.userCartItem(userID, cartItemID)
+	if err != nil {
+		return err
+	}
+	if item.SavedForLater == saved {
+		return nil
+	}
+
+	product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err))
+		return err
+	}
+	// Anything can be saved, but only what is on sale goes back in the cart
+	if !saved && (product.ID == 0 || product.PublishStatus != model.PublishStatusPublished) {
+		return fmt.Errorf("%w: %d", ErrProductNotFound, item.ProductID)
+	}
+
+	item.ItemVariant, err = s.Repo.CartRepository.GetItemVariants(item.ID)
+	if err != nil {
+		s.Logger.Error("error get item variants", zap.Error(err))
+		return err
+	}
+	optionIDs := itemOptionIDs(item.ItemVariant)
+
+	existingItem, err := s.Repo.CartRepository.FindItem(cart.ID, item.ProductID, optionIDs, item.ID, saved)
+	if err != nil {
+		s.Logger.Error("error find cart item", zap.Error(err))
+		return err
+	}
+	if existingItem.ID != 0 {
+		existingItem.Amount += item.Amount
+		item = existingItem
+	}
+	// Saved lines are repriced when shown; cart lines need their subtotal now
+	if !saved {
+		additionalPrice, err := s.Pricing.VariantAdditionalPrice(optionIDs)
+		if err != nil {
+			return err
 		}
+		price, err := s.Pricing.PriceItem(product, additionalPrice, item.Amount)
+		if err != nil {
+			s.Logger.Error("error price cart item", zap.Error(err))
+			return err
+		}
+		item.SubTotal = price.SubTotal
 	}
 
-	itemInput.SubTotal = (product.PriceAfterDiscount + additional_costs) * float64(itemInput.Amount)
-	err = s.Repo.CartRepository.UpdateItem(itemInput)
+	if existingItem.ID != 0 {
+		err = s.Repo.CartRepository.MergeItem(cartItemID, item)
+	} else {
+		err = s.Repo.CartRepository.SetItemSaved(cartItemID, saved, item.SubTotal)
+	}
 	if err != nil {
-		s.Logger.Error("error update item in cart", zap.Error(err))
+		s.Logger.Error("error move cart item", zap.Error(err))
 		return err
 	}
+
 	err = s.Repo.CartRepository.RecalculateTotal(cart.ID)
 	if err != nil {
 		s.Logger.Error("error recalculate total amount in cart", zap.Error(err))
@@ -230,6 +605,65 @@ func (s *CartService) UpdateItemInCart(userId string, itemInput model.CartItem)
 	return nil
 }
 
+// validVariants checks that every selected option belongs to its variant of
+// the product, one option per variant, and fills in the additional prices.
+func (s *CartService) validVariants(product model.Product, selection []model.CartItemVariantDTO) ([]model.CartItemVariantDTO, error) {
+	if !product.HasVariant {
+		return nil, fmt.Errorf("%w: product %d has no variants", ErrCartVariantInvalid, product.ID)
+	}
+	productVariants, err := s.Repo.VariantRepository.GetByProductId(product.ID)
+	if err != nil {
+		s.Logger.Error("error get variants by product id", zap.Error(err))
+		return nil, err
+	}
+	options := make(map[int]map[int]model.VariantOption, len(productVariants))
+	for _, variant := range productVariants {
+		options[variant.ID] = make(map[int]model.VariantOption, len(variant.VariantOption))
+		for _, option := range variant.VariantOption {
+			options[variant.ID][option.ID] = option
+		}
+	}
+
+	seen := make(map[int]bool, len(selection))
+	variants := make([]model.CartItemVariantDTO, 0, len(selection))
+	for _, v := range selection {
+		option, ok := options[v.VariantID][v.VariantOptionID]
+		if !ok {
+			return nil, fmt.Errorf("%w: option %d is not an option of variant %d", ErrCartVariantInvalid, v.VariantOptionID, v.VariantID)
+		}
+		if seen[v.VariantID] {
+			return nil, fmt.Errorf("%w: variant %d selected more than once", ErrCartVariantInvalid, v.VariantID)
+		}
+		seen[v.VariantID] = true
+		v.AdditionalPrice = option.AdditionalPrice
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// variantOptionIDs returns the sorted option IDs of a variant selection, the
+// key identical cart lines share.
+func variantOptionIDs(variants []model.CartItemVariantDTO) []int {
+	optionIDs := make([]int, 0, len(variants))
+	for _, variant := range variants {
+		optionIDs = append(optionIDs, variant.VariantOptionID)
+	}
+	sort.Ints(optionIDs)
+	return optionIDs
+}
+
+// itemOptionIDs returns the sorted option IDs of a stored cart line.
+func itemOptionIDs(variants []model.CarttemVariant) []int {
+	optionIDs := make([]int, 0, len(variants))
+	for _, variant := range variants {
+		if variant.OptionID.Valid {
+			optionIDs = append(optionIDs, int(variant.OptionID.Int64))
+		}
+	}
+	sort.Ints(optionIDs)
+	return optionIDs
+}
+
 func (s *CartService) DeleteProductInCart(cartItemID int) error {
 	cartItem, err := s.Repo.CartRepository.GetItemByID(cartItemID)
 	if err != nil {
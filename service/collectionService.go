@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrCollectionInvalid  = errors.New("invalid collection")
+)
+
+const maxTagLength = 50
+
+var (
+	ruleSeparator = regexp.MustCompile(`(?i)\s+and\s+`)
+	ruleCondition = regexp.MustCompile(`^([a-z_]+)\s*(>=|<=|!=|=|>|<)\s*(.+)$`)
+)
+
+type CollectionService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Product ProductService
+}
+
+func NewCollectionService(repo repository.MainRepository, logger *zap.Logger, product ProductService) CollectionService {
+	return CollectionService{Repo: repo, Logger: logger, Product: product}
+}
+
+func (s CollectionService) GetCollections(pagination model.Pagination) ([]model.Collection, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.CollectionRepository.GetAll(pagination)
+}
+
+func (s CollectionService) GetCollection(id int) (model.Collection, error) {
+	collection, err := s.getCollection(id)
+	if err != nil {
+		return collection, err
+	}
+	if collection.Type == model.CollectionTypeManual {
+		collection.ProductIDs, err = s.Repo.CollectionRepository.GetProductIDs(collection.ID)
+		if err != nil {
+			return collection, err
+		}
+	}
+	return collection, nil
+}
+
+// GetCollectionProducts lists the products of the collection with the given
+// slug, with the same filters as the product listing.
+func (s CollectionService) GetCollectionProducts(slug string, productFilter model.ProductDTO, pagination model.Pagination) (model.Collection, []model.Product, model.Pagination, error) {
+	collection, err := s.Repo.CollectionRepository.GetBySlug(slug)
+	if err != nil {
+		s.Logger.Error("error get collection", zap.Error(err), zap.String("service", "Collection"), zap.String("function", "GetCollectionProducts"))
+		return collection, nil, pagination, err
+	}
+	if collection.ID == 0 {
+		return collection, nil, pagination, fmt.Errorf("%w: %s", ErrCollectionNotFound, slug)
+	}
+
+	switch collection.Type {
+	case model.CollectionTypeManual:
+		productFilter.CollectionID = collection.ID
+	case model.CollectionTypeRule:
+		productFilter.Conditions, err = parseCollectionRule(collection.Rule)
+		if err != nil {
+			return collection, nil, pagination, err
+		}
+	}
+	products, pagination, err := s.Product.GetAllProduct(productFilter, pagination)
+	if err != nil {
+		return collection, nil, pagination, err
+	}
+	return collection, products, pagination, nil
+}
+
+func (s CollectionService) CreateCollection(collectionInput model.CollectionDTO) (model.Collection, error) {
+	collection, err := s.validateCollection(0, collectionInput)
+	if err != nil {
+		return collection, err
+	}
+	return s.Repo.CollectionRepository.Create(collection)
+}
+
+func (s CollectionService) UpdateCollection(id int, collectionInput model.CollectionDTO) (model.Collection, error) {
+	if _, err := s.getCollection(id); err != nil {
+		return model.Collection{}, err
+	}
+	collection, err := s.validateCollection(id, collectionInput)
+	if err != nil {
+		return collection, err
+	}
+	if err := s.Repo.CollectionRepository.Update(collection); err != nil {
+		return collection, err
+	}
+	return collection, nil
+}
+
+func (s CollectionService) DeleteCollection(id int) error {
+	if _, err := s.getCollection(id); err != nil {
+		return err
+	}
+	return s.Repo.CollectionRepository.Delete(id)
+}
+
+// SetProductTags replaces the tags of a product. Tags are lower case and
+// listed once.
+func (s CollectionService) SetProductTags(productID int, tagsInput []string) ([]string, error) {
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Collection"), zap.String("function", "SetProductTags"))
+		return nil, err
+	}
+	if product.ID == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+
+	tags := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tagsInput {
+		tag = normalizeTag(tag)
+		if tag == "" {
+			return nil, fmt.Errorf("%w: tags cannot be empty", ErrCollectionInvalid)
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("%w: tag %s is longer than %d characters", ErrCollectionInvalid, tag, maxTagLength)
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	if err := s.Repo.CollectionRepository.SetProductTags(productID, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (s CollectionService) getCollection(id int) (model.Collection, error) {
+	collection, err := s.Repo.CollectionRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get collection", zap.Error(err), zap.String("service", "Collection"), zap.String("function", "getCollection"))
+		return collection, err
+	}
+	if collection.ID == 0 {
+		return collection, fmt.Errorf("%w: %d", ErrCollectionNotFound, id)
+	}
+	return collection, nil
+}
+
+func (s CollectionService) validateCollection(id int, collectionInput model.CollectionDTO) (model.Collection, error) {
+	collection := model.Collection{
+		ID:          id,
+		Name:        strings.TrimSpace(collectionInput.Name),
+		Slug:        helper.Slugify(collectionInput.Slug),
+		Description: strings.TrimSpace(collectionInput.Description),
+		Type:        strings.ToLower(strings.TrimSpace(collectionInput.Type)),
+		Rule:        strings.TrimSpace(collectionInput.Rule),
+	}
+	if collection.Name == "" {
+		return collection, fmt.Errorf("%w: name is required", ErrCollectionInvalid)
+	}
+	if collection.Slug == "" {
+		collection.Slug = helper.Slugify(collection.Name)
+	}
+	if collection.Slug == "" {
+		return collection, fmt.Errorf("%w: name needs letters or digits for a slug", ErrCollectionInvalid)
+	}
+	if collection.Type == "" {
+		collection.Type = model.CollectionTypeManual
+	}
+
+	switch collection.Type {
+	case model.CollectionTypeManual:
+		if collection.Rule != "" {
+			return collection, fmt.Errorf("%w: a manual collection has no rule", ErrCollectionInvalid)
+		}
+		seen := make(map[int]bool)
+		for _, productID := range collectionInput.ProductIDs {
+			if seen[productID] {
+				return collection, fmt.Errorf("%w: product %d is listed twice", ErrCollectionInvalid, productID)
+			}
+			seen[productID] = true
+			product, err := s.Repo.ProductRepository.GetByID(productID)
+			if err != nil {
+				s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Collection"), zap.String("function", "validateCollection"))
+				return collection, err
+			}
+			if product.ID == 0 {
+				return collection, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+			}
+			collection.ProductIDs = append(collection.ProductIDs, productID)
+		}
+	case model.CollectionTypeRule:
+		if len(collectionInput.ProductIDs) > 0 {
+			return collection, fmt.Errorf("%w: a rule collection has no product list", ErrCollectionInvalid)
+		}
+		if _, err := parseCollectionRule(collection.Rule); err != nil {
+			return collection, err
+		}
+	default:
+		return collection, fmt.Errorf("%w: type must be %s or %s", ErrCollectionInvalid, model.CollectionTypeManual, model.CollectionTypeRule)
+	}
+
+	existing, err := s.Repo.CollectionRepository.GetBySlug(collection.Slug)
+	if err != nil {
+		s.Logger.Error("error get collection", zap.Error(err), zap.String("service", "Collection"), zap.String("function", "validateCollection"))
+		return collection, err
+	}
+	if existing.ID != 0 && existing.ID != id {
+		return collection, fmt.Errorf("%w: slug %s is already used", ErrCollectionInvalid, collection.Slug)
+	}
+	return collection, nil
+}
+
+// parseCollectionRule reads conditions joined by AND, such as
+// "tag=summer AND discount>20". Tags, categories (by ID) and brands (by
+// slug) compare with = and !=; price, discount, rating and stock with any of
+// = != > >= < <=.
+func parseCollectionRule(rule string) ([]model.CollectionCondition, error) {
+	if strings.TrimSpace(rule) == "" {
+		return nil, fmt.Errorf("%w: a rule collection needs a rule", ErrCollectionInvalid)
+	}
+
+	var conditions []model.CollectionCondition
+	for _, part := range splitRule(strings.TrimSpace(rule)) {
+		match := ruleCondition.FindStringSubmatch(strings.ToLower(strings.TrimSpace(part)))
+		if match == nil {
+			return nil, fmt.Errorf("%w: cannot read condition %q", ErrCollectionInvalid, part)
+		}
+		condition := model.CollectionCondition{
+			Field:    match[1],
+			Operator: match[2],
+			Value:    strings.Trim(strings.TrimSpace(match[3]), `"'`),
+		}
+		if condition.Value == "" {
+			return nil, fmt.Errorf("%w: condition %q has no value", ErrCollectionInvalid, part)
+		}
+
+		switch condition.Field {
+		case "tag", "category", "brand":
+			if condition.Operator != "=" && condition.Operator != "!=" {
+				return nil, fmt.Errorf("%w: %s only compares with = or !=", ErrCollectionInvalid, condition.Field)
+			}
+			if condition.Field == "tag" {
+				condition.Value = normalizeTag(condition.Value)
+			}
+			if condition.Field == "category" {
+				if _, err := strconv.Atoi(condition.Value); err != nil {
+					return nil, fmt.Errorf("%w: category takes a category ID", ErrCollectionInvalid)
+				}
+			}
+		case "price", "discount", "rating", "stock":
+			if _, err := strconv.ParseFloat(condition.Value, 64); err != nil {
+				return nil, fmt.Errorf("%w: %s takes a number", ErrCollectionInvalid, condition.Field)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown rule field %s", ErrCollectionInvalid, condition.Field)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// splitRule splits a rule on AND. A value in single or double quotes is kept
+// whole, so tag="rock and roll" is one condition.
+func splitRule(rule string) []string {
+	var parts []string
+	start := 0
+	for _, match := range ruleSeparator.FindAllStringIndex(rule, -1) {
+		if inQuotes(rule[start:match[0]]) {
+			continue
+		}
+		parts = append(parts, rule[start:match[0]])
+		start = match[1]
+	}
+	return append(parts, rule[start:])
+}
+
+// inQuotes reports whether a quote opened in text is still open at its end.
+func inQuotes(text string) bool {
+	var quote rune
+	for _, r := range text {
+		switch {
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case r == quote:
+			quote = 0
+		}
+	}
+	return quote != 0
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
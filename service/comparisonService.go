@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var ErrComparisonInvalid = errors.New("invalid comparison")
+
+const (
+	minCompareProducts = 2
+	maxCompareProducts = 4
+)
+
+type ComparisonService struct {
+	Repo        repository.MainRepository
+	Logger      *zap.Logger
+	Product     ProductService
+	Currency    CurrencyService
+	Translation TranslationService
+}
+
+func NewComparisonService(repo repository.MainRepository, logger *zap.Logger, product ProductService, currency CurrencyService, translation TranslationService) ComparisonService {
+	return ComparisonService{Repo: repo, Logger: logger, Product: product, Currency: currency, Translation: translation}
+}
+
+// CompareProducts returns the products in the given order with a row per
+// price, rating, stock, variant attribute and specification. Prices are in
+// the currency and text in the locale of the request.
+func (s ComparisonService) CompareProducts(productIDs []int, currency model.Currency, locale string) (model.ProductComparison, error) {
+	var comparison model.ProductComparison
+	if len(productIDs) < minCompareProducts || len(productIDs) > maxCompareProducts {
+		return comparison, fmt.Errorf("%w: compare %d to %d products", ErrComparisonInvalid, minCompareProducts, maxCompareProducts)
+	}
+	seen := make(map[int]bool)
+	for _, id := range productIDs {
+		if seen[id] {
+			return comparison, fmt.Errorf("%w: product %d is listed twice", ErrComparisonInvalid, id)
+		}
+		seen[id] = true
+	}
+
+	products := make([]*model.Product, len(productIDs))
+	discounted := make([]model.Money, len(productIDs))
+	for i, id := range productIDs {
+		product, err := s.Product.GetProductByID(id)
+		if err != nil {
+			if errors.Is(err, ErrProductNotFound) {
+				return comparison, fmt.Errorf("%w: %d", ErrProductNotFound, id)
+			}
+			s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Comparison"), zap.String("function", "CompareProducts"))
+			return comparison, err
+		}
+		discounted[i] = helper.ConvertPrice(helper.CalculateDiscountPrice(product.Price, product.Discount), currency)
+		s.Currency.ConvertProduct(product, currency)
+		products[i] = product
+	}
+	s.Translation.TranslateProducts(locale, products...)
+
+	rows := []model.ComparisonRow{
+		comparisonRow("price", "Price", currency.Code, products, func(i int, p *model.Product) interface{} { return p.Price }),
+		comparisonRow("price_after_discount", "Price After Discount", currency.Code, products, func(i int, p *model.Product) interface{} {
+			return discounted[i]
+		}),
+		comparisonRow("promo_price", "Promo Price", currency.Code, products, func(i int, p *model.Product) interface{} {
+			if len(p.Promotions) == 0 && p.FlashSale == nil {
+				return nil
+			}
+			return p.PriceAfterDiscount
+		}),
+		comparisonRow("rating", "Rating", "", products, func(i int, p *model.Product) interface{} { return p.Rating }),
+		comparisonRow("stock", "Stock", "", products, func(i int, p *model.Product) interface{} { return p.TotalStock }),
+	}
+	rows = append(rows, variantRows(products)...)
+	rows = append(rows, specificationRows(products)...)
+
+	comparison.Rows = rows
+	for _, product := range products {
+		comparison.Products = append(comparison.Products, *product)
+	}
+	return comparison, nil
+}
+
+// variantRows adds a row per variant attribute, such as size, listing the
+// option values of each product.
+func variantRows(products []*model.Product) []model.ComparisonRow {
+	var keys []string
+	labels := make(map[string]string)
+	for _, product := range products {
+		for _, variant := range product.Variant {
+			key := strings.ToLower(strings.TrimSpace(variant.AttributeName))
+			if _, ok := labels[key]; !ok {
+				labels[key] = variant.AttributeName
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var rows []model.ComparisonRow
+	for _, key := range keys {
+		rows = append(rows, comparisonRow("variant:"+key, labels[key], "", products, func(i int, p *model.Product) interface{} {
+			for _, variant := range p.Variant {
+				if strings.ToLower(strings.TrimSpace(variant.AttributeName)) != key {
+					continue
+				}
+				options := []string{}
+				for _, option := range variant.VariantOption {
+					options = append(options, option.OptionValue)
+				}
+				sort.Strings(options)
+				return options
+			}
+			return nil
+		}))
+	}
+	return rows
+}
+
+// specificationRows adds a row per specification attribute of any of the
+// products, in the order they are first listed.
+func specificationRows(products []*model.Product) []model.ComparisonRow {
+	var attributes []model.ProductAttribute
+	seen := make(map[string]bool)
+	for _, product := range products {
+		for _, attribute := range product.Attributes {
+			if !seen[attribute.Code] {
+				seen[attribute.Code] = true
+				attributes = append(attributes, attribute)
+			}
+		}
+	}
+
+	var rows []model.ComparisonRow
+	for _, attribute := range attributes {
+		code := attribute.Code
+		rows = append(rows, comparisonRow("attribute:"+code, attribute.Name, attribute.Unit, products, func(i int, p *model.Product) interface{} {
+			for _, value := range p.Attributes {
+				if value.Code == code {
+					return value.Value
+				}
+			}
+			return nil
+		}))
+	}
+	return rows
+}
+
+func comparisonRow(key, label, unit string, products []*model.Product, value func(int, *model.Product) interface{}) model.ComparisonRow {
+	row := model.ComparisonRow{Key: key, Label: label, Unit: unit, Values: make([]interface{}, len(products))}
+	for i, product := range products {
+		row.Values[i] = value(i, product)
+		if i > 0 && !reflect.DeepEqual(row.Values[i], row.Values[0]) {
+			row.Differs = true
+		}
+	}
+	return row
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCouponNotFound    = errors.New("coupon not found")
+	ErrCouponInvalid     = errors.New("invalid coupon")
+	ErrCouponExpired     = errors.New("coupon is not valid at this time")
+	ErrCouponMinSpend    = errors.New("cart does not reach the coupon minimum spend")
+	ErrCouponNotEligible = errors.New("no item in the cart is eligible for this coupon")
+	ErrCouponEmptyCart   = errors.New("cart is empty")
+
+	// Usage limits are checked again while the order is created, so these
+	// come from the repository.
+	ErrCouponUnavailable = repository.ErrCouponUnavailable
+	ErrCouponUsageLimit  = repository.ErrCouponUsageLimit
+	ErrCouponUserLimit   = repository.ErrCouponUserLimit
+)
+
+type CouponService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+}
+
+func NewCouponService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService) CouponService {
+	return CouponService{Repo: repo, Logger: logger, Pricing: pricing}
+}
+
+func (s CouponService) GetAllCoupons(pagination model.Pagination) ([]model.Coupon, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.CouponRepository.GetAll(pagination)
+}
+
+func (s CouponService) CreateCoupon(couponInput model.CouponDTO) (model.Coupon, error) {
+	coupon, err := s.validateCoupon(couponInput)
+	if err != nil {
+		return coupon, err
+	}
+
+	existing, err := s.Repo.CouponRepository.GetByCode(coupon.Code)
+	if err != nil {
+		s.Logger.Error("error get coupon by code", zap.Error(err))
+		return coupon, err
+	}
+	if existing.ID != 0 {
+		return coupon, fmt.Errorf("%w: code %s is already used", ErrCouponInvalid, coupon.Code)
+	}
+	return s.Repo.CouponRepository.Create(coupon)
+}
+
+func (s CouponService) DeleteCoupon(id int) error {
+	existing, err := s.Repo.CouponRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get coupon by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrCouponNotFound
+	}
+	return s.Repo.CouponRepository.Delete(id)
+}
+
+// ApplyCoupon validates the code against the user's cart and attaches it.
+// An empty code removes the coupon from the cart.
+func (s CouponService) ApplyCoupon(userID string, couponInput model.ApplyCouponDTO) (*model.AppliedCoupon, error) {
+	code := strings.TrimSpace(couponInput.Code)
+	if code == "" {
+		return nil, s.RemoveCoupon(userID)
+	}
+
+	cart, err := s.Repo.CartRepository.GetByUserID(userID)
+	if err != nil {
+		s.Logger.Error("error get cart by user id", zap.Error(err))
+		return nil, err
+	}
+	if cart.ID == 0 {
+		return nil, ErrCouponEmptyCart
+	}
+
+	coupon, err := s.Repo.CouponRepository.GetByCode(code)
+	if err != nil {
+		s.Logger.Error("error get coupon by code", zap.Error(err))
+		return nil, err
+	}
+	if coupon.ID == 0 {
+		return nil, ErrCouponNotFound
+	}
+
+	items, err := s.pricedItems(cart.ID)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := s.Quote(userID, coupon, items, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Repo.CartRepository.SetCoupon(cart.ID, coupon.ID)
+	if err != nil {
+		s.Logger.Error("error set cart coupon", zap.Error(err))
+		return nil, err
+	}
+	return &model.AppliedCoupon{Code: coupon.Code, CouponType: coupon.CouponType, DiscountAmount: discount}, nil
+}
+
+func (s CouponService) RemoveCoupon(userID string) error {
+	cart, err := s.Repo.CartRepository.GetByUserID(userID)
+	if err != nil {
+		s.Logger.Error("error get cart by user id", zap.Error(err))
+		return err
+	}
+	if cart.ID == 0 {
+		return nil
+	}
+	return s.Repo.CartRepository.SetCoupon(cart.ID, 0)
+}
+
+// CartCoupon describes the coupon attached to a cart. A coupon that no
+// longer applies is still returned, with the reason in Message.
+func (s CouponService) CartCoupon(userID string, cart model.Cart, items []model.CartItem) (*model.AppliedCoupon, error) {
+	if cart.CouponID == 0 {
+		return nil, nil
+	}
+	coupon, err := s.Repo.CouponRepository.GetByID(cart.CouponID)
+	if err != nil {
+		s.Logger.Error("error get coupon by id", zap.Error(err))
+		return nil, err
+	}
+	if coupon.ID == 0 {
+		return nil, nil
+	}
+
+	applied := &model.AppliedCoupon{Code: coupon.Code, CouponType: coupon.CouponType}
+	applied.DiscountAmount, err = s.Quote(userID, coupon, items, 0)
+	if err != nil {
+		if !isCouponRuleError(err) {
+			return nil, err
+		}
+		applied.Message = err.Error()
+	}
+	return applied, nil
+}
+
+// Quote checks every coupon rule for the given priced cart lines and
+// returns the discount. Free shipping coupons are quoted against
+// shippingCost.
+func (s CouponService) Quote(userID string, coupon model.Coupon, items []model.CartItem, shippingCost model.Money) (model.Money, error) {
+	// A personal coupon does not exist for anyone else
+	if coupon.UserID != "" && coupon.UserID != userID {
+		return 0, ErrCouponNotFound
+	}
+	now := time.Now()
+	if now.Before(coupon.StartsAt) || !now.Before(coupon.EndsAt) {
+		return 0, ErrCouponExpired
+	}
+	if coupon.UsageLimit > 0 && coupon.TimesUsed >= coupon.UsageLimit {
+		return 0, ErrCouponUsageLimit
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := s.Repo.CouponRepository.CountUserRedemptions(coupon.ID, userID)
+		if err != nil {
+			s.Logger.Error("error count coupon redemptions", zap.Error(err))
+			return 0, err
+		}
+		if used >= coupon.PerUserLimit {
+			return 0, ErrCouponUserLimit
+		}
+	}
+
+	if len(items) == 0 {
+		return 0, ErrCouponEmptyCart
+	}
+	var subtotal model.Money
+	for _, item := range items {
+		subtotal += item.SubTotal - item.Discount
+	}
+	if helper.RoundPrice(subtotal) < coupon.MinSpend {
+		return 0, fmt.Errorf("%w of %s", ErrCouponMinSpend, coupon.MinSpend)
+	}
+
+	eligible := helper.CouponEligibleSubtotal(coupon, items)
+	if eligible == 0 {
+		return 0, ErrCouponNotEligible
+	}
+	return helper.CalculateCouponDiscount(coupon, eligible, shippingCost), nil
+}
+
+func (s CouponService) pricedItems(cartID int) ([]model.CartItem, error) {
+	items, err := s.Repo.CartRepository.GetItems(cartID)
+	if err != nil {
+		s.Logger.Error("error get cart items", zap.Error(err))
+		return nil, err
+	}
+	pricing, err := s.Pricing.PriceCart(items)
+	if err != nil {
+		s.Logger.Error("error price cart", zap.Error(err))
+		return nil, err
+	}
+	return pricing.Items, nil
+}
+
+func (s CouponService) validateCoupon(couponInput model.CouponDTO) (model.Coupon, error) {
+	coupon := model.Coupon{
+		Code:          strings.ToUpper(strings.TrimSpace(couponInput.Code)),
+		Description:   couponInput.Description,
+		CouponType:    couponInput.CouponType,
+		DiscountValue: couponInput.DiscountValue,
+		MaxDiscount:   couponInput.MaxDiscount,
+		MinSpend:      couponInput.MinSpend,
+		StartsAt:      couponInput.StartsAt,
+		EndsAt:        couponInput.EndsAt,
+		UsageLimit:    couponInput.UsageLimit,
+		PerUserLimit:  couponInput.PerUserLimit,
+		CategoryIDs:   couponInput.CategoryIDs,
+	}
+
+	if coupon.Code == "" {
+		return coupon, fmt.Errorf("%w: code is required", ErrCouponInvalid)
+	}
+	switch coupon.CouponType {
+	case model.DiscountTypePercentage:
+		if coupon.DiscountValue <= 0 || coupon.DiscountValue > model.NewMoney(100) {
+			return coupon, fmt.Errorf("%w: percentage discount must be between 0 and 100", ErrCouponInvalid)
+		}
+	case model.DiscountTypeFixed:
+		if coupon.DiscountValue <= 0 {
+			return coupon, fmt.Errorf("%w: fixed discount must be greater than 0", ErrCouponInvalid)
+		}
+	case model.CouponTypeFreeShipping:
+		coupon.DiscountValue = 0
+	default:
+		return coupon, fmt.Errorf("%w: coupon_type must be percentage, fixed or free_shipping", ErrCouponInvalid)
+	}
+	if coupon.MaxDiscount < 0 || coupon.MinSpend < 0 || coupon.UsageLimit < 0 || coupon.PerUserLimit < 0 {
+		return coupon, fmt.Errorf("%w: limits cannot be negative", ErrCouponInvalid)
+	}
+	if coupon.StartsAt.IsZero() || !coupon.EndsAt.After(coupon.StartsAt) {
+		return coupon, fmt.Errorf("%w: ends_at must be after starts_at", ErrCouponInvalid)
+	}
+	return coupon, nil
+}
+
+func isCouponRuleError(err error) bool {
+	for _, target := range []error{ErrCouponExpired, ErrCouponMinSpend, ErrCouponNotEligible, ErrCouponEmptyCart,
+		ErrCouponUnavailable, ErrCouponUsageLimit, ErrCouponUserLimit} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
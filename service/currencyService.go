@@ -0,0 +1,282 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrCurrencyNotFound = errors.New("currency not found")
+	ErrCurrencyInvalid  = errors.New("invalid currency")
+)
+
+type CurrencyService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+}
+
+func NewCurrencyService(repo repository.MainRepository, logger *zap.Logger) CurrencyService {
+	return CurrencyService{Repo: repo, Logger: logger}
+}
+
+func (s CurrencyService) GetCurrencies() ([]model.Currency, error) {
+	return s.Repo.CurrencyRepository.GetAll()
+}
+
+// Resolve returns the currency with the given code, or the base currency
+// when the code is empty.
+func (s CurrencyService) Resolve(code string) (model.Currency, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	var currency model.Currency
+	var err error
+	if code == "" {
+		currency, err = s.Repo.CurrencyRepository.GetBase()
+	} else {
+		currency, err = s.Repo.CurrencyRepository.GetByCode(code)
+	}
+	if err != nil {
+		s.Logger.Error("error get currency", zap.Error(err), zap.String("service", "Currency"), zap.String("function", "Resolve"))
+		return currency, err
+	}
+	if currency.Code == "" {
+		return currency, fmt.Errorf("%w: %s", ErrCurrencyNotFound, code)
+	}
+	return currency, nil
+}
+
+// SaveCurrency creates or updates one currency. The base currency keeps a
+// rate of 1.
+func (s CurrencyService) SaveCurrency(code string, currencyInput model.CurrencyDTO) (model.Currency, error) {
+	currencyInput.Code = code
+	currency, err := s.validateCurrency(currencyInput)
+	if err != nil {
+		return currency, err
+	}
+	if err := s.Repo.CurrencyRepository.Save([]model.Currency{currency}); err != nil {
+		return currency, err
+	}
+	return s.Resolve(currency.Code)
+}
+
+// ImportCurrencies reads a CSV with a header row of code, name, symbol,
+// rate, decimals and rounding_increment, in any order. Only code and rate
+// are required. Nothing is saved if any row is invalid.
+func (s CurrencyService) ImportCurrencies(file io.Reader) (int, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrCurrencyInvalid, err.Error())
+	}
+	if len(records) < 2 {
+		return 0, fmt.Errorf("%w: the file needs a header row and at least one currency", ErrCurrencyInvalid)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["code"]; !ok {
+		return 0, fmt.Errorf("%w: the header has no code column", ErrCurrencyInvalid)
+	}
+	if _, ok := columns["rate"]; !ok {
+		return 0, fmt.Errorf("%w: the header has no rate column", ErrCurrencyInvalid)
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var currencies []model.Currency
+	for line, record := range records[1:] {
+		currencyInput := model.CurrencyDTO{
+			Code:   field(record, "code"),
+			Name:   field(record, "name"),
+			Symbol: field(record, "symbol"),
+		}
+		currencyInput.Rate, err = strconv.ParseFloat(field(record, "rate"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: line %d: rate is not a number", ErrCurrencyInvalid, line+2)
+		}
+		if value := field(record, "decimals"); value != "" {
+			decimals, err := strconv.Atoi(value)
+			if err != nil {
+				return 0, fmt.Errorf("%w: line %d: decimals is not a number", ErrCurrencyInvalid, line+2)
+			}
+			currencyInput.Decimals = &decimals
+		}
+		if value := field(record, "rounding_increment"); value != "" {
+			currencyInput.RoundingIncrement, err = model.ParseMoney(value)
+			if err != nil {
+				return 0, fmt.Errorf("%w: line %d: rounding_increment is not a number", ErrCurrencyInvalid, line+2)
+			}
+		}
+
+		currency, err := s.validateCurrency(currencyInput)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		currencies = append(currencies, currency)
+	}
+
+	if err := s.Repo.CurrencyRepository.Save(currencies); err != nil {
+		return 0, err
+	}
+	return len(currencies), nil
+}
+
+func (s CurrencyService) DeleteCurrency(code string) error {
+	currency, err := s.Resolve(code)
+	if err != nil {
+		return err
+	}
+	if currency.IsBase {
+		return fmt.Errorf("%w: the base currency cannot be deleted", ErrCurrencyInvalid)
+	}
+	return s.Repo.CurrencyRepository.Delete(currency.Code)
+}
+
+// ConvertProducts converts the prices of the products for display.
+func (s CurrencyService) ConvertProducts(products []model.Product, currency model.Currency) {
+	for i := range products {
+		convertProduct(&products[i], currency)
+	}
+}
+
+func (s CurrencyService) ConvertProduct(product *model.Product, currency model.Currency) {
+	convertProduct(product, currency)
+}
+
+// ConvertCart converts every amount of a priced cart. Totals are converted
+// as a whole, like the order total at checkout.
+func (s CurrencyService) ConvertCart(cart *model.Cart, currency model.Currency) {
+	cart.Currency = currency.Code
+	if currency.IsBase {
+		return
+	}
+	cart.TotalPrice = helper.ConvertPrice(cart.TotalPrice, currency)
+	cart.SubTotal = helper.ConvertPrice(cart.SubTotal, currency)
+	cart.DiscountTotal = helper.ConvertPrice(cart.DiscountTotal, currency)
+	for i := range cart.Discounts {
+		cart.Discounts[i].Amount = helper.ConvertPrice(cart.Discounts[i].Amount, currency)
+		for j := range cart.Discounts[i].Items {
+			cart.Discounts[i].Items[j].Amount = helper.ConvertPrice(cart.Discounts[i].Items[j].Amount, currency)
+		}
+	}
+	convertCartItems(cart.Items, currency)
+	convertCartItems(cart.SavedItems, currency)
+	if cart.Coupon != nil {
+		cart.Coupon.DiscountAmount = helper.ConvertPrice(cart.Coupon.DiscountAmount, currency)
+	}
+}
+
+func convertCartItems(items []model.CartItem, currency model.Currency) {
+	for i := range items {
+		item := &items[i]
+		item.SubTotal = helper.ConvertPrice(item.SubTotal, currency)
+		item.Discount = helper.ConvertPrice(item.Discount, currency)
+		convertPromotions(item.Promotions, currency)
+		convertProduct(&item.Product, currency)
+	}
+}
+
+// ConvertCartValidation converts the validated cart and the subtotals of
+// its repriced lines.
+func (s CurrencyService) ConvertCartValidation(validation *model.CartValidation, currency model.Currency) {
+	s.ConvertCart(&validation.Cart, currency)
+	if currency.IsBase {
+		return
+	}
+	for i := range validation.Issues {
+		issue := &validation.Issues[i]
+		issue.OldSubTotal = helper.ConvertPrice(issue.OldSubTotal, currency)
+		issue.NewSubTotal = helper.ConvertPrice(issue.NewSubTotal, currency)
+	}
+}
+
+func convertProduct(product *model.Product, currency model.Currency) {
+	product.Currency = currency.Code
+	if currency.IsBase {
+		return
+	}
+	product.Price = helper.ConvertPrice(product.Price, currency)
+	product.PriceAfterDiscount = helper.ConvertPrice(product.PriceAfterDiscount, currency)
+	product.LowestPrice30d = helper.ConvertPrice(product.LowestPrice30d, currency)
+	convertPromotions(product.Promotions, currency)
+	if product.FlashSale != nil {
+		flashSale := *product.FlashSale
+		flashSale.SalePrice = helper.ConvertPrice(flashSale.SalePrice, currency)
+		product.FlashSale = &flashSale
+	}
+	for i := range product.Variant {
+		for j := range product.Variant[i].VariantOption {
+			option := &product.Variant[i].VariantOption[j]
+			option.AdditionalPrice = helper.ConvertPrice(option.AdditionalPrice, currency)
+		}
+	}
+}
+
+func convertPromotions(promotions []model.AppliedPromotion, currency model.Currency) {
+	for i := range promotions {
+		promotions[i].Amount = helper.ConvertPrice(promotions[i].Amount, currency)
+		if promotions[i].DiscountType == model.DiscountTypeFixed {
+			promotions[i].DiscountValue = helper.ConvertPrice(promotions[i].DiscountValue, currency)
+		}
+	}
+}
+
+func (s CurrencyService) validateCurrency(currencyInput model.CurrencyDTO) (model.Currency, error) {
+	currency := model.Currency{
+		Code:              strings.ToUpper(strings.TrimSpace(currencyInput.Code)),
+		Name:              strings.TrimSpace(currencyInput.Name),
+		Symbol:            strings.TrimSpace(currencyInput.Symbol),
+		Rate:              currencyInput.Rate,
+		Decimals:          2,
+		RoundingIncrement: currencyInput.RoundingIncrement,
+	}
+	if currencyInput.Decimals != nil {
+		currency.Decimals = *currencyInput.Decimals
+	}
+
+	if len(currency.Code) != 3 {
+		return currency, fmt.Errorf("%w: code must be a 3 letter ISO code", ErrCurrencyInvalid)
+	}
+	for _, r := range currency.Code {
+		if r < 'A' || r > 'Z' {
+			return currency, fmt.Errorf("%w: code must be a 3 letter ISO code", ErrCurrencyInvalid)
+		}
+	}
+	if currency.Name == "" {
+		currency.Name = currency.Code
+	}
+	if currency.Rate <= 0 {
+		return currency, fmt.Errorf("%w: rate must be greater than 0", ErrCurrencyInvalid)
+	}
+	if currency.Decimals < 0 || currency.Decimals > 4 {
+		return currency, fmt.Errorf("%w: decimals must be between 0 and 4", ErrCurrencyInvalid)
+	}
+	if currency.RoundingIncrement < 0 {
+		return currency, fmt.Errorf("%w: rounding_increment cannot be negative", ErrCurrencyInvalid)
+	}
+
+	base, err := s.Repo.CurrencyRepository.GetBase()
+	if err != nil {
+		s.Logger.Error("error get base currency", zap.Error(err), zap.String("service", "Currency"), zap.String("function", "validateCurrency"))
+		return currency, err
+	}
+	if base.Code == currency.Code && currency.Rate != 1 {
+		return currency, fmt.Errorf("%w: the base currency %s must have a rate of 1", ErrCurrencyInvalid, base.Code)
+	}
+	return currency, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/job"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrFlashSaleNotFound = errors.New("flash sale not found")
+	ErrFlashSaleInvalid  = errors.New("invalid flash sale")
+
+	// Allocation is claimed while the order is created, so these come from
+	// the repository.
+	ErrFlashSaleEnded    = repository.ErrFlashSaleEnded
+	ErrFlashSaleSoldOut  = repository.ErrFlashSaleSoldOut
+	ErrFlashSaleCapacity = repository.ErrFlashSaleCapacity
+)
+
+type FlashSaleService struct {
+	Repo         repository.MainRepository
+	Logger       *zap.Logger
+	PriceChanges job.Trigger
+}
+
+func NewFlashSaleService(repo repository.MainRepository, logger *zap.Logger, priceChanges job.Trigger) FlashSaleService {
+	return FlashSaleService{Repo: repo, Logger: logger, PriceChanges: priceChanges}
+}
+
+// GetFlashSales returns running and upcoming flash sales. The countdown is
+// the number of seconds until an upcoming sale starts or a running sale ends.
+func (s FlashSaleService) GetFlashSales() ([]model.FlashSale, error) {
+	flashSales, err := s.Repo.FlashSaleRepository.GetCurrent()
+	if err != nil {
+		s.Logger.Error("error get flash sales", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range flashSales {
+		if now.Before(flashSales[i].StartsAt) {
+			flashSales[i].SaleStatus = model.FlashSaleUpcoming
+			flashSales[i].CountdownSeconds = int64(flashSales[i].StartsAt.Sub(now).Seconds())
+		} else {
+			flashSales[i].SaleStatus = model.FlashSaleActive
+			flashSales[i].CountdownSeconds = int64(flashSales[i].EndsAt.Sub(now).Seconds())
+		}
+	}
+	return flashSales, nil
+}
+
+func (s FlashSaleService) CreateFlashSale(flashSaleInput model.FlashSaleDTO) (model.FlashSale, error) {
+	flashSale := model.FlashSale{
+		Name:     strings.TrimSpace(flashSaleInput.Name),
+		StartsAt: flashSaleInput.StartsAt.Truncate(time.Second),
+		EndsAt:   flashSaleInput.EndsAt.Truncate(time.Second),
+	}
+	if flashSale.Name == "" {
+		return flashSale, fmt.Errorf("%w: name is required", ErrFlashSaleInvalid)
+	}
+	if flashSale.StartsAt.IsZero() || !flashSale.EndsAt.After(flashSale.StartsAt) {
+		return flashSale, fmt.Errorf("%w: ends_at must be after starts_at", ErrFlashSaleInvalid)
+	}
+	if len(flashSaleInput.Items) == 0 {
+		return flashSale, fmt.Errorf("%w: at least one item is required", ErrFlashSaleInvalid)
+	}
+
+	seen := make(map[int]bool)
+	for _, itemInput := range flashSaleInput.Items {
+		if seen[itemInput.ProductID] {
+			return flashSale, fmt.Errorf("%w: product %d is listed twice", ErrFlashSaleInvalid, itemInput.ProductID)
+		}
+		seen[itemInput.ProductID] = true
+
+		product, err := s.Repo.ProductRepository.GetByID(itemInput.ProductID)
+		if err != nil {
+			s.Logger.Error("error get product by id", zap.Error(err))
+			return flashSale, err
+		}
+		if product.ID == 0 {
+			return flashSale, fmt.Errorf("%w: product %d not found", ErrFlashSaleInvalid, itemInput.ProductID)
+		}
+		if itemInput.SalePrice < 0 || itemInput.SalePrice >= product.Price {
+			return flashSale, fmt.Errorf("%w: sale_price of product %d must be below its price", ErrFlashSaleInvalid, itemInput.ProductID)
+		}
+		if itemInput.Allocation <= 0 {
+			return flashSale, fmt.Errorf("%w: allocation of product %d must be greater than 0", ErrFlashSaleInvalid, itemInput.ProductID)
+		}
+		if itemInput.PerCustomerLimit < 0 {
+			return flashSale, fmt.Errorf("%w: per_customer_limit cannot be negative", ErrFlashSaleInvalid)
+		}
+
+		flashSale.Items = append(flashSale.Items, model.FlashSaleItem{
+			ProductID:        product.ID,
+			ProductName:      product.Name,
+			Price:            product.Price,
+			SalePrice:        itemInput.SalePrice,
+			Allocation:       itemInput.Allocation,
+			PerCustomerLimit: itemInput.PerCustomerLimit,
+		})
+	}
+	flashSale, err := s.Repo.FlashSaleRepository.Create(flashSale)
+	if err != nil {
+		return flashSale, err
+	}
+	s.PriceChanges.Pull()
+	return flashSale, nil
+}
+
+func (s FlashSaleService) DeleteFlashSale(id int) error {
+	existing, err := s.Repo.FlashSaleRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get flash sale by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrFlashSaleNotFound
+	}
+	if err = s.Repo.FlashSaleRepository.Delete(id); err != nil {
+		return err
+	}
+	s.PriceChanges.Pull()
+	return nil
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var ErrLifecycleInvalid = errors.New("invalid product lifecycle")
+
+var publishStatuses = map[string]bool{
+	model.PublishStatusDraft:     true,
+	model.PublishStatusScheduled: true,
+	model.PublishStatusPublished: true,
+	model.PublishStatusArchived:  true,
+}
+
+type LifecycleService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+}
+
+func NewLifecycleService(repo repository.MainRepository, logger *zap.Logger) LifecycleService {
+	return LifecycleService{Repo: repo, Logger: logger}
+}
+
+func (s LifecycleService) GetProductLifecycles(status string, paginationInput model.Pagination) ([]model.ProductLifecycle, model.Pagination, error) {
+	if status != "" && !publishStatuses[status] {
+		return nil, paginationInput, fmt.Errorf("%w: unknown status %q", ErrLifecycleInvalid, status)
+	}
+	if paginationInput.Page == 0 {
+		paginationInput.Page = 1
+	}
+	if paginationInput.PerPage == 0 {
+		paginationInput.PerPage = 5
+	}
+	return s.Repo.LifecycleRepository.GetAll(status, paginationInput)
+}
+
+func (s LifecycleService) GetProductLifecycle(productID int) (model.ProductLifecycle, error) {
+	lifecycle, err := s.Repo.LifecycleRepository.GetByProductID(productID)
+	if err != nil {
+		return lifecycle, err
+	}
+	if lifecycle.ProductID == 0 {
+		return lifecycle, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+	return lifecycle, nil
+}
+
+// UpdateLifecycle moves a product to another publish status. Scheduling
+// needs a publish time in the future; the scheduled publishing job takes it
+// live from there.
+func (s LifecycleService) UpdateLifecycle(productID int, lifecycleInput model.LifecycleDTO) (model.ProductLifecycle, error) {
+	if !publishStatuses[lifecycleInput.PublishStatus] {
+		return model.ProductLifecycle{}, fmt.Errorf("%w: unknown status %q", ErrLifecycleInvalid, lifecycleInput.PublishStatus)
+	}
+	if lifecycleInput.PublishStatus == model.PublishStatusScheduled {
+		if lifecycleInput.PublishAt == nil || !lifecycleInput.PublishAt.After(time.Now()) {
+			return model.ProductLifecycle{}, fmt.Errorf("%w: publish_at must be in the future", ErrLifecycleInvalid)
+		}
+	} else if lifecycleInput.PublishAt != nil {
+		return model.ProductLifecycle{}, fmt.Errorf("%w: publish_at is only used when scheduling", ErrLifecycleInvalid)
+	}
+
+	lifecycle, err := s.Repo.LifecycleRepository.Update(productID, lifecycleInput.PublishStatus, lifecycleInput.PublishAt)
+	if err != nil {
+		return lifecycle, err
+	}
+	if lifecycle.ProductID == 0 {
+		return lifecycle, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+	s.Logger.Info("product lifecycle updated", zap.Int("product_id", productID), zap.String("publish_status", lifecycle.PublishStatus),
+		zap.String("Service", "Lifecycle"), zap.String("Function", "UpdateLifecycle"))
+	return lifecycle, nil
+}
+
+// PublishScheduled publishes the scheduled products that are due. It runs as
+// a background job.
+func (s LifecycleService) PublishScheduled() error {
+	published, err := s.Repo.LifecycleRepository.PublishDue()
+	if err != nil {
+		return err
+	}
+	if published > 0 {
+		s.Logger.Info("scheduled products published", zap.Int64("count", published), zap.String("Service", "Lifecycle"), zap.String("Function", "PublishScheduled"))
+	}
+	return nil
+}
@@ -1,18 +1,31 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
 	"go.uber.org/zap"
 )
 
+// ErrCartUnavailable means some cart lines can no longer be ordered; the
+// cart validation lists them.
+var ErrCartUnavailable = errors.New("cart has unavailable items")
+
 type OrderService struct {
-	Repo   repository.MainRepository
-	Logger *zap.Logger
+	Repo     repository.MainRepository
+	Logger   *zap.Logger
+	Pricing  PricingService
+	Coupon   CouponService
+	Currency CurrencyService
+	Cart     CartService
 }
 
-func NewOrderService(repo repository.MainRepository, logger *zap.Logger) OrderService {
-	return OrderService{Repo: repo, Logger: logger}
+func NewOrderService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, coupon CouponService, currency CurrencyService, cart CartService) OrderService {
+	return OrderService{Repo: repo, Logger: logger, Pricing: pricing, Coupon: coupon, Currency: currency, Cart: cart}
 }
 
 func (s *OrderService) CreateOrder(userID string, orderInput model.OrderDTO) error {
@@ -22,20 +35,71 @@ func (s *OrderService) CreateOrder(userID string, orderInput model.OrderDTO) err
 		return err
 	}
 
-	if cart.UserID != userID {
+	if cart.ID == 0 || cart.UserID != userID {
 		s.Logger.Error("user not match")
+		return errors.New("cart not found")
+	}
+
+	currency, err := s.Currency.Resolve(orderInput.Currency)
+	if err != nil {
+		return err
+	}
+
+	// revalidate and reprice every line so the order is charged with the
+	// current prices and only holds what can still be sold
+	validation, err := s.Cart.Revalidate(cart)
+	if err != nil {
+		s.Logger.Error("error validate cart", zap.Error(err))
+		return err
+	}
+	if !validation.Valid {
+		return fmt.Errorf("%w: %s", ErrCartUnavailable, cartIssueSummary(validation.Issues))
+	}
+	pricing := validation.Pricing
+	cartItems := pricing.Items
+	if len(cartItems) == 0 {
+		return errors.New("cart is empty")
+	}
+
+	var totalAmount int
+	var flashSales []model.FlashSaleClaim
+	for _, item := range cartItems {
+		totalAmount += item.Amount
+		if item.FlashSaleItemID != 0 {
+			flashSales = addFlashSaleClaim(flashSales, item.FlashSaleItemID, item.Amount)
+		}
+	}
+
+	coupon, err := s.orderCoupon(cart, orderInput.CouponCode)
+	if err != nil {
 		return err
 	}
+	var discount model.Money
+	if coupon.ID != 0 {
+		discount, err = s.Coupon.Quote(userID, coupon, cartItems, orderInput.ShippingCost)
+		if err != nil {
+			s.Logger.Error("coupon cannot be applied", zap.Error(err))
+			return err
+		}
+	}
 
+	totalPrice := helper.RoundPrice(pricing.Total + orderInput.ShippingCost - discount)
 	newOrderInput := model.Order{
-		UserID:        userID,
-		CartID:        orderInput.CartID,
-		TotalPrice:    cart.TotalPrice + orderInput.ShippingCost,
-		TotalAmount:   cart.TotalAmount,
-		AddressID:     orderInput.AddressID,
-		ShippingType:  orderInput.ShippingType,
-		ShippingCost:  orderInput.ShippingCost,
-		PaymentMethod: orderInput.PaymentMethod,
+		UserID:            userID,
+		CartID:            orderInput.CartID,
+		TotalPrice:        totalPrice,
+		Currency:          currency.Code,
+		ExchangeRate:      currency.Rate,
+		CurrencyTotal:     helper.ConvertPrice(totalPrice, currency),
+		TotalAmount:       totalAmount,
+		CouponID:          coupon.ID,
+		Discount:          discount,
+		PromotionDiscount: pricing.DiscountTotal,
+		FlashSales:        flashSales,
+		AddressID:         orderInput.AddressID,
+		ShippingType:      orderInput.ShippingType,
+		ShippingCost:      orderInput.ShippingCost,
+		PaymentMethod:     orderInput.PaymentMethod,
 	}
 
 	order, err := s.Repo.OrderRepository.Create(newOrderInput)
@@ -44,7 +108,7 @@ func (s *OrderService) CreateOrder(userID string, orderInput model.OrderDTO) err
 		return err
 	}
 
-	err = s.AddItemOrder(order)
+	err = s.AddItemOrder(order, cartItems, pricing.Discounts)
 	if err != nil {
 		s.Logger.Error("error add item order", zap.Error(err))
 		s.UpdateOrderStatus(order.ID, cart.ID, "failed")
@@ -54,18 +118,67 @@ func (s *OrderService) CreateOrder(userID string, orderInput model.OrderDTO) err
 	return nil
 }
 
-func (s *OrderService) AddItemOrder(order model.Order) error {
-	cartItem, err := s.Repo.CartRepository.GetItems(order.CartID)
+// cartIssueSummary lists the lines blocking the order, e.g.
+// "product 3: out_of_stock, product 7: unavailable".
+func cartIssueSummary(issues []model.CartIssue) string {
+	var parts []string
+	for _, issue := range issues {
+		if issue.Reason == model.CartIssuePriceChanged {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("product %d: %s", issue.ProductID, issue.Reason))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// addFlashSaleClaim merges lines of the same flash sale item, so the
+// per-customer cap is checked against the whole order.
+func addFlashSaleClaim(claims []model.FlashSaleClaim, flashSaleItemID, amount int) []model.FlashSaleClaim {
+	for i := range claims {
+		if claims[i].FlashSaleItemID == flashSaleItemID {
+			claims[i].Amount += amount
+			return claims
+		}
+	}
+	return append(claims, model.FlashSaleClaim{FlashSaleItemID: flashSaleItemID, Amount: amount})
+}
+
+// orderCoupon returns the coupon given at checkout, or else the one
+// applied to the cart.
+func (s *OrderService) orderCoupon(cart model.Cart, code string) (model.Coupon, error) {
+	var coupon model.Coupon
+	var err error
+	if code != "" {
+		coupon, err = s.Repo.CouponRepository.GetByCode(code)
+	} else if cart.CouponID != 0 {
+		coupon, err = s.Repo.CouponRepository.GetByID(cart.CouponID)
+	} else {
+		return coupon, nil
+	}
 	if err != nil {
-		s.Logger.Error("error get cart item by cart id", zap.Error(err))
-		return err
+		s.Logger.Error("error get coupon", zap.Error(err))
+		return coupon, err
 	}
-	for _, item := range cartItem {
+	if coupon.ID == 0 && code != "" {
+		return coupon, ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+// AddItemOrder copies the priced cart lines to the order, with a snapshot
+// of the promotions each line was bought with. Lines saved for later stay
+// in the cart.
+func (s *OrderService) AddItemOrder(order model.Order, cartItems []model.CartItem, discounts []model.CartDiscount) error {
+	for _, item := range cartItems {
+		if item.SavedForLater {
+			continue
+		}
 		orderItemInput := model.OrderItem{
 			OrderID:    order.ID,
 			ProductID:  item.ProductID,
 			Amount:     item.Amount,
 			SubTotal:   item.SubTotal,
+			Discount:   item.Discount,
 			CartItemID: item.ID,
 		}
 		orderItem, err := s.Repo.OrderRepository.AddOrderItem(orderItemInput)
@@ -79,10 +192,46 @@ func (s *OrderService) AddItemOrder(order model.Order) error {
 			s.Logger.Error("error add variant item to order item", zap.Error(err))
 			return err
 		}
+
+		err = s.Repo.OrderRepository.AddOrderPromotions(orderPromotions(orderItem, item, discounts))
+		if err != nil {
+			s.Logger.Error("error add order promotions", zap.Error(err))
+			return err
+		}
 	}
 	return nil
 }
 
+func orderPromotions(orderItem model.OrderItem, item model.CartItem, discounts []model.CartDiscount) []model.OrderPromotion {
+	var promotions []model.OrderPromotion
+	for _, promotion := range item.Promotions {
+		promotions = append(promotions, model.OrderPromotion{
+			OrderID:     orderItem.OrderID,
+			OrderItemID: orderItem.ID,
+			Source:      model.OrderPromotionSourcePromotion,
+			PromotionID: promotion.PromotionID,
+			Name:        promotion.Name,
+			Amount:      helper.RoundPrice(promotion.Amount.Mul(item.Amount)),
+		})
+	}
+	for _, discount := range discounts {
+		for _, line := range discount.Items {
+			if line.CartItemID != item.ID {
+				continue
+			}
+			promotions = append(promotions, model.OrderPromotion{
+				OrderID:     orderItem.OrderID,
+				OrderItemID: orderItem.ID,
+				Source:      discount.RuleType,
+				PromotionID: discount.CartPromotionID,
+				Name:        discount.Name,
+				Amount:      line.Amount,
+			})
+		}
+	}
+	return promotions
+}
+
 func (s *OrderService) AddVariantItem(orderItem model.OrderItem) error {
 	variantItems, err := s.Repo.CartRepository.GetItemVariants(orderItem.CartItemID)
 	if err != nil {
@@ -112,12 +261,35 @@ func (s *OrderService) UpdateOrderStatus(orderID, cartID int, orderStatus string
 		return err
 	}
 
+	if orderStatus == "failed" {
+		err = s.Repo.CouponRepository.ReleaseRedemption(orderID)
+		if err != nil {
+			s.Logger.Error("error release coupon redemption", zap.Error(err))
+			return err
+		}
+		err = s.Repo.FlashSaleRepository.ReleaseClaims(orderID)
+		if err != nil {
+			s.Logger.Error("error release flash sale claims", zap.Error(err))
+			return err
+		}
+	}
+
 	if orderStatus == "success" {
 		err = s.Repo.CartRepository.UpdateCartStatus(cartID)
 		if err != nil {
 			s.Logger.Error("error delete cart after success order", zap.Error(err))
 			return err
 		}
+		err = s.Repo.CartRepository.CarryOverSavedItems(cartID)
+		if err != nil {
+			s.Logger.Error("error carry over saved cart items", zap.Error(err))
+			return err
+		}
+		err = s.Repo.AbandonedCartRepository.MarkConverted(cartID, orderID)
+		if err != nil {
+			s.Logger.Error("error mark cart reminder converted", zap.Error(err))
+			return err
+		}
 	}
 	return nil
 }
@@ -141,6 +313,12 @@ func (s *OrderService) GetOrderByID(orderID int) (*model.Order, error) {
 		return nil, err
 	}
 	order.OrderItems = orderItems
+
+	order.Promotions, err = s.Repo.OrderRepository.GetOrderPromotions(order.ID)
+	if err != nil {
+		s.Logger.Error("error get order promotions", zap.Error(err))
+		return nil, err
+	}
 	return &order, nil
 }
 
@@ -178,6 +356,9 @@ func (s *OrderService) GetOrderItems(orderID int) ([]model.OrderItem, error) {
 			s.Logger.Error("error get product by id", zap.Error(err))
 			return nil, err
 		}
+		if err := s.Pricing.PriceProduct(&product); err != nil {
+			return nil, err
+		}
 		orderItem.Product = product
 		NewVariants := []model.OrderItemVariant{}
 		if product.HasVariant {
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/job"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+// PriceHistoryService records the prices customers pay. Changes is pulled
+// whenever a promotion or flash sale is created, changed or removed, so the
+// snapshot job records the change at once and reschedules.
+type PriceHistoryService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+	Changes job.Trigger
+}
+
+func NewPriceHistoryService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, changes job.Trigger) PriceHistoryService {
+	return PriceHistoryService{Repo: repo, Logger: logger, Pricing: pricing, Changes: changes}
+}
+
+func (s PriceHistoryService) GetPriceHistory(productID int, pagination model.Pagination) ([]model.PriceHistory, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "PriceHistory"), zap.String("function", "GetPriceHistory"))
+		return nil, pagination, err
+	}
+	if product.ID == 0 {
+		return nil, pagination, ErrProductNotFound
+	}
+	return s.Repo.PriceHistoryRepository.GetByProduct(productID, pagination)
+}
+
+// SnapshotPrices records the current price of every product whose price
+// changed since the last entry. Changes to the product price itself are
+// recorded by the database; this catches promotions and flash sales
+// starting and ending. It runs as a background job, on a timer and at
+// every moment NextPriceChange reports.
+func (s PriceHistoryService) SnapshotPrices() error {
+	products, err := s.Repo.ProductRepository.GetAllActive()
+	if err != nil {
+		return err
+	}
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		return err
+	}
+	latest, err := s.Repo.PriceHistoryRepository.GetLatestPrices()
+	if err != nil {
+		return err
+	}
+
+	var entries []model.PriceHistory
+	for _, product := range products {
+		if price, ok := latest[product.ID]; ok && price == product.PriceAfterDiscount {
+			continue
+		}
+		source := model.PriceSourceProduct
+		if product.FlashSale != nil {
+			source = model.PriceSourceFlashSale
+		} else if len(product.Promotions) > 0 {
+			source = model.PriceSourcePromotion
+		}
+		entries = append(entries, model.PriceHistory{
+			ProductID:  product.ID,
+			Price:      product.Price,
+			Discount:   product.Discount,
+			FinalPrice: product.PriceAfterDiscount,
+			Source:     source,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return s.Repo.PriceHistoryRepository.Record(entries)
+}
+
+// NextPriceChange returns when the next promotion or flash sale starts or
+// ends, or a zero time when none is scheduled. It is a second late, so the
+// change is in effect when the snapshot runs.
+func (s PriceHistoryService) NextPriceChange() (time.Time, error) {
+	wait, ok, err := s.Repo.PriceHistoryRepository.GetNextPriceChange()
+	if err != nil || !ok {
+		return time.Time{}, err
+	}
+	return time.Now().Add(wait + time.Second), nil
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+// PricingService is the only place product prices are calculated. Product
+// listing, cart and order creation all go through it so the price a
+// customer sees is the price they are charged.
+type PricingService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+}
+
+func NewPricingService(repo repository.MainRepository, logger *zap.Logger) PricingService {
+	return PricingService{Repo: repo, Logger: logger}
+}
+
+const lowestPriceWindow = 30 * 24 * time.Hour
+
+// offers are the promotions and flash sales running while a request is priced.
+type offers struct {
+	promotions []model.Promotion
+	flashSales map[int]model.FlashSaleItem
+}
+
+func (s PricingService) loadOffers(function string) (offers, error) {
+	var current offers
+	var err error
+	current.promotions, err = s.Repo.PromotionRepository.GetActive()
+	if err != nil {
+		s.Logger.Error("error get active promotions", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", function))
+		return current, err
+	}
+	flashItems, err := s.Repo.FlashSaleRepository.GetActiveItems()
+	if err != nil {
+		s.Logger.Error("error get active flash sale items", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", function))
+		return current, err
+	}
+	current.flashSales = make(map[int]model.FlashSaleItem, len(flashItems))
+	for _, item := range flashItems {
+		current.flashSales[item.ProductID] = item
+	}
+	return current, nil
+}
+
+// price uses the flash sale price when the product is in a running flash
+// sale with stock left and it beats the regular price.
+func (o offers) price(product model.Product, additionalPrice model.Money, amount int) model.PriceBreakdown {
+	regular := helper.CalculatePrice(product, additionalPrice, amount, o.promotions)
+	if item, ok := o.flashSales[product.ID]; ok {
+		flash := helper.CalculateFlashSalePrice(product, item, additionalPrice, amount)
+		if flash.FinalUnitPrice < regular.FinalUnitPrice {
+			return flash
+		}
+	}
+	return regular
+}
+
+func (o offers) priceProduct(product *model.Product) {
+	breakdown := o.price(*product, 0, 1)
+	product.PriceAfterDiscount = breakdown.FinalUnitPrice
+	product.Promotions = breakdown.Promotions
+	product.FlashSale = nil
+	if breakdown.FlashSaleItemID != 0 {
+		item := o.flashSales[product.ID]
+		product.FlashSale = &item
+	}
+}
+
+// PriceProducts sets the final unit price, applied promotions and flash sale
+// on each product.
+func (s PricingService) PriceProducts(products []model.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	current, err := s.loadOffers("PriceProducts")
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		current.priceProduct(&products[i])
+	}
+	return s.setLowestPrices(products)
+}
+
+// setLowestPrices sets the lowest price of the 30 days before the current
+// reduction, which has to be shown next to a discount. Without any history
+// the regular price is the reference.
+func (s PricingService) setLowestPrices(products []model.Product) error {
+	productIDs := make([]int, len(products))
+	for i, product := range products {
+		productIDs[i] = product.ID
+	}
+	prior, err := s.Repo.PriceHistoryRepository.GetPriorPrices(productIDs, lowestPriceWindow)
+	if err != nil {
+		s.Logger.Error("error get prior prices", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", "PriceProducts"))
+		return err
+	}
+	for i := range products {
+		products[i].LowestPrice30d = products[i].Price
+		if price, ok := prior[products[i].ID]; ok {
+			products[i].LowestPrice30d = price
+		}
+	}
+	return nil
+}
+
+func (s PricingService) PriceProduct(product *model.Product) error {
+	products := []model.Product{*product}
+	if err := s.PriceProducts(products); err != nil {
+		return err
+	}
+	*product = products[0]
+	return nil
+}
+
+// PriceItem prices amount units of a product with the given variant
+// additional price.
+func (s PricingService) PriceItem(product model.Product, additionalPrice model.Money, amount int) (model.PriceBreakdown, error) {
+	current, err := s.loadOffers("PriceItem")
+	if err != nil {
+		return model.PriceBreakdown{}, err
+	}
+	return current.price(product, additionalPrice, amount), nil
+}
+
+// VariantAdditionalPrice sums the current additional price of the selected
+// variant options.
+func (s PricingService) VariantAdditionalPrice(optionIDs []int) (model.Money, error) {
+	var additionalPrice model.Money
+	for _, optionID := range optionIDs {
+		option, err := s.Repo.VariantRepository.GetVariantOptionByID(optionID)
+		if err != nil {
+			s.Logger.Error("error get variant option by id", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", "VariantAdditionalPrice"))
+			return 0, err
+		}
+		additionalPrice += option.AdditionalPrice
+	}
+	return helper.RoundPrice(additionalPrice), nil
+}
+
+// PriceCartItem prices a stored cart line with the current product,
+// variant, promotion and flash sale data.
+func (s PricingService) PriceCartItem(item model.CartItem) (model.Product, model.PriceBreakdown, error) {
+	current, err := s.loadOffers("PriceCartItem")
+	if err != nil {
+		return model.Product{}, model.PriceBreakdown{}, err
+	}
+	return s.priceCartItem(item, current)
+}
+
+// PriceCart prices every cart line and evaluates the cart promotions across
+// all of them. Each line gets its share of the cart discounts in Discount.
+func (s PricingService) PriceCart(items []model.CartItem) (model.CartPricing, error) {
+	pricing := model.CartPricing{Items: make([]model.CartItem, 0, len(items))}
+	if len(items) == 0 {
+		return pricing, nil
+	}
+	current, err := s.loadOffers("PriceCart")
+	if err != nil {
+		return pricing, err
+	}
+
+	index := make(map[int]int, len(items))
+	for _, item := range items {
+		item, err = s.priceLine(item, current)
+		if err != nil {
+			return pricing, err
+		}
+		index[item.ID] = len(pricing.Items)
+		pricing.Items = append(pricing.Items, item)
+		pricing.SubTotal += item.SubTotal
+	}
+	pricing.SubTotal = helper.RoundPrice(pricing.SubTotal)
+
+	cartPromotions, err := s.Repo.CartPromotionRepository.GetActive()
+	if err != nil {
+		s.Logger.Error("error get active cart promotions", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", "PriceCart"))
+		return pricing, err
+	}
+	pricing.Discounts = helper.ApplyCartPromotions(pricing.Items, cartPromotions)
+	for _, discount := range pricing.Discounts {
+		for _, line := range discount.Items {
+			i := index[line.CartItemID]
+			pricing.Items[i].Discount = helper.RoundPrice(pricing.Items[i].Discount + line.Amount)
+		}
+		pricing.DiscountTotal += discount.Amount
+	}
+	pricing.DiscountTotal = helper.RoundPrice(pricing.DiscountTotal)
+	pricing.Total = helper.RoundPrice(pricing.SubTotal - pricing.DiscountTotal)
+	return pricing, nil
+}
+
+// PriceLines prices cart lines one by one, without the cart promotions, as
+// the saved for later list is shown.
+func (s PricingService) PriceLines(items []model.CartItem) ([]model.CartItem, error) {
+	priced := make([]model.CartItem, 0, len(items))
+	if len(items) == 0 {
+		return priced, nil
+	}
+	current, err := s.loadOffers("PriceLines")
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		item, err = s.priceLine(item, current)
+		if err != nil {
+			return nil, err
+		}
+		priced = append(priced, item)
+	}
+	return priced, nil
+}
+
+// priceLine fills in the product, subtotal and promotions of a cart line.
+func (s PricingService) priceLine(item model.CartItem, current offers) (model.CartItem, error) {
+	product, price, err := s.priceCartItem(item, current)
+	if err != nil {
+		return item, err
+	}
+	item.Product = product
+	item.SubTotal = price.SubTotal
+	item.Promotions = price.Promotions
+	item.FlashSaleItemID = price.FlashSaleItemID
+	item.Discount = 0
+	return item, nil
+}
+
+func (s PricingService) priceCartItem(item model.CartItem, current offers) (model.Product, model.PriceBreakdown, error) {
+	product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "Pricing"), zap.String("function", "PriceCartItem"))
+		return product, model.PriceBreakdown{}, err
+	}
+
+	var optionIDs []int
+	if product.HasVariant {
+		for _, variant := range item.ItemVariant {
+			if variant.OptionID.Valid {
+				optionIDs = append(optionIDs, int(variant.OptionID.Int64))
+			}
+		}
+	}
+	additionalPrice, err := s.VariantAdditionalPrice(optionIDs)
+	if err != nil {
+		return product, model.PriceBreakdown{}, err
+	}
+
+	current.priceProduct(&product)
+	return product, current.price(product, additionalPrice, item.Amount), nil
+}
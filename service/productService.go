@@ -1,19 +1,20 @@
 package service
 
 import (
-	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
 	"go.uber.org/zap"
 )
 
 type ProductService struct {
-	Repo   repository.MainRepository
-	Logger *zap.Logger
+	Repo      repository.MainRepository
+	Logger    *zap.Logger
+	Pricing   PricingService
+	Attribute AttributeService
 }
 
-func NewProductService(repo repository.MainRepository, logger *zap.Logger) ProductService {
-	return ProductService{Repo: repo, Logger: logger}
+func NewProductService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, attribute AttributeService) ProductService {
+	return ProductService{Repo: repo, Logger: logger, Pricing: pricing, Attribute: attribute}
 }
 
 func (s ProductService) GetAllProduct(productFilter model.ProductDTO, pagination model.Pagination) ([]model.Product, model.Pagination, error) {
@@ -25,7 +26,19 @@ func (s ProductService) GetAllProduct(productFilter model.ProductDTO, pagination
 		pagination.PerPage = 5
 	}
 
-	return s.Repo.ProductRepository.GetAll(productFilter, pagination)
+	var err error
+	productFilter.Attributes, err = s.Attribute.ResolveFilters(productFilter.Attributes)
+	if err != nil {
+		return nil, pagination, err
+	}
+	products, pagination, err := s.Repo.ProductRepository.GetAll(productFilter, pagination)
+	if err != nil {
+		return nil, pagination, err
+	}
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		return nil, pagination, err
+	}
+	return products, pagination, nil
 }
 
 func (s ProductService) GetProductByID(id int) (*model.Product, error) {
@@ -33,6 +46,36 @@ func (s ProductService) GetProductByID(id int) (*model.Product, error) {
 	if err != nil {
 		return nil, err
 	}
+	// Drafts, scheduled and archived products stay off the storefront
+	if product.ID == 0 || product.PublishStatus != model.PublishStatusPublished {
+		return nil, ErrProductNotFound
+	}
+	if err := s.Pricing.PriceProduct(&product); err != nil {
+		return nil, err
+	}
+
+	if product.BrandID != 0 {
+		brand, err := s.Repo.BrandRepository.GetByID(product.BrandID)
+		if err != nil {
+			s.Logger.Error("Error retrieving brand", zap.Error(err), zap.String("Service", "Product"), zap.String("Function", "GetProductByID"))
+			return nil, err
+		}
+		if brand.ID != 0 {
+			product.Brand = &brand
+		}
+	}
+
+	product.Attributes, err = s.Repo.AttributeRepository.GetProductValues(product.ID)
+	if err != nil {
+		s.Logger.Error("Error retrieving attributes", zap.Error(err), zap.String("Service", "Product"), zap.String("Function", "GetProductByID"))
+		return nil, err
+	}
+
+	product.Tags, err = s.Repo.CollectionRepository.GetProductTags(product.ID)
+	if err != nil {
+		s.Logger.Error("Error retrieving tags", zap.Error(err), zap.String("Service", "Product"), zap.String("Function", "GetProductByID"))
+		return nil, err
+	}
 
 	// get variant
 	if product.HasVariant {
@@ -49,7 +92,7 @@ func (s ProductService) GetProductByID(id int) (*model.Product, error) {
 	return &product, nil
 }
 
-func (s ProductService) GetPromoWeekly(paginationInput model.Pagination) ([]model.WeeklyPromo, model.Pagination, error) {
+func (s ProductService) GetPromoWeekly(paginationInput model.Pagination) ([]model.Product, model.Pagination, error) {
 	if paginationInput.Page == 0 {
 		paginationInput.Page = 1
 	}
@@ -58,22 +101,14 @@ func (s ProductService) GetPromoWeekly(paginationInput model.Pagination) ([]mode
 		paginationInput.PerPage = 5
 	}
 
-	weeklyPromo, pagination, err := s.Repo.ProductRepository.GetWeeklyPromo(paginationInput)
+	products, pagination, err := s.Repo.ProductRepository.GetOnPromotion(paginationInput)
 	if err != nil {
 		return nil, paginationInput, err
 	}
 
-	var newWeeklyPromos []model.WeeklyPromo
-	for _, item := range weeklyPromo {
-		product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
-		if err != nil {
-			s.Logger.Error("Error retrieving product", zap.Error(err), zap.String("Service", "Product"), zap.String("Function", "GetPromoWeekly"))
-			return nil, paginationInput, err
-		}
-		item.Product = product
-		item.PromoPrice = helper.CalculateDiscountPrice(product.PriceAfterDiscount, item.PromoDiscount)
-		newWeeklyPromos = append(newWeeklyPromos, item)
-		pagination.CountData++
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		s.Logger.Error("Error pricing products", zap.Error(err), zap.String("Service", "Product"), zap.String("Function", "GetPromoWeekly"))
+		return nil, paginationInput, err
 	}
-	return newWeeklyPromos, pagination, nil
+	return products, pagination, nil
 }
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+var ErrViewerRequired = errors.New("sign in or send a guest token to see recently viewed products")
+
+const maxGuestTokenLength = 64
+
+type ProductViewService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+	Config  util.RecommendationConfig
+}
+
+func NewProductViewService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, config util.RecommendationConfig) ProductViewService {
+	return ProductViewService{Repo: repo, Logger: logger, Pricing: pricing, Config: config}
+}
+
+// RecordView stores a view of the product for the user, or for the guest
+// token when the request is not signed in. Tokens that are too long are
+// dropped and the view is kept as anonymous.
+func (s ProductViewService) RecordView(productID int, userID, guestToken string) error {
+	if userID != "" || len(guestToken) > maxGuestTokenLength {
+		guestToken = ""
+	}
+	return s.Repo.ProductViewRepository.Record(productID, userID, guestToken)
+}
+
+// GetRecentlyViewed returns the latest distinct products the user or guest
+// looked at, capped at the configured limit.
+func (s ProductViewService) GetRecentlyViewed(userID, guestToken string) ([]model.RecentlyViewed, error) {
+	if userID == "" && (guestToken == "" || len(guestToken) > maxGuestTokenLength) {
+		return nil, ErrViewerRequired
+	}
+	limit := s.Config.RecentlyViewedLimit
+	if limit <= 0 {
+		limit = 20
+	}
+
+	views, err := s.Repo.ProductViewRepository.GetRecentlyViewed(userID, guestToken, limit)
+	if err != nil {
+		return nil, err
+	}
+	products := make([]model.Product, len(views))
+	for i, view := range views {
+		products[i] = view.Product
+	}
+	if err := s.loadProducts(products, "GetRecentlyViewed"); err != nil {
+		return nil, err
+	}
+	for i := range views {
+		views[i].Product = products[i]
+	}
+	return views, nil
+}
+
+// GetTrending ranks products by views over the configured rolling window.
+func (s ProductViewService) GetTrending(pagination model.Pagination) ([]model.TrendingProduct, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+
+	window := s.Config.TrendingWindow
+	if window <= 0 {
+		window = 7 * 24 * time.Hour
+	}
+	trending, pagination, err := s.Repo.ProductViewRepository.GetTrending(time.Now().Add(-window), pagination)
+	if err != nil {
+		return nil, pagination, err
+	}
+	products := make([]model.Product, len(trending))
+	for i, item := range trending {
+		products[i] = item.Product
+	}
+	if err := s.loadProducts(products, "GetTrending"); err != nil {
+		return nil, pagination, err
+	}
+	for i := range trending {
+		trending[i].Product = products[i]
+	}
+	return trending, pagination, nil
+}
+
+// loadProducts replaces each product, which only has its ID set, with the
+// full priced product.
+func (s ProductViewService) loadProducts(products []model.Product, function string) error {
+	for i, product := range products {
+		full, err := s.Repo.ProductRepository.GetByID(product.ID)
+		if err != nil {
+			s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "ProductView"), zap.String("function", function))
+			return err
+		}
+		products[i] = full
+	}
+	return s.Pricing.PriceProducts(products)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/job"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPromotionNotFound = errors.New("promotion not found")
+	ErrPromotionInvalid  = errors.New("invalid promotion")
+)
+
+type PromotionService struct {
+	Repo         repository.MainRepository
+	Logger       *zap.Logger
+	PriceChanges job.Trigger
+}
+
+func NewPromotionService(repo repository.MainRepository, logger *zap.Logger, priceChanges job.Trigger) PromotionService {
+	return PromotionService{Repo: repo, Logger: logger, PriceChanges: priceChanges}
+}
+
+func (s *PromotionService) GetAllPromotions(pagination model.Pagination) ([]model.Promotion, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.PromotionRepository.GetAll(pagination)
+}
+
+func (s *PromotionService) CreatePromotion(promotionInput model.PromotionDTO) (model.Promotion, error) {
+	promotion, err := s.validatePromotion(promotionInput)
+	if err != nil {
+		return promotion, err
+	}
+	promotion, err = s.Repo.PromotionRepository.Create(promotion)
+	if err != nil {
+		return promotion, err
+	}
+	s.PriceChanges.Pull()
+	return promotion, nil
+}
+
+func (s *PromotionService) UpdatePromotion(id int, promotionInput model.PromotionDTO) error {
+	existing, err := s.Repo.PromotionRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get promotion by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrPromotionNotFound
+	}
+
+	promotion, err := s.validatePromotion(promotionInput)
+	if err != nil {
+		return err
+	}
+	promotion.ID = id
+	if err = s.Repo.PromotionRepository.Update(promotion); err != nil {
+		return err
+	}
+	s.PriceChanges.Pull()
+	return nil
+}
+
+func (s *PromotionService) DeletePromotion(id int) error {
+	existing, err := s.Repo.PromotionRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get promotion by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrPromotionNotFound
+	}
+	if err = s.Repo.PromotionRepository.Delete(id); err != nil {
+		return err
+	}
+	s.PriceChanges.Pull()
+	return nil
+}
+
+func (s *PromotionService) validatePromotion(promotionInput model.PromotionDTO) (model.Promotion, error) {
+	promotion := model.Promotion{
+		Name:          strings.TrimSpace(promotionInput.Name),
+		Description:   promotionInput.Description,
+		TargetType:    promotionInput.TargetType,
+		TargetID:      promotionInput.TargetID,
+		DiscountType:  promotionInput.DiscountType,
+		DiscountValue: promotionInput.DiscountValue,
+		Priority:      promotionInput.Priority,
+		Stackable:     promotionInput.Stackable,
+		StartsAt:      promotionInput.StartsAt,
+		EndsAt:        promotionInput.EndsAt,
+	}
+
+	if promotion.Name == "" {
+		return promotion, fmt.Errorf("%w: name is required", ErrPromotionInvalid)
+	}
+	switch promotion.TargetType {
+	case "", model.PromotionTargetAll:
+		promotion.TargetType = model.PromotionTargetAll
+		promotion.TargetID = 0
+	case model.PromotionTargetCategory, model.PromotionTargetProduct:
+		if promotion.TargetID <= 0 {
+			return promotion, fmt.Errorf("%w: target_id is required for category and product promotions", ErrPromotionInvalid)
+		}
+	default:
+		return promotion, fmt.Errorf("%w: target_type must be all, category or product", ErrPromotionInvalid)
+	}
+	switch promotion.DiscountType {
+	case model.DiscountTypePercentage:
+		if promotion.DiscountValue <= 0 || promotion.DiscountValue > model.NewMoney(100) {
+			return promotion, fmt.Errorf("%w: percentage discount must be between 0 and 100", ErrPromotionInvalid)
+		}
+	case model.DiscountTypeFixed:
+		if promotion.DiscountValue <= 0 {
+			return promotion, fmt.Errorf("%w: fixed discount must be greater than 0", ErrPromotionInvalid)
+		}
+	default:
+		return promotion, fmt.Errorf("%w: discount_type must be percentage or fixed", ErrPromotionInvalid)
+	}
+	if promotion.StartsAt.IsZero() || !promotion.EndsAt.After(promotion.StartsAt) {
+		return promotion, fmt.Errorf("%w: ends_at must be after starts_at", ErrPromotionInvalid)
+	}
+	return promotion, nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQuestionNotFound      = errors.New("question not found")
+	ErrAnswerNotFound        = errors.New("answer not found")
+	ErrQuestionEmpty         = errors.New("text is required")
+	ErrQuestionTooLong       = errors.New("text is longer than 1000 characters")
+	ErrQuestionBannedWord    = errors.New("text contains a banned word")
+	ErrAnswerNotAllowed      = errors.New("only admins and customers who bought the product can answer")
+	ErrQuestionInvalidStatus = errors.New("moderation status must be approved or rejected")
+	ErrQuestionAlreadyVoted  = errors.New("already upvoted by this user")
+	ErrQuestionOwnPost       = errors.New("users cannot upvote their own question or answer")
+)
+
+const maxQuestionLength = 1000
+
+// QuestionService handles product questions and answers. They share the
+// banned words of review moderation.
+type QuestionService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+	Config util.ReviewConfig
+}
+
+func NewQuestionService(repo repository.MainRepository, logger *zap.Logger, config util.ReviewConfig) QuestionService {
+	return QuestionService{Repo: repo, Logger: logger, Config: config}
+}
+
+func (s *QuestionService) AskQuestion(userID string, productID int, questionInput model.QuestionDTO) (model.Question, error) {
+	text, err := s.checkText(questionInput.Question)
+	if err != nil {
+		return model.Question{}, err
+	}
+
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "Question"), zap.String("function", "AskQuestion"))
+		return model.Question{}, err
+	}
+	if product.ID == 0 || product.PublishStatus != model.PublishStatusPublished {
+		return model.Question{}, ErrProductNotFound
+	}
+
+	newQuestion := model.Question{
+		ProductID: productID,
+		UserID:    userID,
+		Question:  text,
+	}
+	return s.Repo.QuestionRepository.CreateQuestion(newQuestion)
+}
+
+// GetProductQuestions lists the approved questions of a product with their
+// approved answers.
+func (s *QuestionService) GetProductQuestions(productID int, sortBy string, pagination model.Pagination) ([]model.Question, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	if sortBy != model.QuestionSortUpvotes {
+		sortBy = model.QuestionSortRecent
+	}
+
+	questionFilter := model.QuestionFilter{
+		ProductID:        productID,
+		ModerationStatus: model.ReviewStatusApproved,
+		SortBy:           sortBy,
+	}
+	questions, pagination, err := s.Repo.QuestionRepository.GetQuestions(questionFilter, pagination)
+	if err != nil || len(questions) == 0 {
+		return questions, pagination, err
+	}
+
+	questionIDs := make([]int, len(questions))
+	for i, question := range questions {
+		questionIDs[i] = question.ID
+	}
+	answers, err := s.Repo.QuestionRepository.GetApprovedAnswers(questionIDs)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	// moderation details are only meant for admins
+	index := make(map[int]int, len(questions))
+	for i := range questions {
+		questions[i].ModerationStatus = ""
+		questions[i].ModerationNote = ""
+		index[questions[i].ID] = i
+	}
+	for _, answer := range answers {
+		i := index[answer.QuestionID]
+		questions[i].Answers = append(questions[i].Answers, answer)
+	}
+	return questions, pagination, nil
+}
+
+// AnswerQuestion answers an approved question. Answers by admins are
+// published right away; those by customers who bought the product wait for
+// moderation.
+func (s *QuestionService) AnswerQuestion(user model.User, questionID int, answerInput model.AnswerDTO) (model.Answer, error) {
+	text, err := s.checkText(answerInput.Answer)
+	if err != nil {
+		return model.Answer{}, err
+	}
+
+	question, err := s.getApprovedQuestion(questionID)
+	if err != nil {
+		return model.Answer{}, err
+	}
+
+	newAnswer := model.Answer{
+		QuestionID:       question.ID,
+		UserID:           user.ID,
+		Answer:           text,
+		IsAdmin:          user.Role == model.RoleAdmin,
+		ModerationStatus: model.ReviewStatusPending,
+	}
+	if newAnswer.IsAdmin {
+		newAnswer.ModerationStatus = model.ReviewStatusApproved
+	} else {
+		newAnswer.IsVerifiedBuyer, err = s.Repo.OrderRepository.HasPurchased(user.ID, question.ProductID)
+		if err != nil {
+			s.Logger.Error("error check purchase", zap.Error(err), zap.String("service", "Question"), zap.String("function", "AnswerQuestion"))
+			return model.Answer{}, err
+		}
+		if !newAnswer.IsVerifiedBuyer {
+			return model.Answer{}, ErrAnswerNotAllowed
+		}
+	}
+	return s.Repo.QuestionRepository.CreateAnswer(newAnswer)
+}
+
+func (s *QuestionService) UpvoteQuestion(userID string, questionID int) error {
+	question, err := s.getApprovedQuestion(questionID)
+	if err != nil {
+		return err
+	}
+	if question.UserID == userID {
+		return ErrQuestionOwnPost
+	}
+	return s.addUpvote(model.QuestionVoteQuestion, question.ID, userID)
+}
+
+func (s *QuestionService) UpvoteAnswer(userID string, answerID int) error {
+	answer, err := s.Repo.QuestionRepository.GetAnswerByID(answerID)
+	if err != nil {
+		s.Logger.Error("error get answer by id", zap.Error(err), zap.String("service", "Question"), zap.String("function", "UpvoteAnswer"))
+		return err
+	}
+	if answer.ID == 0 || answer.ModerationStatus != model.ReviewStatusApproved {
+		return ErrAnswerNotFound
+	}
+	if answer.UserID == userID {
+		return ErrQuestionOwnPost
+	}
+	return s.addUpvote(model.QuestionVoteAnswer, answer.ID, userID)
+}
+
+func (s *QuestionService) GetQuestionQueue(moderationStatus string, pagination model.Pagination) ([]model.Question, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	if moderationStatus == "" {
+		moderationStatus = model.ReviewStatusPending
+	}
+
+	questionFilter := model.QuestionFilter{
+		ModerationStatus: moderationStatus,
+		SortBy:           model.QuestionSortRecent,
+	}
+	return s.Repo.QuestionRepository.GetQuestions(questionFilter, pagination)
+}
+
+func (s *QuestionService) GetAnswerQueue(moderationStatus string, pagination model.Pagination) ([]model.Answer, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	if moderationStatus == "" {
+		moderationStatus = model.ReviewStatusPending
+	}
+	return s.Repo.QuestionRepository.GetAnswers(moderationStatus, pagination)
+}
+
+func (s *QuestionService) ModerateQuestion(questionID int, moderationInput model.QuestionModerationDTO) error {
+	if !validModerationStatus(moderationInput.ModerationStatus) {
+		return ErrQuestionInvalidStatus
+	}
+
+	question, err := s.Repo.QuestionRepository.GetQuestionByID(questionID)
+	if err != nil {
+		s.Logger.Error("error get question by id", zap.Error(err), zap.String("service", "Question"), zap.String("function", "ModerateQuestion"))
+		return err
+	}
+	if question.ID == 0 {
+		return ErrQuestionNotFound
+	}
+	return s.Repo.QuestionRepository.UpdateQuestionStatus(questionID, moderationInput.ModerationStatus, moderationInput.Note)
+}
+
+func (s *QuestionService) ModerateAnswer(answerID int, moderationInput model.QuestionModerationDTO) error {
+	if !validModerationStatus(moderationInput.ModerationStatus) {
+		return ErrQuestionInvalidStatus
+	}
+
+	answer, err := s.Repo.QuestionRepository.GetAnswerByID(answerID)
+	if err != nil {
+		s.Logger.Error("error get answer by id", zap.Error(err), zap.String("service", "Question"), zap.String("function", "ModerateAnswer"))
+		return err
+	}
+	if answer.ID == 0 {
+		return ErrAnswerNotFound
+	}
+	return s.Repo.QuestionRepository.UpdateAnswerStatus(answerID, moderationInput.ModerationStatus, moderationInput.Note)
+}
+
+func (s *QuestionService) getApprovedQuestion(questionID int) (model.Question, error) {
+	question, err := s.Repo.QuestionRepository.GetQuestionByID(questionID)
+	if err != nil {
+		s.Logger.Error("error get question by id", zap.Error(err), zap.String("service", "Question"), zap.String("function", "getApprovedQuestion"))
+		return question, err
+	}
+	if question.ID == 0 || question.ModerationStatus != model.ReviewStatusApproved {
+		return question, ErrQuestionNotFound
+	}
+	return question, nil
+}
+
+func (s *QuestionService) addUpvote(targetType string, targetID int, userID string) error {
+	voted, err := s.Repo.QuestionRepository.AddUpvote(targetType, targetID, userID)
+	if err != nil {
+		s.Logger.Error("error add upvote", zap.Error(err), zap.String("service", "Question"), zap.String("function", "addUpvote"))
+		return err
+	}
+	if !voted {
+		return ErrQuestionAlreadyVoted
+	}
+	return nil
+}
+
+// checkText trims a question or answer and runs it through the keyword
+// filter.
+func (s *QuestionService) checkText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return text, ErrQuestionEmpty
+	}
+	if len([]rune(text)) > maxQuestionLength {
+		return text, ErrQuestionTooLong
+	}
+	if word, found := helper.FindBannedWord(text, helper.ParseBannedWords(s.Config.BannedWords)); found {
+		s.Logger.Info("question rejected by keyword filter", zap.String("word", word), zap.String("service", "Question"), zap.String("function", "checkText"))
+		return text, ErrQuestionBannedWord
+	}
+	return text, nil
+}
+
+func validModerationStatus(moderationStatus string) bool {
+	return moderationStatus == model.ReviewStatusApproved || moderationStatus == model.ReviewStatusRejected
+}
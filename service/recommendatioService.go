@@ -1,18 +1,33 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
 	"go.uber.org/zap"
 )
 
+var (
+	ErrProductNotFound        = errors.New("product not found")
+	ErrRecommendationNotFound = errors.New("recommendation not found")
+	ErrRecommendationInvalid  = errors.New("invalid recommendation")
+)
+
+const defaultAssociationLimit = 5
+
 type RecommendationService struct {
-	Repo   repository.MainRepository
-	Logger *zap.Logger
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
+	Config  util.RecommendationConfig
 }
 
-func NewRecommendationService(repo repository.MainRepository, logger *zap.Logger) RecommendationService {
-	return RecommendationService{Repo: repo, Logger: logger}
+func NewRecommendationService(repo repository.MainRepository, logger *zap.Logger, pricing PricingService, config util.RecommendationConfig) RecommendationService {
+	return RecommendationService{Repo: repo, Logger: logger, Pricing: pricing, Config: config}
 }
 
 func (s *RecommendationService) GetProductRecommendations(recommedFilter model.RecommendationDTO, pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
@@ -26,3 +41,217 @@ func (s *RecommendationService) GetProductRecommendations(recommedFilter model.R
 
 	return s.Repo.RecommendationRepository.GetRecommendations(recommedFilter, pagination)
 }
+
+// GetAllRecommendations lists every banner and recommendation for the
+// admin, including scheduled and ended ones.
+func (s RecommendationService) GetAllRecommendations(pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	return s.Repo.RecommendationRepository.GetAll(pagination)
+}
+
+func (s RecommendationService) CreateRecommendation(recommendationInput model.RecommendationDTO) (model.Recommendation, error) {
+	recommendation, err := s.validateRecommendation(recommendationInput)
+	if err != nil {
+		return recommendation, err
+	}
+	return s.Repo.RecommendationRepository.Create(recommendation)
+}
+
+func (s RecommendationService) UpdateRecommendation(id int, recommendationInput model.RecommendationDTO) error {
+	existing, err := s.Repo.RecommendationRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get recommendation by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrRecommendationNotFound
+	}
+
+	recommendation, err := s.validateRecommendation(recommendationInput)
+	if err != nil {
+		return err
+	}
+	recommendation.ID = id
+	return s.Repo.RecommendationRepository.Update(recommendation)
+}
+
+func (s RecommendationService) DeleteRecommendation(id int) error {
+	existing, err := s.Repo.RecommendationRepository.GetByID(id)
+	if err != nil {
+		s.Logger.Error("error get recommendation by id", zap.Error(err))
+		return err
+	}
+	if existing.ID == 0 {
+		return ErrRecommendationNotFound
+	}
+	return s.Repo.RecommendationRepository.Delete(id)
+}
+
+// GetPersonalisedRecommendations returns the "recommended for you" feed. A
+// user with no orders, cart or wishlist yet gets the curated recommendations,
+// filtered the same way, on every page.
+func (s *RecommendationService) GetPersonalisedRecommendations(userID string, pagination model.Pagination) ([]model.Recommendation, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+
+	recommendations, pagination, err := s.Repo.RecommendationRepository.GetPersonalised(userID, pagination)
+	if err != nil {
+		return nil, pagination, err
+	}
+	if len(recommendations) > 0 {
+		return recommendations, pagination, nil
+	}
+	// Past the last page the count is unknown; see whether there is a feed
+	// at all before falling back.
+	if pagination.Page > 1 {
+		first, _, err := s.Repo.RecommendationRepository.GetPersonalised(userID, model.Pagination{Page: 1, PerPage: 1})
+		if err != nil {
+			return nil, pagination, err
+		}
+		if len(first) > 0 {
+			return recommendations, pagination, nil
+		}
+	}
+	return s.Repo.RecommendationRepository.GetCuratedForUser(userID, pagination)
+}
+
+// RefreshAssociations recomputes the frequently bought together table. It
+// runs as a background job.
+func (s RecommendationService) RefreshAssociations() error {
+	minSupport := s.Config.MinSupport
+	if minSupport < 1 {
+		minSupport = 1
+	}
+	return s.Repo.RecommendationRepository.RefreshAssociations(minSupport)
+}
+
+// GetFrequentlyBoughtTogether returns the products most often ordered
+// together with the given product.
+func (s RecommendationService) GetFrequentlyBoughtTogether(productID, limit int) ([]model.ProductAssociation, error) {
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "Recommendation"), zap.String("function", "GetFrequentlyBoughtTogether"))
+		return nil, err
+	}
+	if product.ID == 0 {
+		return nil, ErrProductNotFound
+	}
+
+	if limit <= 0 {
+		limit = defaultAssociationLimit
+	}
+	associations, err := s.Repo.RecommendationRepository.GetAssociations(productID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadAssociatedProducts(associations, "GetFrequentlyBoughtTogether")
+}
+
+// CompleteYourOrder suggests products bought together with what is already
+// in the user's cart.
+func (s RecommendationService) CompleteYourOrder(userID string, limit int) ([]model.ProductAssociation, error) {
+	cart, err := s.Repo.CartRepository.GetByUserID(userID)
+	if err != nil {
+		s.Logger.Error("error get cart by user id", zap.Error(err), zap.String("service", "Recommendation"), zap.String("function", "CompleteYourOrder"))
+		return nil, err
+	}
+	if cart.ID == 0 {
+		return []model.ProductAssociation{}, nil
+	}
+	items, err := s.Repo.CartRepository.GetItems(cart.ID)
+	if err != nil {
+		s.Logger.Error("error get cart items", zap.Error(err), zap.String("service", "Recommendation"), zap.String("function", "CompleteYourOrder"))
+		return nil, err
+	}
+	if len(items) == 0 {
+		return []model.ProductAssociation{}, nil
+	}
+
+	productIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	if limit <= 0 {
+		limit = defaultAssociationLimit
+	}
+	associations, err := s.Repo.RecommendationRepository.GetCartAssociations(productIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	return s.loadAssociatedProducts(associations, "CompleteYourOrder")
+}
+
+func (s RecommendationService) loadAssociatedProducts(associations []model.ProductAssociation, function string) ([]model.ProductAssociation, error) {
+	products := make([]model.Product, len(associations))
+	for i, association := range associations {
+		product, err := s.Repo.ProductRepository.GetByID(association.Product.ID)
+		if err != nil {
+			s.Logger.Error("error get product by id", zap.Error(err), zap.String("service", "Recommendation"), zap.String("function", function))
+			return nil, err
+		}
+		products[i] = product
+	}
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.ProductAssociation, 0, len(associations))
+	for i, association := range associations {
+		association.Product = products[i]
+		result = append(result, association)
+	}
+	return result, nil
+}
+
+func (s RecommendationService) validateRecommendation(recommendationInput model.RecommendationDTO) (model.Recommendation, error) {
+	recommendation := model.Recommendation{
+		Product:       model.Product{ID: recommendationInput.ProductID},
+		IsRecommended: recommendationInput.IsRecommended,
+		SetInBanner:   recommendationInput.SetInBanner,
+		Title:         strings.TrimSpace(recommendationInput.Title),
+		Subtitle:      strings.TrimSpace(recommendationInput.Subtitle),
+		PhotoUrl:      strings.TrimSpace(recommendationInput.PhotoUrl),
+		PathUrl:       strings.TrimSpace(recommendationInput.PathUrl),
+		DisplayOrder:  recommendationInput.DisplayOrder,
+		StartsAt:      recommendationInput.StartsAt,
+		EndsAt:        recommendationInput.EndsAt,
+	}
+
+	if recommendation.Title == "" {
+		return recommendation, fmt.Errorf("%w: title is required", ErrRecommendationInvalid)
+	}
+	if !recommendation.IsRecommended && !recommendation.SetInBanner {
+		return recommendation, fmt.Errorf("%w: set is_recommended, set_in_banner or both", ErrRecommendationInvalid)
+	}
+	if recommendation.PathUrl != "" && !strings.HasPrefix(recommendation.PathUrl, "/") &&
+		!strings.HasPrefix(recommendation.PathUrl, "http://") && !strings.HasPrefix(recommendation.PathUrl, "https://") {
+		return recommendation, fmt.Errorf("%w: path_url must be a path or an http(s) link", ErrRecommendationInvalid)
+	}
+	if recommendation.StartsAt != nil && recommendation.EndsAt != nil && !recommendation.EndsAt.After(*recommendation.StartsAt) {
+		return recommendation, fmt.Errorf("%w: ends_at must be after starts_at", ErrRecommendationInvalid)
+	}
+
+	if recommendation.Product.ID <= 0 {
+		return recommendation, fmt.Errorf("%w: product_id is required", ErrRecommendationInvalid)
+	}
+	product, err := s.Repo.ProductRepository.GetByID(recommendation.Product.ID)
+	if err != nil {
+		s.Logger.Error("error get product by id", zap.Error(err))
+		return recommendation, err
+	}
+	if product.ID == 0 {
+		return recommendation, fmt.Errorf("%w: product %d does not exist", ErrRecommendationInvalid, recommendation.Product.ID)
+	}
+	recommendation.Product = product
+	return recommendation, nil
+}
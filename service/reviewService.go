@@ -0,0 +1,201 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrReviewNotFound       = errors.New("review not found")
+	ErrReviewInvalidRating  = errors.New("rating must be between 1 and 5")
+	ErrReviewNotAllowed     = errors.New("only delivered purchases can be reviewed")
+	ErrReviewAlreadyExists  = errors.New("order item has already been reviewed")
+	ErrReviewBannedWord     = errors.New("review contains a banned word")
+	ErrReviewInvalidStatus  = errors.New("review status must be approved or rejected")
+	ErrReviewAlreadyVoted   = errors.New("review has already been voted by this user")
+	ErrReviewOwnReview      = errors.New("users cannot vote on their own review")
+	ErrReviewReportNoReason = errors.New("report reason is required")
+)
+
+type ReviewService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+	Config util.ReviewConfig
+}
+
+func NewReviewService(repo repository.MainRepository, logger *zap.Logger, config util.ReviewConfig) ReviewService {
+	return ReviewService{Repo: repo, Logger: logger, Config: config}
+}
+
+func (s *ReviewService) SubmitReview(userID string, reviewInput model.ReviewDTO) (model.Review, error) {
+	if reviewInput.Rating < 1 || reviewInput.Rating > 5 {
+		return model.Review{}, ErrReviewInvalidRating
+	}
+
+	reviewInput.Comment = strings.TrimSpace(reviewInput.Comment)
+	if word, found := helper.FindBannedWord(reviewInput.Comment, helper.ParseBannedWords(s.Config.BannedWords)); found {
+		s.Logger.Info("review rejected by keyword filter", zap.String("word", word), zap.String("service", "Review"), zap.String("function", "SubmitReview"))
+		return model.Review{}, ErrReviewBannedWord
+	}
+
+	orderItem, err := s.Repo.OrderRepository.GetOrderItemByID(reviewInput.OrderItemID)
+	if err != nil {
+		s.Logger.Error("error get order item by id", zap.Error(err))
+		return model.Review{}, err
+	}
+	if orderItem.ID == 0 {
+		return model.Review{}, ErrReviewNotAllowed
+	}
+
+	order, err := s.Repo.OrderRepository.GetByID(orderItem.OrderID)
+	if err != nil {
+		s.Logger.Error("error get order by id", zap.Error(err))
+		return model.Review{}, err
+	}
+	if order.UserID != userID || order.OrderStatus != "success" {
+		return model.Review{}, ErrReviewNotAllowed
+	}
+
+	existing, err := s.Repo.ReviewRepository.GetByOrderItemID(orderItem.ID)
+	if err != nil {
+		s.Logger.Error("error get review by order item id", zap.Error(err))
+		return model.Review{}, err
+	}
+	if existing.ID != 0 {
+		return model.Review{}, ErrReviewAlreadyExists
+	}
+
+	newReview := model.Review{
+		OrderItemID: orderItem.ID,
+		ProductID:   orderItem.ProductID,
+		UserID:      userID,
+		Rating:      reviewInput.Rating,
+		Comment:     reviewInput.Comment,
+	}
+	return s.Repo.ReviewRepository.Create(newReview)
+}
+
+func (s *ReviewService) GetProductReviews(productID int, sortBy string, pagination model.Pagination) ([]model.Review, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	if sortBy != model.ReviewSortHelpful {
+		sortBy = model.ReviewSortRecent
+	}
+
+	reviewFilter := model.ReviewFilter{
+		ProductID:    productID,
+		ReviewStatus: model.ReviewStatusApproved,
+		SortBy:       sortBy,
+	}
+	reviews, pagination, err := s.Repo.ReviewRepository.GetAll(reviewFilter, pagination)
+	if err != nil {
+		return nil, pagination, err
+	}
+
+	// moderation details are only meant for admins
+	for i := range reviews {
+		reviews[i].ReviewStatus = ""
+		reviews[i].ModerationNote = ""
+		reviews[i].ReportCount = 0
+	}
+	return reviews, pagination, nil
+}
+
+func (s *ReviewService) GetModerationQueue(reviewStatus string, pagination model.Pagination) ([]model.Review, model.Pagination, error) {
+	if pagination.Page == 0 {
+		pagination.Page = 1
+	}
+	if pagination.PerPage == 0 {
+		pagination.PerPage = 5
+	}
+	if reviewStatus == "" {
+		reviewStatus = model.ReviewStatusPending
+	}
+
+	reviewFilter := model.ReviewFilter{
+		ReviewStatus: reviewStatus,
+		SortBy:       model.ReviewSortRecent,
+	}
+	return s.Repo.ReviewRepository.GetAll(reviewFilter, pagination)
+}
+
+func (s *ReviewService) ModerateReview(reviewID int, moderationInput model.ReviewModerationDTO) error {
+	if moderationInput.ReviewStatus != model.ReviewStatusApproved && moderationInput.ReviewStatus != model.ReviewStatusRejected {
+		return ErrReviewInvalidStatus
+	}
+
+	review, err := s.Repo.ReviewRepository.GetByID(reviewID)
+	if err != nil {
+		s.Logger.Error("error get review by id", zap.Error(err))
+		return err
+	}
+	if review.ID == 0 {
+		return ErrReviewNotFound
+	}
+
+	return s.Repo.ReviewRepository.UpdateStatus(reviewID, moderationInput.ReviewStatus, moderationInput.Note)
+}
+
+func (s *ReviewService) MarkHelpful(userID string, reviewID int) error {
+	review, err := s.getVotableReview(userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	voted, err := s.Repo.ReviewRepository.AddHelpfulVote(review.ID, userID)
+	if err != nil {
+		s.Logger.Error("error add helpful vote", zap.Error(err))
+		return err
+	}
+	if !voted {
+		return ErrReviewAlreadyVoted
+	}
+	return nil
+}
+
+func (s *ReviewService) ReportReview(userID string, reviewID int, reportInput model.ReviewReportDTO) error {
+	reportInput.Reason = strings.TrimSpace(reportInput.Reason)
+	if reportInput.Reason == "" {
+		return ErrReviewReportNoReason
+	}
+
+	review, err := s.getVotableReview(userID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	reported, err := s.Repo.ReviewRepository.AddReport(review.ID, userID, reportInput.Reason, s.Config.ReportThreshold)
+	if err != nil {
+		s.Logger.Error("error add review report", zap.Error(err))
+		return err
+	}
+	if !reported {
+		return ErrReviewAlreadyVoted
+	}
+	return nil
+}
+
+func (s *ReviewService) getVotableReview(userID string, reviewID int) (model.Review, error) {
+	review, err := s.Repo.ReviewRepository.GetByID(reviewID)
+	if err != nil {
+		s.Logger.Error("error get review by id", zap.Error(err))
+		return review, err
+	}
+	if review.ID == 0 || review.ReviewStatus != model.ReviewStatusApproved {
+		return review, ErrReviewNotFound
+	}
+	if review.UserID == userID {
+		return review, ErrReviewOwnReview
+	}
+	return review, nil
+}
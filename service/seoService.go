@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/helper"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"go.uber.org/zap"
+)
+
+var ErrSEOInvalid = errors.New("invalid seo")
+
+const maxMetaTitleLength = 255
+
+type SEOService struct {
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Product ProductService
+}
+
+func NewSEOService(repo repository.MainRepository, logger *zap.Logger, product ProductService) SEOService {
+	return SEOService{Repo: repo, Logger: logger, Product: product}
+}
+
+// GetProductBySlug returns the product holding the slug. When the slug is a
+// former one, no product is returned and redirect holds the current slug.
+func (s SEOService) GetProductBySlug(slug string) (product *model.Product, redirect string, err error) {
+	id, err := s.Repo.SlugRepository.GetEntityID(model.SlugEntityProduct, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	if id != 0 {
+		product, err = s.Product.GetProductByID(id)
+		return product, "", err
+	}
+
+	id, err = s.Repo.SlugRepository.GetRedirect(model.SlugEntityProduct, slug)
+	if err != nil {
+		return nil, "", err
+	}
+	seo, err := s.Repo.SlugRepository.GetSEO(model.SlugEntityProduct, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if seo.EntityID == 0 {
+		return nil, "", fmt.Errorf("%w: %s", ErrProductNotFound, slug)
+	}
+	return nil, seo.Slug, nil
+}
+
+func (s SEOService) UpdateProductSEO(id int, seoInput model.SEODTO) (model.SEO, error) {
+	return s.updateSEO(model.SlugEntityProduct, id, seoInput)
+}
+
+func (s SEOService) UpdateCategorySEO(id int, seoInput model.SEODTO) (model.SEO, error) {
+	return s.updateSEO(model.SlugEntityCategory, id, seoInput)
+}
+
+// updateSEO renames a record. Without an explicit slug, a new name gets a
+// new generated slug and the old one redirects to it.
+func (s SEOService) updateSEO(entityType string, id int, seoInput model.SEODTO) (model.SEO, error) {
+	current, err := s.Repo.SlugRepository.GetSEO(entityType, id)
+	if err != nil {
+		return current, err
+	}
+	if current.EntityID == 0 {
+		if entityType == model.SlugEntityCategory {
+			return current, fmt.Errorf("%w: %d", ErrCategoryNotFound, id)
+		}
+		return current, fmt.Errorf("%w: %d", ErrProductNotFound, id)
+	}
+
+	seo := model.SEO{
+		EntityType:      entityType,
+		EntityID:        id,
+		Name:            strings.TrimSpace(seoInput.Name),
+		Slug:            helper.Slugify(seoInput.Slug),
+		MetaTitle:       strings.TrimSpace(seoInput.MetaTitle),
+		MetaDescription: strings.TrimSpace(seoInput.MetaDescription),
+	}
+	if seo.Name == "" {
+		seo.Name = current.Name
+	}
+	if utf8.RuneCountInString(seo.MetaTitle) > maxMetaTitleLength {
+		return seo, fmt.Errorf("%w: meta title is longer than %d characters", ErrSEOInvalid, maxMetaTitleLength)
+	}
+
+	switch {
+	case seo.Slug != "":
+		taken, err := s.slugTaken(entityType, id, seo.Slug)
+		if err != nil {
+			return seo, err
+		}
+		if taken {
+			return seo, fmt.Errorf("%w: slug %s is already used", ErrSEOInvalid, seo.Slug)
+		}
+	case seo.Name != current.Name:
+		seo.Slug, err = s.uniqueSlug(entityType, id, seo.Name)
+		if err != nil {
+			return seo, err
+		}
+	default:
+		seo.Slug = current.Slug
+	}
+
+	if err := s.Repo.SlugRepository.Save(seo, current.Slug); err != nil {
+		return seo, err
+	}
+	return seo, nil
+}
+
+// uniqueSlug slugifies name, appending -2, -3, ... until no other record
+// holds it or redirects from it.
+func (s SEOService) uniqueSlug(entityType string, id int, name string) (string, error) {
+	base := helper.Slugify(name)
+	if base == "" {
+		base = fmt.Sprintf("%s-%d", entityType, id)
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		taken, err := s.slugTaken(entityType, id, slug)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// slugTaken reports whether another record holds the slug or keeps it as a
+// redirect. A record may take back one of its own former slugs.
+func (s SEOService) slugTaken(entityType string, id int, slug string) (bool, error) {
+	owner, err := s.Repo.SlugRepository.GetEntityID(entityType, slug)
+	if err != nil {
+		s.Logger.Error("error get slug", zap.Error(err), zap.String("service", "SEO"), zap.String("function", "slugTaken"))
+		return false, err
+	}
+	if owner != 0 && owner != id {
+		return true, nil
+	}
+
+	redirect, err := s.Repo.SlugRepository.GetRedirect(entityType, slug)
+	if err != nil {
+		s.Logger.Error("error get slug redirect", zap.Error(err), zap.String("service", "SEO"), zap.String("function", "slugTaken"))
+		return false, err
+	}
+	return redirect != 0 && redirect != id, nil
+}
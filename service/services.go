@@ -1,7 +1,10 @@
 package service
 
 import (
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/job"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/notify"
 	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
 	"go.uber.org/zap"
 )
 
@@ -14,17 +17,63 @@ type MainService struct {
 	WishlistService       WishlistService
 	CartService           CartService
 	OrderService          OrderService
+	ReviewService         ReviewService
+	PricingService        PricingService
+	PromotionService      PromotionService
+	CouponService         CouponService
+	FlashSaleService      FlashSaleService
+	CartPromotionService  CartPromotionService
+	ProductViewService    ProductViewService
+	BestSellerService     BestSellerService
+	PriceHistoryService   PriceHistoryService
+	CurrencyService       CurrencyService
+	TranslationService    TranslationService
+	AttributeService      AttributeService
+	BrandService          BrandService
+	CollectionService     CollectionService
+	SEOService            SEOService
+	ComparisonService     ComparisonService
+	QuestionService       QuestionService
+	LifecycleService      LifecycleService
+	AbandonedCartService  AbandonedCartService
 }
 
-func NewMainService(repo repository.MainRepository, log *zap.Logger) MainService {
+func NewMainService(repo repository.MainRepository, log *zap.Logger, config util.Configuration) MainService {
+	pricing := NewPricingService(repo, log)
+	currency := NewCurrencyService(repo, log)
+	coupon := NewCouponService(repo, log, pricing)
+	attribute := NewAttributeService(repo, log)
+	product := NewProductService(repo, log, pricing, attribute)
+	translation := NewTranslationService(repo, log, config.Locale)
+	cart := NewCartService(repo, log, pricing, coupon)
+	priceChanges := job.NewTrigger()
 	return MainService{
 		AddressService:        NewAddressService(repo, log),
 		CategoryService:       NewCategoryService(repo, log),
-		ProductService:        NewProductService(repo, log),
-		RecommendationService: NewRecommendationService(repo, log),
+		ProductService:        product,
+		RecommendationService: NewRecommendationService(repo, log, pricing, config.Recommendation),
 		UserService:           NewUserService(repo, log),
-		WishlistService:       NewWishlistService(repo, log),
-		CartService:           NewCartService(repo, log),
-		OrderService:          NewOrderService(repo, log),
+		WishlistService:       NewWishlistService(repo, log, pricing),
+		CartService:           cart,
+		OrderService:          NewOrderService(repo, log, pricing, coupon, currency, cart),
+		ReviewService:         NewReviewService(repo, log, config.Review),
+		PricingService:        pricing,
+		PromotionService:      NewPromotionService(repo, log, priceChanges),
+		CouponService:         coupon,
+		FlashSaleService:      NewFlashSaleService(repo, log, priceChanges),
+		CartPromotionService:  NewCartPromotionService(repo, log),
+		ProductViewService:    NewProductViewService(repo, log, pricing, config.Recommendation),
+		BestSellerService:     NewBestSellerService(repo, log, pricing, config.BestSeller),
+		PriceHistoryService:   NewPriceHistoryService(repo, log, pricing, priceChanges),
+		CurrencyService:       currency,
+		TranslationService:    translation,
+		AttributeService:      attribute,
+		BrandService:          NewBrandService(repo, log, product),
+		CollectionService:     NewCollectionService(repo, log, product),
+		SEOService:            NewSEOService(repo, log, product),
+		ComparisonService:     NewComparisonService(repo, log, product, currency, translation),
+		QuestionService:       NewQuestionService(repo, log, config.Review),
+		LifecycleService:      NewLifecycleService(repo, log),
+		AbandonedCartService:  NewAbandonedCartService(repo, log, notify.New(config.Notification, log), config.AbandonedCart),
 	}
 }
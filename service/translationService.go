@@ -0,0 +1,474 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/model"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/repository"
+	"github.com/Safiramdhn/project-app-ecommerce-golang-safira/util"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrTranslationInvalid = errors.New("invalid translation")
+	ErrCategoryNotFound   = errors.New("category not found")
+)
+
+type TranslationService struct {
+	Repo   repository.MainRepository
+	Logger *zap.Logger
+	Config util.LocaleConfig
+}
+
+func NewTranslationService(repo repository.MainRepository, logger *zap.Logger, config util.LocaleConfig) TranslationService {
+	return TranslationService{Repo: repo, Logger: logger, Config: config}
+}
+
+// Negotiate picks the content locale of a request. An explicit locale wins;
+// otherwise the Accept-Language header is tried in order of preference.
+// A region falls back to its language ("id-ID" to "id"), and anything not
+// supported falls back to the default locale.
+func (s TranslationService) Negotiate(locale, acceptLanguage string) string {
+	if supported, ok := s.supported(locale); ok {
+		return supported
+	}
+
+	type preference struct {
+		tag     string
+		quality float64
+	}
+	var preferences []preference
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+		if tag != "" && tag != "*" && quality > 0 {
+			preferences = append(preferences, preference{tag: tag, quality: quality})
+		}
+	}
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+	for _, preference := range preferences {
+		if supported, ok := s.supported(preference.tag); ok {
+			return supported
+		}
+	}
+	return s.defaultLocale()
+}
+
+func (s TranslationService) supported(locale string) (string, bool) {
+	locale = normaliseLocale(locale)
+	if locale == "" {
+		return "", false
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	for _, candidate := range []string{locale, language} {
+		if candidate == s.defaultLocale() {
+			return candidate, true
+		}
+		for _, supported := range s.Config.Supported {
+			if candidate == normaliseLocale(supported) {
+				return candidate, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s TranslationService) defaultLocale() string {
+	if s.Config.Default == "" {
+		return "en"
+	}
+	return normaliseLocale(s.Config.Default)
+}
+
+func normaliseLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// TranslateProducts swaps the text of the products, their categories and
+// variants for the locale. Text without a translation is left as is. A
+// failed lookup is logged and leaves the default text, so it never fails
+// the response.
+func (s TranslationService) TranslateProducts(locale string, products ...*model.Product) {
+	if locale == s.defaultLocale() || len(products) == 0 {
+		return
+	}
+
+	var productIDs, categoryIDs, variantIDs, optionIDs []int
+	for _, product := range products {
+		productIDs = append(productIDs, product.ID)
+		if product.Category.ID != 0 {
+			categoryIDs = append(categoryIDs, product.Category.ID)
+		}
+		for _, variant := range product.Variant {
+			variantIDs = append(variantIDs, variant.ID)
+			for _, option := range variant.VariantOption {
+				optionIDs = append(optionIDs, option.ID)
+			}
+		}
+	}
+
+	productText := s.lookup(model.TranslationEntityProduct, productIDs, locale)
+	categoryText := s.lookup(model.TranslationEntityCategory, categoryIDs, locale)
+	variantText := s.lookup(model.TranslationEntityVariant, variantIDs, locale)
+	optionText := s.lookup(model.TranslationEntityVariantOption, optionIDs, locale)
+	for _, product := range products {
+		translate(&product.Name, productText, product.ID, model.TranslationFieldName)
+		translate(&product.Description, productText, product.ID, model.TranslationFieldDescription)
+		translate(&product.Category.Name, categoryText, product.Category.ID, model.TranslationFieldName)
+		for j := range product.Variant {
+			variant := &product.Variant[j]
+			translate(&variant.AttributeName, variantText, variant.ID, model.TranslationFieldAttributeName)
+			for k := range variant.VariantOption {
+				option := &variant.VariantOption[k]
+				translate(&option.OptionValue, optionText, option.ID, model.TranslationFieldOptionValue)
+			}
+		}
+	}
+}
+
+// TranslateProductList translates a plain list of products.
+func (s TranslationService) TranslateProductList(locale string, products []model.Product) {
+	refs := make([]*model.Product, len(products))
+	for i := range products {
+		refs[i] = &products[i]
+	}
+	s.TranslateProducts(locale, refs...)
+}
+
+func (s TranslationService) TranslateCategories(locale string, categories []model.Category) {
+	if locale == s.defaultLocale() || len(categories) == 0 {
+		return
+	}
+	var ids []int
+	for _, category := range categories {
+		ids = append(ids, category.ID)
+	}
+	text := s.lookup(model.TranslationEntityCategory, ids, locale)
+	for i := range categories {
+		translate(&categories[i].Name, text, categories[i].ID, model.TranslationFieldName)
+	}
+}
+
+// TranslateRecommendations translates the banner text and the products of
+// the recommendations.
+func (s TranslationService) TranslateRecommendations(locale string, recommendations []model.Recommendation) {
+	if locale == s.defaultLocale() || len(recommendations) == 0 {
+		return
+	}
+	var ids []int
+	products := make([]*model.Product, len(recommendations))
+	for i := range recommendations {
+		ids = append(ids, recommendations[i].ID)
+		products[i] = &recommendations[i].Product
+	}
+	text := s.lookup(model.TranslationEntityRecommendation, ids, locale)
+	s.TranslateProducts(locale, products...)
+	for i := range recommendations {
+		recommendation := &recommendations[i]
+		translate(&recommendation.Title, text, recommendation.ID, model.TranslationFieldTitle)
+		translate(&recommendation.Subtitle, text, recommendation.ID, model.TranslationFieldSubtitle)
+	}
+}
+
+// TranslateCart translates the products of the cart lines and of the lines
+// saved for later.
+func (s TranslationService) TranslateCart(locale string, cart *model.Cart) {
+	products := make([]*model.Product, 0, len(cart.Items)+len(cart.SavedItems))
+	for i := range cart.Items {
+		products = append(products, &cart.Items[i].Product)
+	}
+	for i := range cart.SavedItems {
+		products = append(products, &cart.SavedItems[i].Product)
+	}
+	s.TranslateProducts(locale, products...)
+}
+
+// lookup returns the translations of the entities keyed by entity and field.
+func (s TranslationService) lookup(entityType string, ids []int, locale string) map[int]map[string]string {
+	translations, err := s.Repo.TranslationRepository.GetByEntities(entityType, ids, locale)
+	if err != nil {
+		s.Logger.Error("error get translations", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "lookup"), zap.String("entity", entityType))
+		return nil
+	}
+	text := make(map[int]map[string]string)
+	for _, translation := range translations {
+		if text[translation.EntityID] == nil {
+			text[translation.EntityID] = make(map[string]string)
+		}
+		text[translation.EntityID][translation.Field] = translation.Value
+	}
+	return text
+}
+
+func translate(target *string, text map[int]map[string]string, id int, field string) {
+	if value, ok := text[id][field]; ok {
+		*target = value
+	}
+}
+
+// GetProductTranslations returns a product's translations in every locale,
+// with its variants.
+func (s TranslationService) GetProductTranslations(productID int) ([]model.ProductTranslation, error) {
+	variants, err := s.productVariants(productID)
+	if err != nil {
+		return nil, err
+	}
+	variantIDs, optionIDs := variantEntityIDs(variants)
+
+	var translations []model.Translation
+	for entityType, ids := range map[string][]int{
+		model.TranslationEntityProduct:       {productID},
+		model.TranslationEntityVariant:       variantIDs,
+		model.TranslationEntityVariantOption: optionIDs,
+	} {
+		entries, err := s.Repo.TranslationRepository.GetByEntities(entityType, ids, "")
+		if err != nil {
+			s.Logger.Error("error get translations", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "GetProductTranslations"))
+			return nil, err
+		}
+		translations = append(translations, entries...)
+	}
+
+	var locales []string
+	text := make(map[string]map[string]map[int]map[string]string)
+	for _, translation := range translations {
+		if text[translation.Locale] == nil {
+			locales = append(locales, translation.Locale)
+			text[translation.Locale] = make(map[string]map[int]map[string]string)
+		}
+		byEntity := text[translation.Locale]
+		if byEntity[translation.EntityType] == nil {
+			byEntity[translation.EntityType] = make(map[int]map[string]string)
+		}
+		if byEntity[translation.EntityType][translation.EntityID] == nil {
+			byEntity[translation.EntityType][translation.EntityID] = make(map[string]string)
+		}
+		byEntity[translation.EntityType][translation.EntityID][translation.Field] = translation.Value
+	}
+	sort.Strings(locales)
+
+	productTranslations := []model.ProductTranslation{}
+	for _, locale := range locales {
+		byEntity := text[locale]
+		productTranslation := model.ProductTranslation{
+			Locale:      locale,
+			Name:        byEntity[model.TranslationEntityProduct][productID][model.TranslationFieldName],
+			Description: byEntity[model.TranslationEntityProduct][productID][model.TranslationFieldDescription],
+		}
+		for _, variant := range variants {
+			variantTranslation := model.VariantTranslation{
+				ID:            variant.ID,
+				AttributeName: byEntity[model.TranslationEntityVariant][variant.ID][model.TranslationFieldAttributeName],
+			}
+			for _, option := range variant.VariantOption {
+				optionValue := byEntity[model.TranslationEntityVariantOption][option.ID][model.TranslationFieldOptionValue]
+				if optionValue != "" {
+					variantTranslation.Options = append(variantTranslation.Options, model.VariantOptionTranslation{ID: option.ID, OptionValue: optionValue})
+				}
+			}
+			if variantTranslation.AttributeName != "" || len(variantTranslation.Options) > 0 {
+				productTranslation.Variants = append(productTranslation.Variants, variantTranslation)
+			}
+		}
+		productTranslations = append(productTranslations, productTranslation)
+	}
+	return productTranslations, nil
+}
+
+// SaveProductTranslation replaces a product's translation in one locale,
+// including its variants. Variants and options left out lose their
+// translation in that locale.
+func (s TranslationService) SaveProductTranslation(productID int, locale string, translationInput model.ProductTranslation) (model.ProductTranslation, error) {
+	locale, err := s.validateLocale(locale)
+	if err != nil {
+		return translationInput, err
+	}
+	translationInput.Locale = locale
+	variants, err := s.productVariants(productID)
+	if err != nil {
+		return translationInput, err
+	}
+
+	options := make(map[int]int)
+	for _, variant := range variants {
+		for _, option := range variant.VariantOption {
+			options[option.ID] = variant.ID
+		}
+	}
+	var translations []model.Translation
+	translations = appendTranslation(translations, model.TranslationEntityProduct, productID, model.TranslationFieldName, translationInput.Name)
+	translations = appendTranslation(translations, model.TranslationEntityProduct, productID, model.TranslationFieldDescription, translationInput.Description)
+	for _, variantInput := range translationInput.Variants {
+		if !hasVariant(variants, variantInput.ID) {
+			return translationInput, fmt.Errorf("%w: variant %d does not belong to product %d", ErrTranslationInvalid, variantInput.ID, productID)
+		}
+		translations = appendTranslation(translations, model.TranslationEntityVariant, variantInput.ID, model.TranslationFieldAttributeName, variantInput.AttributeName)
+		for _, optionInput := range variantInput.Options {
+			if options[optionInput.ID] != variantInput.ID {
+				return translationInput, fmt.Errorf("%w: option %d does not belong to variant %d", ErrTranslationInvalid, optionInput.ID, variantInput.ID)
+			}
+			translations = appendTranslation(translations, model.TranslationEntityVariantOption, optionInput.ID, model.TranslationFieldOptionValue, optionInput.OptionValue)
+		}
+	}
+
+	variantIDs, optionIDs := variantEntityIDs(variants)
+	err = s.Repo.TranslationRepository.Replace(locale, map[string][]int{
+		model.TranslationEntityProduct:       {productID},
+		model.TranslationEntityVariant:       variantIDs,
+		model.TranslationEntityVariantOption: optionIDs,
+	}, translations)
+	if err != nil {
+		s.Logger.Error("error save product translation", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "SaveProductTranslation"))
+		return translationInput, err
+	}
+	return translationInput, nil
+}
+
+func (s TranslationService) DeleteProductTranslation(productID int, locale string) error {
+	locale, err := s.validateLocale(locale)
+	if err != nil {
+		return err
+	}
+	variants, err := s.productVariants(productID)
+	if err != nil {
+		return err
+	}
+	variantIDs, optionIDs := variantEntityIDs(variants)
+	return s.Repo.TranslationRepository.Replace(locale, map[string][]int{
+		model.TranslationEntityProduct:       {productID},
+		model.TranslationEntityVariant:       variantIDs,
+		model.TranslationEntityVariantOption: optionIDs,
+	}, nil)
+}
+
+// SaveCategoryTranslation replaces a category's name in one locale. An
+// empty name removes the translation.
+func (s TranslationService) SaveCategoryTranslation(categoryID int, locale string, translationInput model.ContentTranslation) (model.ContentTranslation, error) {
+	locale, err := s.validateLocale(locale)
+	if err != nil {
+		return translationInput, err
+	}
+	category, err := s.Repo.CategoryRepository.GetByID(categoryID)
+	if err != nil {
+		s.Logger.Error("error get category", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "SaveCategoryTranslation"))
+		return translationInput, err
+	}
+	if category.ID == 0 {
+		return translationInput, fmt.Errorf("%w: %d", ErrCategoryNotFound, categoryID)
+	}
+
+	translationInput = model.ContentTranslation{Locale: locale, Name: strings.TrimSpace(translationInput.Name)}
+	translations := appendTranslation(nil, model.TranslationEntityCategory, categoryID, model.TranslationFieldName, translationInput.Name)
+	err = s.Repo.TranslationRepository.Replace(locale, map[string][]int{model.TranslationEntityCategory: {categoryID}}, translations)
+	if err != nil {
+		s.Logger.Error("error save category translation", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "SaveCategoryTranslation"))
+		return translationInput, err
+	}
+	return translationInput, nil
+}
+
+// SaveRecommendationTranslation replaces a banner's title and subtitle in
+// one locale. Empty fields fall back to the default text.
+func (s TranslationService) SaveRecommendationTranslation(recommendationID int, locale string, translationInput model.ContentTranslation) (model.ContentTranslation, error) {
+	locale, err := s.validateLocale(locale)
+	if err != nil {
+		return translationInput, err
+	}
+	recommendation, err := s.Repo.RecommendationRepository.GetByID(recommendationID)
+	if err != nil {
+		s.Logger.Error("error get recommendation", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "SaveRecommendationTranslation"))
+		return translationInput, err
+	}
+	if recommendation.ID == 0 {
+		return translationInput, fmt.Errorf("%w: %d", ErrRecommendationNotFound, recommendationID)
+	}
+
+	translationInput = model.ContentTranslation{
+		Locale:   locale,
+		Title:    strings.TrimSpace(translationInput.Title),
+		Subtitle: strings.TrimSpace(translationInput.Subtitle),
+	}
+	var translations []model.Translation
+	translations = appendTranslation(translations, model.TranslationEntityRecommendation, recommendationID, model.TranslationFieldTitle, translationInput.Title)
+	translations = appendTranslation(translations, model.TranslationEntityRecommendation, recommendationID, model.TranslationFieldSubtitle, translationInput.Subtitle)
+	err = s.Repo.TranslationRepository.Replace(locale, map[string][]int{model.TranslationEntityRecommendation: {recommendationID}}, translations)
+	if err != nil {
+		s.Logger.Error("error save recommendation translation", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "SaveRecommendationTranslation"))
+		return translationInput, err
+	}
+	return translationInput, nil
+}
+
+// validateLocale only accepts supported locales other than the default,
+// whose text lives on the records themselves.
+func (s TranslationService) validateLocale(locale string) (string, error) {
+	locale = normaliseLocale(locale)
+	if locale == s.defaultLocale() {
+		return locale, fmt.Errorf("%w: %s is the default locale, edit the record itself", ErrTranslationInvalid, locale)
+	}
+	for _, supported := range s.Config.Supported {
+		if locale == normaliseLocale(supported) {
+			return locale, nil
+		}
+	}
+	return locale, fmt.Errorf("%w: locale %q is not supported", ErrTranslationInvalid, locale)
+}
+
+func (s TranslationService) productVariants(productID int) ([]model.Variant, error) {
+	product, err := s.Repo.ProductRepository.GetByID(productID)
+	if err != nil {
+		s.Logger.Error("error get product", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "productVariants"))
+		return nil, err
+	}
+	if product.ID == 0 {
+		return nil, fmt.Errorf("%w: %d", ErrProductNotFound, productID)
+	}
+	if !product.HasVariant {
+		return nil, nil
+	}
+	variants, err := s.Repo.VariantRepository.GetByProductId(product.ID)
+	if err != nil {
+		s.Logger.Error("error get variants", zap.Error(err), zap.String("service", "Translation"), zap.String("function", "productVariants"))
+		return nil, err
+	}
+	return variants, nil
+}
+
+func variantEntityIDs(variants []model.Variant) ([]int, []int) {
+	var variantIDs, optionIDs []int
+	for _, variant := range variants {
+		variantIDs = append(variantIDs, variant.ID)
+		for _, option := range variant.VariantOption {
+			optionIDs = append(optionIDs, option.ID)
+		}
+	}
+	return variantIDs, optionIDs
+}
+
+func hasVariant(variants []model.Variant, id int) bool {
+	for _, variant := range variants {
+		if variant.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func appendTranslation(translations []model.Translation, entityType string, id int, field, value string) []model.Translation {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return translations
+	}
+	return append(translations, model.Translation{EntityType: entityType, EntityID: id, Field: field, Value: value})
+}
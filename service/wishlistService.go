@@ -7,12 +7,13 @@ import (
 )
 
 type WishlistService struct {
-	Repo   repository.MainRepository
-	Logger *zap.Logger
+	Repo    repository.MainRepository
+	Logger  *zap.Logger
+	Pricing PricingService
 }
 
-func NewWishlistService(repo repository.MainRepository, log *zap.Logger) WishlistService {
-	return WishlistService{Repo: repo, Logger: log}
+func NewWishlistService(repo repository.MainRepository, log *zap.Logger, pricing PricingService) WishlistService {
+	return WishlistService{Repo: repo, Logger: log, Pricing: pricing}
 }
 
 func (s *WishlistService) AddProductToWishlist(wishlistInput model.WishlistDTO) error {
@@ -36,14 +37,22 @@ func (s *WishlistService) GetWishlistByUserId(userId string, paginationInput mod
 		return nil, paginationInput, err
 	}
 
+	var products []model.Product
 	for _, item := range wishlist {
 		product, err := s.Repo.ProductRepository.GetByID(item.ProductID)
 		if err != nil {
 			s.Logger.Error("Error getting product", zap.Error(err))
 			continue
 		}
-		item.Product = product
 		newWishlist = append(newWishlist, item)
+		products = append(products, product)
+	}
+
+	if err := s.Pricing.PriceProducts(products); err != nil {
+		return nil, pagination, err
+	}
+	for i := range newWishlist {
+		newWishlist[i].Product = products[i]
 	}
 	return newWishlist, pagination, nil
 }
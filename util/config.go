@@ -1,17 +1,27 @@
 package util
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // Configuration holds the application configuration
 type Configuration struct {
-	AppName string    `mapstructure:"app_name"`
-	Port    string    `mapstructure:"port"`
-	Debug   bool      `mapstructure:"debug"`
-	Jwtkey  string    `mapstructure:"jwtkey"`
-	DB      DbConfig  `mapstructure:"db"`
-	Dir     DirConfig `mapstructure:"dir"`
+	AppName        string               `mapstructure:"app_name"`
+	Port           string               `mapstructure:"port"`
+	Debug          bool                 `mapstructure:"debug"`
+	Jwtkey         string               `mapstructure:"jwtkey"`
+	DB             DbConfig             `mapstructure:"db"`
+	Dir            DirConfig            `mapstructure:"dir"`
+	Review         ReviewConfig         `mapstructure:"review"`
+	Recommendation RecommendationConfig `mapstructure:"recommendation"`
+	BestSeller     BestSellerConfig     `mapstructure:"best_seller"`
+	PriceHistory   PriceHistoryConfig   `mapstructure:"price_history"`
+	Product        ProductConfig        `mapstructure:"product"`
+	Locale         LocaleConfig         `mapstructure:"locale"`
+	AbandonedCart  AbandonedCartConfig  `mapstructure:"abandoned_cart"`
+	Notification   NotificationConfig   `mapstructure:"notification"`
 }
 
 // DbConfig holds the database configuration
@@ -27,6 +37,67 @@ type DirConfig struct {
 	Logs    string `mapstructure:"logs"`
 }
 
+// ReviewConfig holds the review moderation settings
+type ReviewConfig struct {
+	BannedWords     string `mapstructure:"banned_words"`
+	ReportThreshold int    `mapstructure:"report_threshold"`
+}
+
+// RecommendationConfig holds the settings of the recommendation jobs
+type RecommendationConfig struct {
+	RefreshInterval     time.Duration `mapstructure:"refresh_interval"`
+	MinSupport          int           `mapstructure:"min_support"`
+	RecentlyViewedLimit int           `mapstructure:"recently_viewed_limit"`
+	TrendingWindow      time.Duration `mapstructure:"trending_window"`
+}
+
+// BestSellerConfig holds the settings of the best-seller ranking job.
+// Products ranked within FlagTopN over FlagWindow days are flagged as best
+// selling.
+type BestSellerConfig struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	Windows         []int         `mapstructure:"windows"`
+	FlagWindow      int           `mapstructure:"flag_window"`
+	FlagTopN        int           `mapstructure:"flag_top_n"`
+}
+
+// PriceHistoryConfig holds the settings of the price snapshot job
+type PriceHistoryConfig struct {
+	SnapshotInterval time.Duration `mapstructure:"snapshot_interval"`
+}
+
+// ProductConfig holds the settings of the scheduled publishing job
+type ProductConfig struct {
+	PublishInterval time.Duration `mapstructure:"publish_interval"`
+}
+
+// LocaleConfig holds the content locales. Text stored on the records
+// themselves is in the Default locale; Supported lists every locale
+// translations can be given and requested in.
+type LocaleConfig struct {
+	Default   string   `mapstructure:"default"`
+	Supported []string `mapstructure:"supported"`
+}
+
+// AbandonedCartConfig holds the settings of the abandoned cart job. Carts
+// untouched for IdleAfter get a reminder, with a CouponPercent coupon valid
+// for CouponValidFor when both are set. Carts untouched for ExpireAfter
+// expire. A zero duration turns that step off.
+type AbandonedCartConfig struct {
+	CheckInterval  time.Duration `mapstructure:"check_interval"`
+	IdleAfter      time.Duration `mapstructure:"idle_after"`
+	ExpireAfter    time.Duration `mapstructure:"expire_after"`
+	CouponPercent  float64       `mapstructure:"coupon_percent"`
+	CouponValidFor time.Duration `mapstructure:"coupon_valid_for"`
+}
+
+// NotificationConfig picks how notifications reach users. The file driver
+// appends them to File.
+type NotificationConfig struct {
+	Driver string `mapstructure:"driver"`
+	File   string `mapstructure:"file"`
+}
+
 // InitConfig initializes and reads configuration using Viper
 func InitConfig() (Configuration, error) {
 	// Set the file name and type for the .env file
@@ -45,6 +116,27 @@ func InitConfig() (Configuration, error) {
 	viper.SetDefault("db.password", "postgres")
 	viper.SetDefault("dir.uploads", "./uploads")
 	viper.SetDefault("dir.logs", "./logs")
+	viper.SetDefault("review.banned_words", "")
+	viper.SetDefault("review.report_threshold", 3)
+	viper.SetDefault("recommendation.refresh_interval", "1h")
+	viper.SetDefault("recommendation.min_support", 2)
+	viper.SetDefault("recommendation.recently_viewed_limit", 20)
+	viper.SetDefault("recommendation.trending_window", "168h")
+	viper.SetDefault("best_seller.refresh_interval", "1h")
+	viper.SetDefault("best_seller.windows", []int{7, 30, 90})
+	viper.SetDefault("best_seller.flag_window", 30)
+	viper.SetDefault("best_seller.flag_top_n", 10)
+	viper.SetDefault("price_history.snapshot_interval", "15m")
+	viper.SetDefault("product.publish_interval", "1m")
+	viper.SetDefault("locale.default", "en")
+	viper.SetDefault("locale.supported", []string{"en"})
+	viper.SetDefault("abandoned_cart.check_interval", "15m")
+	viper.SetDefault("abandoned_cart.idle_after", "24h")
+	viper.SetDefault("abandoned_cart.expire_after", "720h")
+	viper.SetDefault("abandoned_cart.coupon_percent", 0)
+	viper.SetDefault("abandoned_cart.coupon_valid_for", "72h")
+	viper.SetDefault("notification.driver", "file")
+	viper.SetDefault("notification.file", "./logs/notifications.log")
 
 	// Read the .env file if it exists
 	err := viper.ReadInConfig()
@@ -9,9 +9,10 @@ import (
 
 var secretKey = []byte("ec0mM3RceAPP")
 
-func GenerateToken(userId string, config Configuration) (string, error) {
+func GenerateToken(userId, role string, config Configuration) (string, error) {
 	claim := jwt.MapClaims{
 		"userId": userId,
+		"role":   role,
 		"exp":    time.Now().Add(time.Hour * 12).Unix(),
 	}
 